@@ -0,0 +1,37 @@
+// Command gots-trace renders a decision trace written by
+// strategy.JSONTraceSink for a range of bars.
+//
+//	gots-trace -from 120 -to 140 trace.jsonl
+//
+// With no file argument the trace is read from standard input.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/evdnx/gots/strategy"
+)
+
+func main() {
+	from := flag.Int64("from", 0, "first bar to render")
+	to := flag.Int64("to", 0, "last bar to render (0 = until the end)")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := strategy.RenderDecisions(os.Stdout, in, *from, *to); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
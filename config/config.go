@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // StrategyConfig holds all tunable parameters for a strategy.
@@ -26,6 +27,63 @@ type StrategyConfig struct {
 	TakeProfitPct   float64 // e.g. 0.03  = 3 %
 	TrailingPct     float64 // optional, 0 = disabled
 
+	// IndicatorOverrides sets goti indicator parameters that have no field
+	// above, keyed by their goti.IndicatorConfig name (e.g. "MFIVolumeScale",
+	// "AMDOScaling").  Overrides are applied after the field mapping.
+	IndicatorOverrides map[string]float64
+
+	// SignalFallback selects the heuristic fallback policy (see the
+	// FallbackPolicy constants).  Empty means FallbackAlways.
+	SignalFallback FallbackPolicy
+
+	// StopType selects how BaseStrategy places the hard stop it enforces on
+	// every position (see the StopType constants).  Empty means StopPct.
+	StopType StopType
+	// StopLossAbs is the stop distance in price units for StopAbs.
+	StopLossAbs float64
+
+	// TrailingATRMult enables a chandelier trailing stop placed this many
+	// ATRs behind the best price since entry (0 = disabled).  When it is set
+	// together with TrailingPct the tighter of the two levels applies.
+	TrailingATRMult float64
+	// TrailingActivationPct arms the trailing stop only after the best price
+	// has moved this fraction in the position's favour (0 = armed at entry).
+	TrailingActivationPct float64
+
+	// ---- Time‑based exits (0 / empty = disabled) ------------------------------
+	// MaxHoldingBars closes a position after it has been held this many bars.
+	MaxHoldingBars int
+	// MaxHoldingDuration closes a position once it has been open this long
+	// according to the strategy clock.
+	MaxHoldingDuration time.Duration
+	// SessionEnd ("HH:MM", in the strategy clock's location) flattens every
+	// position on the first bar at or after that time of day; no new
+	// positions are opened until the next day.
+	SessionEnd string
+	// ExitUnprofitableBars closes a position that is not in profit after
+	// this many bars.
+	ExitUnprofitableBars int
+
+	// ---- Scaling (0 = disabled) -------------------------------------------
+	// R is the per‑unit distance from the entry to the hard stop.
+	// ScaleOutR takes ScaleOutFraction of the position off once price has
+	// moved ScaleOutR × R in its favour; the remainder is left to the
+	// trailing stop and the strategy's own exits.
+	ScaleOutR        float64
+	ScaleOutFraction float64
+	// PyramidMaxAdds adds to a winner every PyramidStepR × R of further
+	// progress, each add PyramidScale (default 0.5) times the previous one.
+	PyramidMaxAdds int
+	PyramidStepR   float64
+	PyramidScale   float64
+	// AverageDownMaxAdds adds to a loser every AverageDownStepR × R against
+	// the position, with the same decreasing size as pyramiding.
+	AverageDownMaxAdds int
+	AverageDownStepR   float64
+	// MaxPositionRisk caps the open risk of a scaled position (qty × stop
+	// distance) as a fraction of equity (default 2 × MaxRiskPerTrade).
+	MaxPositionRisk float64
+
 	// ---- NEW PRODUCTION SETTINGS -------------------------------------------------
 	// QuantityPrecision defines the number of decimal places to round to
 	// (e.g. 2 for crypto/futures, 0 for equities).
@@ -36,8 +94,102 @@ type StrategyConfig struct {
 
 	// StepSize – the increment allowed by the exchange (e.g. 0.0001).
 	StepSize float64
+
+	// PositionMode restricts the sides a strategy may hold (e.g. long‑only on
+	// spot venues).  The zero value allows both.
+	PositionMode PositionMode
+
+	// ---- POSITION SIZING ---------------------------------------------------------
+	// Sizing selects the position sizer used by BaseStrategy (see risk.NewSizer).
+	// The zero value keeps the fixed‑fractional behaviour of risk.CalcQty.
+	Sizing SizingMethod
+
+	// FixedNotional is the quote‑currency amount per trade for SizingFixedNotional.
+	FixedNotional float64
+
+	// TargetVol is the annualised volatility target for SizingVolTarget
+	// (e.g. 0.20 = 20 %).
+	TargetVol float64
+
+	// BarsPerYear annualises per‑bar volatility for SizingVolTarget
+	// (default 252 = daily bars).
+	BarsPerYear float64
+
+	// ATRStopMultiple is the stop distance in ATR units for SizingATRStop
+	// (default 2).
+	ATRStopMultiple float64
+
+	// KellyFraction scales the Kelly / optimal‑f bet (1 = full Kelly,
+	// default 0.5 = half Kelly).
+	KellyFraction float64
+
+	// KellyMinTrades is the number of closed trades required before the
+	// Kelly / optimal‑f estimate replaces fixed‑fractional sizing (default
+	// DefaultKellyMinTrades).
+	KellyMinTrades int
+
+	// KellyMaxRisk caps the fraction of equity a Kelly / optimal‑f sizer may
+	// risk on one trade (default 0.05).
+	KellyMaxRisk float64
+
+	// KellyLookback is the rolling window of closed trades used for the
+	// estimate (default DefaultKellyLookback).
+	KellyLookback int
 }
 
+// Defaults of the Kelly / optimal‑f settings left at zero.
+const (
+	DefaultKellyMinTrades = 20  // closed trades before the estimate is trusted
+	DefaultKellyLookback  = 100 // rolling window of closed trades
+)
+
+// FallbackPolicy controls when strategies may substitute price‑buffer
+// heuristics for indicator signals.
+type FallbackPolicy string
+
+const (
+	FallbackAlways   FallbackPolicy = "always"   // OR heuristics into every signal (default)
+	FallbackWarmup   FallbackPolicy = "warmup"   // use heuristics only while an indicator is warming up
+	FallbackDisabled FallbackPolicy = "disabled" // indicator signals only
+)
+
+// StopType names a hard‑stop placement rule.
+type StopType string
+
+const (
+	StopPct  StopType = "pct"  // StopLossPct away from the average entry (default)
+	StopATR  StopType = "atr"  // ATRStopMultiple × ATR away from the average entry
+	StopAbs  StopType = "abs"  // StopLossAbs price units away from the average entry
+	StopNone StopType = "none" // no hard stop (strategy exits only)
+)
+
+// PositionMode names the sides a strategy is allowed to hold.
+type PositionMode string
+
+const (
+	PositionBoth      PositionMode = "both"       // long and short (default)
+	PositionLongOnly  PositionMode = "long_only"  // short signals only exit longs
+	PositionShortOnly PositionMode = "short_only" // long signals only cover shorts
+)
+
+// AllowsLong reports whether long positions are permitted.
+func (m PositionMode) AllowsLong() bool { return m != PositionShortOnly }
+
+// AllowsShort reports whether short positions are permitted.
+func (m PositionMode) AllowsShort() bool { return m != PositionLongOnly }
+
+// SizingMethod names a position‑sizing model.
+type SizingMethod string
+
+const (
+	SizingFixedFractional SizingMethod = "fixed_fractional" // risk MaxRiskPerTrade to a StopLossPct stop
+	SizingFixedNotional   SizingMethod = "fixed_notional"   // constant FixedNotional per trade
+	SizingVolTarget       SizingMethod = "vol_target"       // scale exposure to hit TargetVol
+	SizingATRStop         SizingMethod = "atr_stop"         // risk MaxRiskPerTrade to an ATR‑multiple stop
+	SizingKelly           SizingMethod = "kelly"            // fractional Kelly from the strategy's trade history
+	SizingOptimalF        SizingMethod = "optimal_f"        // fractional optimal f from the strategy's trade history
+)
+
 // Validate checks that all numeric fields are within sensible bounds.
 // It returns the first encountered error, allowing the caller to surface a
 // clear configuration problem before any trading starts.
@@ -83,5 +235,130 @@ func (c *StrategyConfig) Validate() error {
 	if c.StepSize <= 0 {
 		return errors.New("StepSize must be positive")
 	}
+	switch c.SignalFallback {
+	case "", FallbackAlways, FallbackWarmup, FallbackDisabled:
+	default:
+		return fmt.Errorf("unknown SignalFallback policy %q", c.SignalFallback)
+	}
+	switch c.StopType {
+	case "", StopPct, StopATR, StopNone:
+	case StopAbs:
+		if c.StopLossAbs <= 0 {
+			return errors.New("StopLossAbs must be positive for abs stops")
+		}
+	default:
+		return fmt.Errorf("unknown StopType %q", c.StopType)
+	}
+	if c.StopLossAbs < 0 {
+		return errors.New("StopLossAbs cannot be negative")
+	}
+	if c.TrailingATRMult < 0 {
+		return errors.New("TrailingATRMult cannot be negative")
+	}
+	if c.TrailingActivationPct < 0 {
+		return errors.New("TrailingActivationPct cannot be negative")
+	}
+	if c.MaxHoldingBars < 0 || c.ExitUnprofitableBars < 0 {
+		return errors.New("MaxHoldingBars and ExitUnprofitableBars cannot be negative")
+	}
+	if c.MaxHoldingDuration < 0 {
+		return errors.New("MaxHoldingDuration cannot be negative")
+	}
+	if c.SessionEnd != "" {
+		if _, err := c.SessionEndOffset(); err != nil {
+			return err
+		}
+	}
+	if err := c.validateScaling(); err != nil {
+		return err
+	}
+	switch c.PositionMode {
+	case "", PositionBoth, PositionLongOnly, PositionShortOnly:
+	default:
+		return fmt.Errorf("unknown PositionMode %q", c.PositionMode)
+	}
+	return c.validateSizing()
+}
+
+// validateSizing checks the parameters required by the selected sizer.
+func (c *StrategyConfig) validateSizing() error {
+	if c.FixedNotional < 0 {
+		return errors.New("FixedNotional cannot be negative")
+	}
+	if c.TargetVol < 0 || c.TargetVol > 5 {
+		return fmt.Errorf("TargetVol (%f) must be between 0 and 5", c.TargetVol)
+	}
+	if c.BarsPerYear < 0 {
+		return errors.New("BarsPerYear cannot be negative")
+	}
+	if c.ATRStopMultiple < 0 {
+		return errors.New("ATRStopMultiple cannot be negative")
+	}
+	if c.KellyFraction < 0 || c.KellyFraction > 1 {
+		return fmt.Errorf("KellyFraction (%f) must be between 0 and 1", c.KellyFraction)
+	}
+	if c.KellyMinTrades < 0 || c.KellyLookback < 0 {
+		return errors.New("KellyMinTrades and KellyLookback cannot be negative")
+	}
+	minTrades, lookback := c.KellyMinTrades, c.KellyLookback
+	if minTrades == 0 {
+		minTrades = DefaultKellyMinTrades
+	}
+	if lookback == 0 {
+		lookback = DefaultKellyLookback
+	}
+	if minTrades > lookback {
+		return fmt.Errorf("KellyMinTrades (%d) exceeds KellyLookback (%d)", minTrades, lookback)
+	}
+	if c.KellyMaxRisk < 0 || c.KellyMaxRisk > 0.5 {
+		return fmt.Errorf("KellyMaxRisk (%f) must be between 0 and 0.5", c.KellyMaxRisk)
+	}
+	switch c.Sizing {
+	case "", SizingFixedFractional, SizingATRStop, SizingKelly, SizingOptimalF:
+	case SizingFixedNotional:
+		if c.FixedNotional <= 0 {
+			return errors.New("FixedNotional must be positive for fixed_notional sizing")
+		}
+	case SizingVolTarget:
+		if c.TargetVol <= 0 {
+			return errors.New("TargetVol must be positive for vol_target sizing")
+		}
+	default:
+		return fmt.Errorf("unknown Sizing method %q", c.Sizing)
+	}
+	return nil
+}
+
+// SessionEndOffset parses SessionEnd into an offset from midnight.
+func (c *StrategyConfig) SessionEndOffset() (time.Duration, error) {
+	t, err := time.Parse("15:04", c.SessionEnd)
+	if err != nil {
+		return 0, fmt.Errorf("SessionEnd %q must be formatted HH:MM", c.SessionEnd)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (c *StrategyConfig) validateScaling() error {
+	if c.ScaleOutR < 0 || c.PyramidStepR < 0 || c.AverageDownStepR < 0 {
+		return errors.New("ScaleOutR, PyramidStepR and AverageDownStepR cannot be negative")
+	}
+	if c.ScaleOutR > 0 && (c.ScaleOutFraction <= 0 || c.ScaleOutFraction > 1) {
+		return fmt.Errorf("ScaleOutFraction (%f) must be in (0, 1]", c.ScaleOutFraction)
+	}
+	if c.PyramidMaxAdds < 0 || c.AverageDownMaxAdds < 0 {
+		return errors.New("PyramidMaxAdds and AverageDownMaxAdds cannot be negative")
+	}
+	if c.PyramidMaxAdds > 0 && c.PyramidStepR <= 0 {
+		return errors.New("PyramidStepR must be positive when pyramiding")
+	}
+	if c.AverageDownMaxAdds > 0 && c.AverageDownStepR <= 0 {
+		return errors.New("AverageDownStepR must be positive when averaging down")
+	}
+	if c.PyramidScale < 0 || c.PyramidScale > 1 {
+		return fmt.Errorf("PyramidScale (%f) must be between 0 and 1", c.PyramidScale)
+	}
+	if c.MaxPositionRisk < 0 || c.MaxPositionRisk > 1 {
+		return fmt.Errorf("MaxPositionRisk (%f) must be between 0 and 1", c.MaxPositionRisk)
+	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestValidateSuccess(t *testing.T) {
 	cfg := StrategyConfig{
@@ -45,3 +48,111 @@ func TestValidateFailsOnBadRisk(t *testing.T) {
 		t.Fatal("expected validation error for negative MaxRiskPerTrade")
 	}
 }
+
+func TestValidateSizing(t *testing.T) {
+	base := StrategyConfig{
+		RSIOverbought:     70,
+		RSIOversold:       30,
+		MFIOverbought:     80,
+		MFIOversold:       20,
+		HMAPeriod:         9,
+		ATSEMAperiod:      5,
+		MaxRiskPerTrade:   0.01,
+		StopLossPct:       0.015,
+		QuantityPrecision: 2,
+		StepSize:          0.0001,
+	}
+	cfg := base
+	cfg.Sizing = "unknown"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for unknown sizing method")
+	}
+	cfg = base
+	cfg.Sizing = SizingVolTarget
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for vol_target without TargetVol")
+	}
+	cfg.TargetVol = 0.15
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid vol_target config, got %v", err)
+	}
+
+	// An unset KellyLookback means the default window, which the minimum
+	// trade count must fit into.
+	cfg = base
+	cfg.Sizing = SizingKelly
+	cfg.KellyMinTrades = DefaultKellyLookback + 1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for KellyMinTrades above the default lookback")
+	}
+	cfg.KellyMinTrades = DefaultKellyLookback
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected KellyMinTrades equal to the default lookback to pass, got %v", err)
+	}
+
+	// An unset KellyMinTrades means the default minimum, which a short
+	// lookback could never collect.
+	cfg = base
+	cfg.Sizing = SizingKelly
+	cfg.KellyLookback = DefaultKellyMinTrades - 10
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for a KellyLookback below the default KellyMinTrades")
+	}
+	cfg.KellyLookback = DefaultKellyMinTrades
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected KellyLookback equal to the default KellyMinTrades to pass, got %v", err)
+	}
+}
+
+func TestValidateStopType(t *testing.T) {
+	base := StrategyConfig{
+		RSIOverbought:     70,
+		RSIOversold:       30,
+		MFIOverbought:     80,
+		MFIOversold:       20,
+		HMAPeriod:         9,
+		ATSEMAperiod:      5,
+		MaxRiskPerTrade:   0.01,
+		StopLossPct:       0.015,
+		QuantityPrecision: 2,
+		StepSize:          0.0001,
+	}
+	cfg := base
+	cfg.StopType = "bogus"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for unknown stop type")
+	}
+	cfg = base
+	cfg.StopType = StopAbs
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for abs stop without StopLossAbs")
+	}
+	cfg.StopLossAbs = 2.5
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid abs stop config, got %v", err)
+	}
+}
+
+func TestValidateSessionEnd(t *testing.T) {
+	cfg := StrategyConfig{
+		RSIOverbought:     70,
+		RSIOversold:       30,
+		MFIOverbought:     80,
+		MFIOversold:       20,
+		HMAPeriod:         9,
+		ATSEMAperiod:      5,
+		MaxRiskPerTrade:   0.01,
+		StopLossPct:       0.015,
+		QuantityPrecision: 2,
+		StepSize:          0.0001,
+		SessionEnd:        "25:99",
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for malformed SessionEnd")
+	}
+	cfg.SessionEnd = "15:55"
+	off, err := cfg.SessionEndOffset()
+	if err != nil || off != 15*time.Hour+55*time.Minute {
+		t.Fatalf("SessionEndOffset = %v, %v", off, err)
+	}
+}
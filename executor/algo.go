@@ -0,0 +1,353 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/metrics"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// AlgoType selects how AlgoExecutor schedules the child orders of a parent.
+type AlgoType string
+
+const (
+	// AlgoTWAP spreads the parent evenly over Bars bars.
+	AlgoTWAP AlgoType = "twap"
+	// AlgoVWAP follows VolumeProfile, the historical share of volume of
+	// each of the next bars.
+	AlgoVWAP AlgoType = "vwap"
+	// AlgoPOV trades ParticipationRate of the volume observed every bar.
+	AlgoPOV AlgoType = "pov"
+)
+
+// algoEpsilon absorbs float noise when comparing quantities.
+const algoEpsilon = 1e-9
+
+// AlgoConfig holds the schedule and limits of AlgoExecutor.
+type AlgoConfig struct {
+	Type AlgoType
+
+	Bars              int       // TWAP horizon in bars
+	VolumeProfile     []float64 // VWAP: relative volume of each bar of the horizon
+	ParticipationRate float64   // POV: fraction of every bar's volume, (0, 1]
+
+	// MaxParticipation caps every child at this fraction of the bar's
+	// volume; 0 means no cap.
+	MaxParticipation float64
+
+	// LimitPct skips the bars whose close is more than LimitPct worse than
+	// the arrival price (above it for buys, below it for sells); the
+	// skipped quantity is caught up later.  0 means no price limit.
+	LimitPct float64
+
+	// MinParentQty routes smaller orders straight to the wrapped executor.
+	MinParentQty float64
+}
+
+// DefaultAlgoConfig works every parent as a 10‑bar TWAP.
+func DefaultAlgoConfig() AlgoConfig {
+	return AlgoConfig{Type: AlgoTWAP, Bars: 10}
+}
+
+// Validate checks the schedule and limits.
+func (ac AlgoConfig) Validate() error {
+	switch ac.Type {
+	case AlgoTWAP:
+		if ac.Bars < 1 {
+			return fmt.Errorf("TWAP Bars (%d) must be at least 1", ac.Bars)
+		}
+	case AlgoVWAP:
+		if len(ac.VolumeProfile) == 0 {
+			return errors.New("VWAP needs a VolumeProfile")
+		}
+		sum := 0.0
+		for _, v := range ac.VolumeProfile {
+			if v < 0 {
+				return fmt.Errorf("VolumeProfile entries must not be negative, got %f", v)
+			}
+			sum += v
+		}
+		if sum <= 0 {
+			return errors.New("VolumeProfile must not be all zero")
+		}
+	case AlgoPOV:
+		if ac.ParticipationRate <= 0 || ac.ParticipationRate > 1 {
+			return fmt.Errorf("ParticipationRate (%f) must be in (0, 1]", ac.ParticipationRate)
+		}
+	default:
+		return fmt.Errorf("unknown execution algo %q", ac.Type)
+	}
+	if ac.MaxParticipation < 0 || ac.MaxParticipation > 1 {
+		return fmt.Errorf("MaxParticipation (%f) must be in [0, 1]", ac.MaxParticipation)
+	}
+	if ac.LimitPct < 0 || ac.MinParentQty < 0 {
+		return errors.New("LimitPct and MinParentQty must not be negative")
+	}
+	return nil
+}
+
+// ParentStatus is the state of a parent order.
+type ParentStatus string
+
+const (
+	ParentWorking   ParentStatus = "working"
+	ParentFilled    ParentStatus = "filled"
+	ParentCancelled ParentStatus = "cancelled" // by Cancel or by a newer parent on the symbol
+)
+
+// ExecutionReport describes a parent order worked by AlgoExecutor.
+type ExecutionReport struct {
+	ID       string
+	Symbol   string
+	Side     types.Side
+	Algo     AlgoType
+	Status   ParentStatus
+	Qty      float64 // parent quantity
+	Filled   float64
+	Children int
+	Bars     int    // bars worked
+	Comment  string // of the parent, copied to its children
+
+	// ArrivalPrice is the parent's Price, or the first close seen for a
+	// market parent.  AvgPrice is the volume‑weighted fill price and
+	// LastPrice the last close seen while working.
+	ArrivalPrice float64
+	AvgPrice     float64
+	LastPrice    float64
+}
+
+// Shortfall returns the implementation shortfall versus the arrival price
+// in quote currency: the execution cost of the filled quantity plus the
+// opportunity cost of the unfilled rest marked at LastPrice.  Positive is
+// a cost.
+func (r ExecutionReport) Shortfall() float64 {
+	sign := 1.0
+	if r.Side == types.Sell {
+		sign = -1
+	}
+	exec := sign * (r.AvgPrice - r.ArrivalPrice) * r.Filled
+	opportunity := sign * (r.LastPrice - r.ArrivalPrice) * (r.Qty - r.Filled)
+	return exec + opportunity
+}
+
+// ShortfallBps returns Shortfall in basis points of the arrival notional.
+func (r ExecutionReport) ShortfallBps() float64 {
+	notional := r.Qty * r.ArrivalPrice
+	if notional == 0 {
+		return 0
+	}
+	return r.Shortfall() / notional * 1e4
+}
+
+// AlgoExecutor sits between a strategy and any Executor and works large
+// orders as parent orders: Submit starts a parent, and every OnBar sends
+// the child order the schedule calls for, filled at the bar's close.  A
+// newer parent on the same symbol replaces the working one, so a strategy
+// that re‑submits the difference to its target every bar stays in control.
+// Equity and Position are those of the wrapped executor.
+//
+// Backtests call OnBar for every symbol before ProcessBar; a parent
+// submitted on a bar starts trading on the next one.
+type AlgoExecutor struct {
+	inner Executor
+	algo  AlgoConfig
+	cfg   config.StrategyConfig // child quantity rounding
+	mu    sync.Mutex
+
+	seq     int
+	working map[string]*ExecutionReport
+	done    []ExecutionReport
+}
+
+// NewAlgoExecutor wraps inner.  Child quantities are rounded with
+// risk.RoundQty under cfg.
+func NewAlgoExecutor(inner Executor, ac AlgoConfig, cfg config.StrategyConfig) (*AlgoExecutor, error) {
+	if inner == nil {
+		return nil, errors.New("algo executor needs an executor to wrap")
+	}
+	if err := ac.Validate(); err != nil {
+		return nil, err
+	}
+	return &AlgoExecutor{
+		inner:   inner,
+		algo:    ac,
+		cfg:     cfg,
+		working: make(map[string]*ExecutionReport),
+	}, nil
+}
+
+// Submit starts a parent order, replacing the one working on the symbol,
+// or passes an order below MinParentQty straight through.
+func (a *AlgoExecutor) Submit(o types.Order) error {
+	if o.Qty <= 0 {
+		return nil
+	}
+	if o.Qty < a.algo.MinParentQty {
+		return a.inner.Submit(o)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p := a.working[o.Symbol]; p != nil {
+		a.finish(p, ParentCancelled)
+	}
+	a.seq++
+	a.working[o.Symbol] = &ExecutionReport{
+		ID:           "P" + strconv.Itoa(a.seq),
+		Symbol:       o.Symbol,
+		Side:         o.Side,
+		Algo:         a.algo.Type,
+		Status:       ParentWorking,
+		Qty:          o.Qty,
+		Comment:      o.Comment,
+		ArrivalPrice: o.Price,
+		LastPrice:    o.Price,
+	}
+	return nil
+}
+
+// Equity returns the equity of the wrapped executor.
+func (a *AlgoExecutor) Equity() float64 { return a.inner.Equity() }
+
+// Position returns the filled position of the wrapped executor; the
+// unfilled rest of a working parent is not included.
+func (a *AlgoExecutor) Position(symbol string) (float64, float64) {
+	return a.inner.Position(symbol)
+}
+
+// OnBar works the parent of symbol through one bar and returns the child
+// order it sent, if any.
+func (a *AlgoExecutor) OnBar(symbol string, high, low, close, volume float64) (types.Order, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.working[symbol]
+	if p == nil || close <= 0 {
+		return types.Order{}, false
+	}
+	if p.ArrivalPrice == 0 {
+		p.ArrivalPrice = close
+	}
+	p.LastPrice = close
+	p.Bars++
+
+	remaining := p.Qty - p.Filled
+	if a.cfg.QuantityPrecision > 0 {
+		// Children are rounded, so the rest is too up to float noise.
+		f := math.Pow10(a.cfg.QuantityPrecision)
+		remaining = math.Round(remaining*f) / f
+	}
+	want := a.childQty(p, volume)
+	if a.algo.MaxParticipation > 0 {
+		want = math.Min(want, a.algo.MaxParticipation*volume)
+	}
+	if want <= 0 || !a.withinLimit(p, close) {
+		return types.Order{}, false
+	}
+	qty := remaining
+	if want < remaining-algoEpsilon {
+		qty = risk.RoundQty(want, a.cfg)
+	}
+	if qty <= 0 {
+		return types.Order{}, false
+	}
+
+	child := types.Order{Symbol: symbol, Side: p.Side, Qty: qty, Price: close, Comment: p.Comment}
+	before, _ := a.inner.Position(symbol)
+	if err := a.inner.Submit(child); err != nil {
+		_ = log.Output(2, "algo executor: child order failed: "+err.Error())
+		return types.Order{}, false
+	}
+	after, _ := a.inner.Position(symbol)
+	filled := after - before
+	if p.Side == types.Sell {
+		filled = -filled
+	}
+	if filled <= 0 {
+		return types.Order{}, false
+	}
+	if math.Abs(filled-qty) <= algoEpsilon {
+		filled = qty
+	}
+	p.AvgPrice = (p.AvgPrice*p.Filled + close*filled) / (p.Filled + filled)
+	p.Filled += filled
+	p.Children++
+	child.Qty = filled
+	if p.Qty-p.Filled <= algoEpsilon {
+		a.finish(p, ParentFilled)
+	}
+	return child, true
+}
+
+// childQty is the quantity the schedule wants traded on the current bar,
+// including any shortfall from earlier bars.
+func (a *AlgoExecutor) childQty(p *ExecutionReport, volume float64) float64 {
+	switch a.algo.Type {
+	case AlgoPOV:
+		return a.algo.ParticipationRate * volume
+	case AlgoVWAP:
+		total, cum := 0.0, 0.0
+		for i, v := range a.algo.VolumeProfile {
+			total += v
+			if i < p.Bars {
+				cum += v
+			}
+		}
+		return p.Qty*cum/total - p.Filled
+	default:
+		return p.Qty*math.Min(float64(p.Bars)/float64(a.algo.Bars), 1) - p.Filled
+	}
+}
+
+// withinLimit reports whether close is inside the price limit of p.
+func (a *AlgoExecutor) withinLimit(p *ExecutionReport, close float64) bool {
+	if a.algo.LimitPct <= 0 {
+		return true
+	}
+	if p.Side == types.Buy {
+		return close <= p.ArrivalPrice*(1+a.algo.LimitPct)
+	}
+	return close >= p.ArrivalPrice*(1-a.algo.LimitPct)
+}
+
+// finish moves p to the reports and records its shortfall.
+func (a *AlgoExecutor) finish(p *ExecutionReport, status ParentStatus) {
+	p.Status = status
+	delete(a.working, p.Symbol)
+	a.done = append(a.done, *p)
+	metrics.ImplementationShortfall.WithLabelValues(string(p.Algo)).Observe(p.ShortfallBps())
+}
+
+// Cancel stops the parent working on symbol and returns its report.
+func (a *AlgoExecutor) Cancel(symbol string) (ExecutionReport, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.working[symbol]
+	if p == nil {
+		return ExecutionReport{}, false
+	}
+	a.finish(p, ParentCancelled)
+	return *p, true
+}
+
+// Working returns the report of the parent working on symbol.
+func (a *AlgoExecutor) Working(symbol string) (ExecutionReport, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p := a.working[symbol]; p != nil {
+		return *p, true
+	}
+	return ExecutionReport{}, false
+}
+
+// Reports returns the finished parents in the order they finished.
+func (a *AlgoExecutor) Reports() []ExecutionReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ExecutionReport(nil), a.done...)
+}
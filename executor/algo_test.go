@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/types"
+)
+
+var algoRounding = config.StrategyConfig{QuantityPrecision: 2, MinQty: 0.01}
+
+func newAlgo(t *testing.T, ac AlgoConfig) (*AlgoExecutor, *PaperExecutor) {
+	t.Helper()
+	paper := NewPaperExecutor(1_000_000)
+	a, err := NewAlgoExecutor(paper, ac, algoRounding)
+	if err != nil {
+		t.Fatalf("NewAlgoExecutor failed: %v", err)
+	}
+	return a, paper
+}
+
+// workBars feeds one bar per close with the given volume and returns the
+// child quantities sent.
+func workBars(a *AlgoExecutor, symbol string, closes []float64, volume float64) []float64 {
+	var qtys []float64
+	for _, c := range closes {
+		if child, ok := a.OnBar(symbol, c, c, c, volume); ok {
+			qtys = append(qtys, child.Qty)
+		}
+	}
+	return qtys
+}
+
+func TestAlgoTWAP(t *testing.T) {
+	a, paper := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 4})
+	if err := a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 100, Price: 10, Comment: "rebalance"}); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if pos, _ := a.Position("X"); pos != 0 {
+		t.Fatalf("a parent must not trade on submission, position %v", pos)
+	}
+	qtys := workBars(a, "X", []float64{10, 10.1, 10.2, 10.3, 10.4}, 1000)
+	if !reflect.DeepEqual(qtys, []float64{25, 25, 25, 25}) {
+		t.Fatalf("TWAP children %v, want four slices of 25", qtys)
+	}
+	if pos, _ := paper.Position("X"); pos != 100 {
+		t.Fatalf("position %v, want the whole parent", pos)
+	}
+
+	reports := a.Reports()
+	if len(reports) != 1 {
+		t.Fatalf("expected one finished parent, got %+v", reports)
+	}
+	r := reports[0]
+	if r.Status != ParentFilled || r.Children != 4 || r.Comment != "rebalance" {
+		t.Fatalf("unexpected report %+v", r)
+	}
+	if math.Abs(r.AvgPrice-10.15) > 1e-9 {
+		t.Fatalf("average price %v, want 10.15", r.AvgPrice)
+	}
+	// Buying 100 at 10.15 against a 10 arrival costs 15, i.e. 150 bps.
+	if math.Abs(r.Shortfall()-15) > 1e-9 || math.Abs(r.ShortfallBps()-150) > 1e-6 {
+		t.Fatalf("shortfall %v (%v bps), want 15 (150 bps)", r.Shortfall(), r.ShortfallBps())
+	}
+	if _, ok := a.Working("X"); ok {
+		t.Fatal("a filled parent must not be working")
+	}
+}
+
+func TestAlgoTWAPRounding(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 3})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 1, Price: 10})
+	qtys := workBars(a, "X", []float64{10, 10, 10}, 1000)
+	if !reflect.DeepEqual(qtys, []float64{0.33, 0.33, 0.34}) {
+		t.Fatalf("children %v, want 0.33, 0.33 and the 0.34 rest", qtys)
+	}
+}
+
+func TestAlgoVWAP(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoVWAP, VolumeProfile: []float64{1, 3, 4, 2}})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 100, Price: 10})
+	qtys := workBars(a, "X", []float64{10, 10, 10, 10}, 1000)
+	if !reflect.DeepEqual(qtys, []float64{10, 30, 40, 20}) {
+		t.Fatalf("VWAP children %v, want the 1:3:4:2 profile", qtys)
+	}
+	if pos, _ := a.Position("X"); pos != -100 {
+		t.Fatalf("position %v, want a 100 short", pos)
+	}
+}
+
+func TestAlgoPOV(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoPOV, ParticipationRate: 0.1})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 100, Price: 10})
+	var qtys []float64
+	for _, v := range []float64{200, 500, 0, 1000, 1000} {
+		if child, ok := a.OnBar("X", 10, 10, 10, v); ok {
+			qtys = append(qtys, child.Qty)
+		}
+	}
+	if !reflect.DeepEqual(qtys, []float64{20, 50, 30}) {
+		t.Fatalf("POV children %v, want 10 %% of volume up to the parent", qtys)
+	}
+}
+
+func TestAlgoParticipationCap(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 2, MaxParticipation: 0.1})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 100, Price: 10})
+	qtys := workBars(a, "X", []float64{10, 10, 10, 10}, 300)
+	if !reflect.DeepEqual(qtys, []float64{30, 30, 30, 10}) {
+		t.Fatalf("children %v, want at most 10 %% of the 300 volume each bar", qtys)
+	}
+}
+
+func TestAlgoPriceLimit(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 2, LimitPct: 0.01})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 100, Price: 100})
+	if _, ok := a.OnBar("X", 102, 102, 102, 1000); ok {
+		t.Fatal("a buy child must not trade more than 1 % above arrival")
+	}
+	if child, ok := a.OnBar("X", 100.5, 100.5, 100.5, 1000); !ok || child.Qty != 100 {
+		t.Fatalf("the skipped slice must be caught up, got %+v", child)
+	}
+}
+
+func TestAlgoReplaceAndCancel(t *testing.T) {
+	a, _ := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 4})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 100, Price: 50})
+	workBars(a, "X", []float64{49}, 1000)
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 40, Price: 49})
+	if r := a.Reports(); len(r) != 1 || r[0].Status != ParentCancelled || r[0].Filled != 25 {
+		t.Fatalf("the newer parent must replace the working one, got %+v", r)
+	}
+	if w, ok := a.Working("X"); !ok || w.Qty != 40 || w.ID == a.Reports()[0].ID {
+		t.Fatalf("unexpected working parent %+v", w)
+	}
+
+	workBars(a, "X", []float64{48}, 1000)
+	r, ok := a.Cancel("X")
+	if !ok || r.Status != ParentCancelled || r.Filled != 10 {
+		t.Fatalf("unexpected cancelled parent %+v", r)
+	}
+	// Sold 10 at 48 against 49 (cost 10) and 30 unsold marked at 48
+	// (opportunity cost 30).
+	if math.Abs(r.Shortfall()-40) > 1e-9 {
+		t.Fatalf("shortfall %v, want 40", r.Shortfall())
+	}
+	if _, ok := a.Cancel("X"); ok {
+		t.Fatal("nothing is left to cancel")
+	}
+}
+
+func TestAlgoSmallOrdersPassThrough(t *testing.T) {
+	a, paper := newAlgo(t, AlgoConfig{Type: AlgoTWAP, Bars: 4, MinParentQty: 10})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 5, Price: 10})
+	if pos, _ := paper.Position("X"); pos != 5 {
+		t.Fatalf("an order below MinParentQty must fill at once, position %v", pos)
+	}
+	if _, ok := a.Working("X"); ok {
+		t.Fatal("no parent expected")
+	}
+}
+
+func TestAlgoConfigValidate(t *testing.T) {
+	if err := DefaultAlgoConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []AlgoConfig{
+		{Type: "iceberg", Bars: 4},
+		{Type: AlgoTWAP},
+		{Type: AlgoVWAP},
+		{Type: AlgoVWAP, VolumeProfile: []float64{0, 0}},
+		{Type: AlgoVWAP, VolumeProfile: []float64{1, -1}},
+		{Type: AlgoPOV, ParticipationRate: 1.5},
+		{Type: AlgoTWAP, Bars: 4, MaxParticipation: 2},
+		{Type: AlgoTWAP, Bars: 4, LimitPct: -0.1},
+	}
+	for i, ac := range bad {
+		if err := ac.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, ac)
+		}
+	}
+	if _, err := NewAlgoExecutor(nil, DefaultAlgoConfig(), algoRounding); err == nil {
+		t.Fatal("expected an error without a wrapped executor")
+	}
+}
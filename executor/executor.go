@@ -1,7 +1,9 @@
 package executor
 
 import (
+	"errors"
 	"log"
+	"math"
 	"sync"
 
 	"github.com/evdnx/gots/metrics"
@@ -21,6 +23,7 @@ type PaperExecutor struct {
 	equity    float64
 	positions map[string]float64 // qty (positive = long, negative = short)
 	avgPrice  map[string]float64
+	book      Book
 }
 
 // NewPaperExecutor creates a fresh executor with the supplied starting equity.
@@ -32,39 +35,91 @@ func NewPaperExecutor(startEquity float64) *PaperExecutor {
 	}
 }
 
-// Submit processes a market order (perfect fills, no slippage).
+// Submit processes a market order (perfect fills, no slippage).  Cash
+// reserved for resting buy limits is not available to it.
 func (p *PaperExecutor) Submit(o types.Order) error {
 	if o.Qty == 0 {
 		return nil
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.fill(o)
+	return nil
+}
 
+// fill executes o at o.Price.  It reports false when a buy exceeds the
+// unreserved cash.
+func (p *PaperExecutor) fill(o types.Order) bool {
 	cost := o.Price * o.Qty
 	if o.Side == types.Buy {
-		if cost > p.equity {
-			return log.Output(2, "paper executor: insufficient cash")
+		if cost > p.equity-p.book.Reserved() {
+			_ = log.Output(3, "paper executor: insufficient cash")
+			return false
 		}
 		p.equity -= cost
+		prev := p.positions[o.Symbol]
 		p.positions[o.Symbol] += o.Qty
-		prev := p.avgPrice[o.Symbol]
-		newAvg := (prev*(p.positions[o.Symbol]-o.Qty) + cost) / p.positions[o.Symbol]
-		p.avgPrice[o.Symbol] = newAvg
+		p.avgPrice[o.Symbol] = NextAvgPrice(prev, p.avgPrice[o.Symbol], o.Qty, o.Price)
 	} else { // Sell / short
 		p.equity += cost
+		prev := p.positions[o.Symbol]
 		p.positions[o.Symbol] -= o.Qty
-		prev := p.avgPrice[o.Symbol]
-		newAvg := (prev*(p.positions[o.Symbol]+o.Qty) + cost) / p.positions[o.Symbol]
-		p.avgPrice[o.Symbol] = newAvg
+		p.avgPrice[o.Symbol] = NextAvgPrice(prev, p.avgPrice[o.Symbol], -o.Qty, o.Price)
 	}
 	metrics.OrdersSubmitted.WithLabelValues("paper").Inc()
 	metrics.EquityGauge.Set(p.equity)
 
 	log.Printf("[EXEC] %s %s %.4f @ %.2f (eq: %.2f)",
 		o.Side, o.Symbol, o.Qty, o.Price, p.equity)
+	return true
+}
+
+// PlaceLimit rests a limit order at o.Price until MatchBar crosses it.  A
+// buy reserves its cost and is rejected when the cash is not available.
+func (p *PaperExecutor) PlaceLimit(o types.Order) (string, error) {
+	if o.Qty <= 0 || o.Price <= 0 {
+		return "", errors.New("paper executor: limit order needs a positive qty and price")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if o.Side == types.Buy && o.Qty*o.Price > p.equity-p.book.Reserved() {
+		return "", errors.New("paper executor: insufficient cash")
+	}
+	return p.book.Add(o), nil
+}
+
+// Cancel removes a resting limit order.
+func (p *PaperExecutor) Cancel(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.book.Remove(id) {
+		return errors.New("paper executor: unknown order " + id)
+	}
 	return nil
 }
 
+// OpenOrders returns the resting limit orders of symbol.
+func (p *PaperExecutor) OpenOrders(symbol string) []types.RestingOrder {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.book.Open(symbol)
+}
+
+// MatchBar fills, at their limit price, the resting orders of symbol that
+// a bar trading between low and high reaches, and returns them.  Backtest
+// loops call it before handing the bar to the strategy.
+func (p *PaperExecutor) MatchBar(symbol string, high, low float64) []types.RestingOrder {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var filled []types.RestingOrder
+	for _, o := range p.book.Cross(symbol, high, low) {
+		if p.fill(o.Order) {
+			filled = append(filled, o)
+		}
+	}
+	return filled
+}
+
 // Equity returns the current cash balance (thread‑safe).
 func (p *PaperExecutor) Equity() float64 {
 	p.mu.RLock()
@@ -78,3 +133,22 @@ func (p *PaperExecutor) Position(sym string) (float64, float64) {
 	defer p.mu.RUnlock()
 	return p.positions[sym], p.avgPrice[sym]
 }
+
+// NextAvgPrice returns the average entry price after a fill of signedQty
+// (positive = buy) at price against a position of prevQty with average
+// entry prevAvg.  Adding to a position blends the price, reducing it keeps
+// the average, flipping through flat restarts at the fill price and a flat
+// result yields 0.
+func NextAvgPrice(prevQty, prevAvg, signedQty, price float64) float64 {
+	newQty := prevQty + signedQty
+	switch {
+	case newQty == 0:
+		return 0
+	case prevQty == 0 || (prevQty > 0) != (newQty > 0):
+		return price
+	case (prevQty > 0) == (signedQty > 0):
+		return (prevAvg*math.Abs(prevQty) + price*math.Abs(signedQty)) / math.Abs(newQty)
+	default:
+		return prevAvg
+	}
+}
@@ -42,3 +42,63 @@ func TestPaperExecutor_InsufficientCash(t *testing.T) {
 		t.Fatalf("equity should stay unchanged on insufficient cash")
 	}
 }
+
+func TestPaperExecutor_ShortAveragePrice(t *testing.T) {
+	ex := NewPaperExecutor(10_000)
+	_ = ex.Submit(types.Order{Symbol: "ETHUSD", Side: types.Sell, Qty: 2, Price: 100})
+	_ = ex.Submit(types.Order{Symbol: "ETHUSD", Side: types.Sell, Qty: 2, Price: 110})
+	qty, avg := ex.Position("ETHUSD")
+	if qty != -4 || avg != 105 {
+		t.Fatalf("expected short 4 @ 105, got qty=%v avg=%v", qty, avg)
+	}
+	// Partial cover keeps the average; flipping long restarts it.
+	_ = ex.Submit(types.Order{Symbol: "ETHUSD", Side: types.Buy, Qty: 1, Price: 90})
+	if _, avg := ex.Position("ETHUSD"); avg != 105 {
+		t.Fatalf("partial cover should keep avg 105, got %v", avg)
+	}
+	_ = ex.Submit(types.Order{Symbol: "ETHUSD", Side: types.Buy, Qty: 5, Price: 95})
+	if qty, avg := ex.Position("ETHUSD"); qty != 2 || avg != 95 {
+		t.Fatalf("expected long 2 @ 95 after flip, got qty=%v avg=%v", qty, avg)
+	}
+}
+
+func TestPaperExecutor_LimitOrders(t *testing.T) {
+	ex := NewPaperExecutor(1000)
+	buy, err := ex.PlaceLimit(types.Order{Symbol: "BTCUSD", Side: types.Buy, Qty: 5, Price: 90})
+	if err != nil {
+		t.Fatalf("place buy: %v", err)
+	}
+	// 450 is reserved: a second buy beyond the remaining cash is rejected,
+	// and so is a market buy.
+	if _, err := ex.PlaceLimit(types.Order{Symbol: "BTCUSD", Side: types.Buy, Qty: 6, Price: 100}); err == nil {
+		t.Fatal("expected the reservation to reject the second buy")
+	}
+	_ = ex.Submit(types.Order{Symbol: "BTCUSD", Side: types.Buy, Qty: 6, Price: 100})
+	if qty, _ := ex.Position("BTCUSD"); qty != 0 {
+		t.Fatalf("market buy must not use reserved cash, position %v", qty)
+	}
+	sell, _ := ex.PlaceLimit(types.Order{Symbol: "BTCUSD", Side: types.Sell, Qty: 2, Price: 110})
+
+	if filled := ex.MatchBar("BTCUSD", 105, 95); len(filled) != 0 {
+		t.Fatalf("nothing should fill inside the range, got %+v", filled)
+	}
+	filled := ex.MatchBar("BTCUSD", 100, 89)
+	if len(filled) != 1 || filled[0].ID != buy {
+		t.Fatalf("expected the buy to fill, got %+v", filled)
+	}
+	if qty, avg := ex.Position("BTCUSD"); qty != 5 || avg != 90 {
+		t.Fatalf("unexpected position after fill: qty=%v avg=%v", qty, avg)
+	}
+	if open := ex.OpenOrders("BTCUSD"); len(open) != 1 || open[0].ID != sell {
+		t.Fatalf("only the sell should rest, got %+v", open)
+	}
+	if err := ex.Cancel(sell); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := ex.Cancel(sell); err == nil {
+		t.Fatal("cancelling twice must fail")
+	}
+	if filled := ex.MatchBar("BTCUSD", 120, 100); len(filled) != 0 {
+		t.Fatalf("cancelled order filled: %+v", filled)
+	}
+}
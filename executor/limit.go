@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"strconv"
+
+	"github.com/evdnx/gots/types"
+)
+
+// LimitExecutor is implemented by executors that can rest limit orders.
+// An order that disappears from OpenOrders without being cancelled has
+// been filled at its limit price.
+type LimitExecutor interface {
+	Executor
+	PlaceLimit(o types.Order) (id string, err error)
+	Cancel(id string) error
+	OpenOrders(symbol string) []types.RestingOrder
+}
+
+// Book is an in‑memory book of resting limit orders used by the simulated
+// executors.  It is not safe for concurrent use; the owner holds its lock.
+type Book struct {
+	seq    int
+	orders []types.RestingOrder
+}
+
+// Add rests o and returns its id.
+func (b *Book) Add(o types.Order) string {
+	b.seq++
+	id := "L" + strconv.Itoa(b.seq)
+	b.orders = append(b.orders, types.RestingOrder{ID: id, Order: o})
+	return id
+}
+
+// Remove takes the order with the given id off the book and reports
+// whether it was resting.
+func (b *Book) Remove(id string) bool {
+	for i, o := range b.orders {
+		if o.ID == id {
+			b.orders = append(b.orders[:i], b.orders[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns the resting orders of symbol in placement order.
+func (b *Book) Open(symbol string) []types.RestingOrder {
+	var out []types.RestingOrder
+	for _, o := range b.orders {
+		if o.Symbol == symbol {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Reserved returns the cash committed to resting buy orders.
+func (b *Book) Reserved() float64 {
+	sum := 0.0
+	for _, o := range b.orders {
+		if o.Side == types.Buy {
+			sum += o.Qty * o.Price
+		}
+	}
+	return sum
+}
+
+// Cross removes and returns, in placement order, the orders of symbol that
+// a bar trading between low and high reaches: buys at or above low and
+// sells at or below high.
+func (b *Book) Cross(symbol string, high, low float64) []types.RestingOrder {
+	var crossed []types.RestingOrder
+	kept := b.orders[:0]
+	for _, o := range b.orders {
+		if o.Symbol == symbol && ((o.Side == types.Buy && low <= o.Price) || (o.Side == types.Sell && high >= o.Price)) {
+			crossed = append(crossed, o)
+			continue
+		}
+		kept = append(kept, o)
+	}
+	b.orders = kept
+	return crossed
+}
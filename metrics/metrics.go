@@ -21,14 +21,39 @@ var (
 		[]string{"strategy"},
 	)
 
+	SignalSources = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gots_signal_sources_total",
+			Help: "Signals evaluated per bar, by signal name and source (indicator, fallback, both, none).",
+		},
+		[]string{"signal", "source"},
+	)
+
 	EquityGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "gots_equity",
 			Help: "Current equity of the executor (paper or live).",
 		},
 	)
+
+	ImplementationShortfall = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "gots_implementation_shortfall_bps",
+			Help:    "Implementation shortfall of finished parent orders versus arrival price, in basis points (by algo).",
+			Buckets: []float64{-100, -50, -20, -10, -5, 0, 5, 10, 20, 50, 100},
+		},
+		[]string{"algo"},
+	)
+
+	AccountEquity = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gots_account_equity",
+			Help: "Marked equity of each portfolio sub‑account.",
+		},
+		[]string{"account"},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(OrdersSubmitted, PositionsOpen, EquityGauge)
+	prometheus.MustRegister(OrdersSubmitted, PositionsOpen, SignalSources, EquityGauge, ImplementationShortfall, AccountEquity)
 }
@@ -0,0 +1,195 @@
+// Package portfolio runs several strategies side by side on one executor.
+// Every strategy trades its own sub‑account with allocated capital, own
+// positions and own P&L, and the Portfolio nets the sub‑account positions
+// onto the real executor.
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/types"
+)
+
+// ErrInsufficientCash is returned when a buy exceeds the cash of a
+// sub‑account.
+var ErrInsufficientCash = errors.New("portfolio: insufficient cash in sub-account")
+
+// Account is a sub‑account of a Portfolio.  It implements
+// executor.Executor, so any strategy can be constructed on it: market
+// orders fill at their price on the account's own book, and Equity is the
+// account's cash like with PaperExecutor, so strategies size off their
+// allocation only.  Orders reach the real executor when the Portfolio nets
+// the accounts.  It is safe for concurrent use.
+type Account struct {
+	name   string
+	weight float64
+
+	mu        sync.Mutex
+	cash      float64
+	positions map[string]float64
+	avgPrice  map[string]float64
+	marks     map[string]float64 // last close or fill per symbol
+	funded    float64            // net capital transferred in
+	realized  float64
+	returns   []float64 // per‑bar returns of the marked equity
+	lastNAV   float64
+}
+
+var _ executor.Executor = (*Account)(nil)
+
+func newAccount(name string, weight float64) *Account {
+	return &Account{
+		name:      name,
+		weight:    weight,
+		positions: make(map[string]float64),
+		avgPrice:  make(map[string]float64),
+		marks:     make(map[string]float64),
+	}
+}
+
+// Name returns the account name.
+func (a *Account) Name() string { return a.name }
+
+// Submit fills a market order at o.Price.  A buy that exceeds the cash of
+// the account is rejected with ErrInsufficientCash.
+func (a *Account) Submit(o types.Order) error {
+	if o.Qty <= 0 {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	signed := o.Qty
+	if o.Side == types.Sell {
+		signed = -o.Qty
+	} else if o.Qty*o.Price > a.cash {
+		return ErrInsufficientCash
+	}
+	prev, avg := a.positions[o.Symbol], a.avgPrice[o.Symbol]
+	if prev != 0 && (prev > 0) != (signed > 0) {
+		closed := math.Min(math.Abs(prev), o.Qty)
+		a.realized += closed * (o.Price - avg) * math.Copysign(1, prev)
+	}
+	a.cash -= signed * o.Price
+	a.positions[o.Symbol] = prev + signed
+	a.avgPrice[o.Symbol] = executor.NextAvgPrice(prev, avg, signed, o.Price)
+	a.marks[o.Symbol] = o.Price
+	return nil
+}
+
+// Equity returns the cash of the account.
+func (a *Account) Equity() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cash
+}
+
+// Position returns the account's quantity and average entry price.
+func (a *Account) Position(symbol string) (float64, float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.positions[symbol], a.avgPrice[symbol]
+}
+
+// NAV returns the cash plus the positions marked at the latest closes.
+func (a *Account) NAV() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.nav()
+}
+
+func (a *Account) nav() float64 {
+	nav := a.cash
+	for sym, qty := range a.positions {
+		nav += qty * a.marks[sym]
+	}
+	return nav
+}
+
+// PnL returns the marked profit of the account: its NAV less the capital
+// transferred in.
+func (a *Account) PnL() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.nav() - a.funded
+}
+
+// RealizedPnL returns the profit of the closed parts of positions.
+func (a *Account) RealizedPnL() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.realized
+}
+
+// mark values the positions at the supplied closes.
+func (a *Account) mark(closes map[string]float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for sym, c := range closes {
+		if c > 0 {
+			a.marks[sym] = c
+		}
+	}
+}
+
+// recordReturn appends the return of the NAV since the previous bar,
+// keeping the last n.
+func (a *Account) recordReturn(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	nav := a.nav()
+	if a.lastNAV > 0 {
+		a.returns = append(a.returns, nav/a.lastNAV-1)
+		if len(a.returns) > n {
+			a.returns = a.returns[len(a.returns)-n:]
+		}
+	}
+	a.lastNAV = nav
+}
+
+// transfer moves amount of cash into the account (out of it when
+// negative).  Withdrawals are limited to the cash; the amount moved is
+// returned.
+func (a *Account) transfer(amount float64) float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if amount < 0 {
+		amount = -math.Min(-amount, math.Max(a.cash, 0))
+	}
+	a.cash += amount
+	a.funded += amount
+	a.lastNAV = a.nav()
+	return amount
+}
+
+// symbols returns the symbols the account has traded.
+func (a *Account) symbols() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]string, 0, len(a.positions))
+	for sym := range a.positions {
+		out = append(out, sym)
+	}
+	return out
+}
+
+// stats returns the mean and standard deviation of the last n returns,
+// and false while fewer are known.
+func (a *Account) stats(n int) (mean, sd float64, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.returns) < n {
+		return 0, 0, false
+	}
+	recent := a.returns[len(a.returns)-n:]
+	for _, r := range recent {
+		mean += r
+	}
+	mean /= float64(n)
+	for _, r := range recent {
+		sd += (r - mean) * (r - mean)
+	}
+	return mean, math.Sqrt(sd / float64(n)), true
+}
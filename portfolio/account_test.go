@@ -0,0 +1,66 @@
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/types"
+)
+
+func fundedAccount(cash float64) *Account {
+	a := newAccount("a", 1)
+	a.transfer(cash)
+	return a
+}
+
+func TestAccountFillsAndRejectsBeyondCash(t *testing.T) {
+	a := fundedAccount(1_000)
+	if err := a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 11, Price: 100}); !errors.Is(err, ErrInsufficientCash) {
+		t.Fatalf("expected a buy above the cash to be rejected, got %v", err)
+	}
+	if err := a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 8, Price: 100}); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if qty, avg := a.Position("X"); qty != 8 || avg != 100 {
+		t.Fatalf("position %v @ %v, want 8 @ 100", qty, avg)
+	}
+	if cash := a.Equity(); cash != 200 {
+		t.Fatalf("cash %v, want 200", cash)
+	}
+}
+
+func TestAccountPnL(t *testing.T) {
+	a := fundedAccount(1_000)
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 5, Price: 100})
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 2, Price: 110})
+	if r := a.RealizedPnL(); math.Abs(r-20) > 1e-9 {
+		t.Fatalf("realized %v, want 20", r)
+	}
+	a.mark(map[string]float64{"X": 120})
+	// 3 left marked 60 above entry plus the 20 realized.
+	if pnl := a.PnL(); math.Abs(pnl-80) > 1e-9 {
+		t.Fatalf("PnL %v, want 80", pnl)
+	}
+	if nav := a.NAV(); math.Abs(nav-1_080) > 1e-9 {
+		t.Fatalf("NAV %v, want 1080", nav)
+	}
+
+	// Shorts credit the cash and lose when the price rises.
+	_ = a.Submit(types.Order{Symbol: "Y", Side: types.Sell, Qty: 1, Price: 50})
+	a.mark(map[string]float64{"Y": 60})
+	if pnl := a.PnL(); math.Abs(pnl-70) > 1e-9 {
+		t.Fatalf("PnL %v, want 70 with the short 10 under water", pnl)
+	}
+}
+
+func TestAccountTransferKeepsPositions(t *testing.T) {
+	a := fundedAccount(1_000)
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 9, Price: 100})
+	if moved := a.transfer(-500); moved != -100 {
+		t.Fatalf("only the 100 of cash may be withdrawn, moved %v", moved)
+	}
+	if a.Equity() != 0 || a.PnL() != 0 {
+		t.Fatalf("cash %v PnL %v, want 0 and 0", a.Equity(), a.PnL())
+	}
+}
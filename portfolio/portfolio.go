@@ -0,0 +1,388 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/metrics"
+	"github.com/evdnx/gots/types"
+)
+
+// AllocRule selects how a Portfolio splits capital between its accounts.
+type AllocRule string
+
+const (
+	// AllocFixed splits capital in proportion to the account weights.
+	AllocFixed AllocRule = "fixed"
+	// AllocRiskBudget treats the weights as risk budgets: every account
+	// gets capital in proportion to its weight over the volatility of its
+	// returns.
+	AllocRiskBudget AllocRule = "risk_budget"
+	// AllocPerformance scales the weights by the Sharpe ratio of each
+	// account's returns, floored at 0.
+	AllocPerformance AllocRule = "performance"
+)
+
+// Config tunes the capital allocation of a Portfolio.
+type Config struct {
+	Rule AllocRule
+	// Window is the number of per‑bar account returns the risk‑budget and
+	// performance rules look at; fixed weights apply until every account
+	// has that many.
+	Window int
+	// RebalanceBars reallocates capital every so many bars of OnBar; 0
+	// only allocates when an account is added or Reallocate is called.
+	RebalanceBars int
+	// MinShare in [0, 1] is the part of every account's fixed‑weight share
+	// it keeps whatever the rule, so an account starved by the performance
+	// rule can still earn its way back.
+	MinShare float64
+}
+
+// DefaultConfig allocates by fixed weights over a 50‑bar window and keeps
+// a tenth of every fixed share.
+func DefaultConfig() Config {
+	return Config{Rule: AllocFixed, Window: 50, MinShare: 0.1}
+}
+
+// Validate checks the allocation settings.
+func (pc Config) Validate() error {
+	switch pc.Rule {
+	case AllocFixed, AllocRiskBudget, AllocPerformance:
+	default:
+		return fmt.Errorf("unknown allocation rule %q", pc.Rule)
+	}
+	if pc.Window < 2 || pc.Window > 1024 {
+		return fmt.Errorf("Window (%d) must be between 2 and 1024", pc.Window)
+	}
+	if pc.RebalanceBars < 0 {
+		return fmt.Errorf("RebalanceBars (%d) cannot be negative", pc.RebalanceBars)
+	}
+	if pc.MinShare < 0 || pc.MinShare > 1 {
+		return fmt.Errorf("MinShare (%f) must be in [0, 1]", pc.MinShare)
+	}
+	return nil
+}
+
+// AccountReport summarises an account.
+type AccountReport struct {
+	Name        string
+	Weight      float64
+	Share       float64 // share of capital after the last allocation
+	Cash        float64
+	NAV         float64
+	PnL         float64
+	RealizedPnL float64
+}
+
+// Portfolio runs several strategies on one real executor through
+// sub‑accounts.  Strategies are built on the accounts returned by Account;
+// the back‑test or live loop hands every bar to the strategies first and
+// then calls OnBar, which marks the accounts, submits one order per symbol
+// to bring the real position to the sum of the account positions, and
+// reallocates capital when due.  Opposite positions of two accounts in the
+// same symbol thus net out instead of stomping each other.  It is safe for
+// concurrent use.
+type Portfolio struct {
+	Exec executor.Executor
+	Log  logger.Logger
+	// Cfg supplies the exchange MinQty below which net differences are
+	// left alone.
+	Cfg config.StrategyConfig
+	pc  Config
+
+	mu       sync.Mutex
+	accounts []*Account
+	shares   map[string]float64
+	reserve  float64 // capital not allocated to any account
+	bars     int
+}
+
+// New returns a portfolio without accounts whose capital is the current
+// equity of exec.
+func New(exec executor.Executor, cfg config.StrategyConfig, pc Config, log logger.Logger) (*Portfolio, error) {
+	if exec == nil {
+		return nil, fmt.Errorf("portfolio needs an executor")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := pc.Validate(); err != nil {
+		return nil, err
+	}
+	return &Portfolio{
+		Exec:    exec,
+		Log:     log,
+		Cfg:     cfg,
+		pc:      pc,
+		shares:  make(map[string]float64),
+		reserve: exec.Equity(),
+	}, nil
+}
+
+// Account adds a sub‑account with the given positive weight and
+// reallocates capital across all accounts.
+func (p *Portfolio) Account(name string, weight float64) (*Account, error) {
+	if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return nil, fmt.Errorf("account %q needs a positive weight", name)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, a := range p.accounts {
+		if a.name == name {
+			return nil, fmt.Errorf("account %q already exists", name)
+		}
+	}
+	a := newAccount(name, weight)
+	p.accounts = append(p.accounts, a)
+	p.reallocate()
+	return a, nil
+}
+
+// OnBar marks every account at closes, nets the account positions onto
+// the real executor at those closes and reallocates every RebalanceBars
+// bars.  Symbols without a close keep their previous mark and are not
+// netted.
+func (p *Portfolio) OnBar(closes map[string]float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, a := range p.accounts {
+		a.mark(closes)
+		a.recordReturn(p.pc.Window)
+	}
+	p.net(closes)
+	p.bars++
+	if p.pc.RebalanceBars > 0 && p.bars%p.pc.RebalanceBars == 0 {
+		p.reallocate()
+	}
+	for _, a := range p.accounts {
+		metrics.AccountEquity.WithLabelValues(a.name).Set(a.NAV())
+	}
+}
+
+// Reallocate splits capital according to the rule now.
+func (p *Portfolio) Reallocate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reallocate()
+}
+
+// NAV returns the unallocated capital plus the NAV of every account.
+func (p *Portfolio) NAV() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.nav()
+}
+
+func (p *Portfolio) nav() float64 {
+	total := p.reserve
+	for _, a := range p.accounts {
+		total += a.NAV()
+	}
+	return total
+}
+
+// Reports returns a summary of every account in the order they were added.
+func (p *Portfolio) Reports() []AccountReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]AccountReport, 0, len(p.accounts))
+	for _, a := range p.accounts {
+		out = append(out, AccountReport{
+			Name:        a.name,
+			Weight:      a.weight,
+			Share:       p.shares[a.name],
+			Cash:        a.Equity(),
+			NAV:         a.NAV(),
+			PnL:         a.PnL(),
+			RealizedPnL: a.RealizedPnL(),
+		})
+	}
+	return out
+}
+
+// net submits, per symbol, the difference between the summed account
+// positions and the real position.
+func (p *Portfolio) net(closes map[string]float64) {
+	seen := map[string]bool{}
+	var symbols []string
+	for _, a := range p.accounts {
+		for _, sym := range a.symbols() {
+			if !seen[sym] {
+				seen[sym] = true
+				symbols = append(symbols, sym)
+			}
+		}
+	}
+	sort.Strings(symbols)
+	for _, sym := range symbols {
+		price := closes[sym]
+		if price <= 0 {
+			continue
+		}
+		want := 0.0
+		for _, a := range p.accounts {
+			qty, _ := a.Position(sym)
+			want += qty
+		}
+		held, _ := p.Exec.Position(sym)
+		delta := want - held
+		// Account positions are on the exchange grid already, so only
+		// float noise and dust below MinQty are left alone.
+		if math.Abs(delta) < 1e-9 || (want != 0 && math.Abs(delta) < p.Cfg.MinQty) {
+			continue
+		}
+		side := types.Buy
+		if delta < 0 {
+			side = types.Sell
+		}
+		o := types.Order{Symbol: sym, Side: side, Qty: math.Abs(delta), Price: price, Comment: "portfolio_net"}
+		if err := p.Exec.Submit(o); err != nil {
+			p.Log.Error("order_submit_failed",
+				logger.String("symbol", o.Symbol),
+				logger.String("side", string(o.Side)),
+				logger.Float64("qty", o.Qty),
+				logger.Err(err),
+			)
+			continue
+		}
+		p.Log.Info("order_submitted",
+			logger.String("symbol", o.Symbol),
+			logger.String("side", string(o.Side)),
+			logger.Float64("qty", o.Qty),
+			logger.Float64("price", o.Price),
+			logger.String("ctx", o.Comment),
+			logger.Float64("target", want),
+		)
+		metrics.OrdersSubmitted.WithLabelValues(o.Comment).Inc()
+	}
+}
+
+// reallocate moves cash so that every account's NAV approaches its share
+// of the portfolio NAV.  Cash tied up in positions cannot be withdrawn, so
+// withdrawals are limited to an account's cash and deposits to what the
+// withdrawals and the reserve provide.
+func (p *Portfolio) reallocate() {
+	if len(p.accounts) == 0 {
+		return
+	}
+	p.shares = p.computeShares()
+	total := p.nav()
+	transfers := make([]float64, len(p.accounts))
+	for i, a := range p.accounts {
+		transfers[i] = p.shares[a.name]*total - a.NAV()
+	}
+	deposits := 0.0
+	for i, a := range p.accounts {
+		if transfers[i] < 0 {
+			p.reserve -= a.transfer(transfers[i])
+		} else {
+			deposits += transfers[i]
+		}
+	}
+	scale := 1.0
+	if deposits > p.reserve {
+		scale = math.Max(p.reserve, 0) / deposits
+	}
+	for i, a := range p.accounts {
+		if transfers[i] > 0 {
+			p.reserve -= a.transfer(transfers[i] * scale)
+		}
+	}
+	p.Log.Info("portfolio_reallocated",
+		logger.String("rule", string(p.pc.Rule)),
+		logger.Any("shares", p.shares),
+		logger.Float64("nav", total),
+		logger.Float64("reserve", p.reserve),
+	)
+}
+
+// computeShares applies the allocation rule, blending in MinShare of the
+// fixed shares.
+func (p *Portfolio) computeShares() map[string]float64 {
+	fixed := make([]float64, len(p.accounts))
+	for i, a := range p.accounts {
+		fixed[i] = a.weight
+	}
+	normalize(fixed)
+
+	rule := fixed
+	switch p.pc.Rule {
+	case AllocRiskBudget:
+		rule = p.riskBudgetShares()
+	case AllocPerformance:
+		rule = p.performanceShares()
+	}
+	if rule == nil {
+		rule = fixed
+	}
+	shares := make(map[string]float64, len(p.accounts))
+	for i, a := range p.accounts {
+		shares[a.name] = p.pc.MinShare*fixed[i] + (1-p.pc.MinShare)*rule[i]
+	}
+	return shares
+}
+
+// riskBudgetShares weighs every account by its budget over its volatility.
+// Accounts without a volatility yet (idle or too new) are assumed to have
+// the mean volatility of the others; nil means no account has one.
+func (p *Portfolio) riskBudgetShares() []float64 {
+	vols := make([]float64, len(p.accounts))
+	known, sum := 0, 0.0
+	for i, a := range p.accounts {
+		if _, sd, ok := a.stats(p.pc.Window); ok && sd > 0 {
+			vols[i] = sd
+			known++
+			sum += sd
+		}
+	}
+	if known == 0 {
+		return nil
+	}
+	raw := make([]float64, len(p.accounts))
+	for i, a := range p.accounts {
+		if vols[i] == 0 {
+			vols[i] = sum / float64(known)
+		}
+		raw[i] = a.weight / vols[i]
+	}
+	return normalize(raw)
+}
+
+// performanceShares weighs every account by its weight times its Sharpe
+// ratio floored at 0; nil means the window is not full for every account
+// or no account performed.
+func (p *Portfolio) performanceShares() []float64 {
+	raw := make([]float64, len(p.accounts))
+	for i, a := range p.accounts {
+		mean, sd, ok := a.stats(p.pc.Window)
+		if !ok {
+			return nil
+		}
+		if sd > 0 && mean > 0 {
+			raw[i] = a.weight * mean / sd
+		}
+	}
+	return normalize(raw)
+}
+
+// normalize scales v in place to sum to 1 and returns it, or nil when the
+// sum is not positive.
+func normalize(v []float64) []float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x
+	}
+	if sum <= 0 {
+		return nil
+	}
+	for i := range v {
+		v[i] /= sum
+	}
+	return v
+}
@@ -0,0 +1,216 @@
+package portfolio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/strategy"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+// testConfig mirrors the strategy tests: inverted RSI/MFI thresholds so
+// that only the price‑based logic decides, no hard stop, 2 dp quantities.
+func testConfig() config.StrategyConfig {
+	return config.StrategyConfig{
+		RSIOverbought:     -1e9,
+		RSIOversold:       1e9,
+		MFIOverbought:     -1e9,
+		MFIOversold:       1e9,
+		VWAOStrongTrend:   1e9,
+		HMAPeriod:         9,
+		ATSEMAperiod:      5,
+		MaxRiskPerTrade:   0.01,
+		StopLossPct:       0.015,
+		StopType:          config.StopNone,
+		QuantityPrecision: 2,
+		MinQty:            0.001,
+		StepSize:          0.0001,
+	}
+}
+
+func newPortfolio(t *testing.T, pc Config) (*Portfolio, *testutils.MockExecutor) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	p, err := New(exec, testConfig(), pc, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return p, exec
+}
+
+func addAccount(t *testing.T, p *Portfolio, name string, weight float64) *Account {
+	t.Helper()
+	a, err := p.Account(name, weight)
+	if err != nil {
+		t.Fatalf("Account failed: %v", err)
+	}
+	return a
+}
+
+func TestPortfolioFixedAllocation(t *testing.T) {
+	p, _ := newPortfolio(t, DefaultConfig())
+	a := addAccount(t, p, "trend", 3)
+	b := addAccount(t, p, "revert", 1)
+	if a.Equity() != 7_500 || b.Equity() != 2_500 {
+		t.Fatalf("cash %v/%v, want 7500/2500", a.Equity(), b.Equity())
+	}
+	if nav := p.NAV(); math.Abs(nav-10_000) > 1e-9 {
+		t.Fatalf("allocation must conserve capital, NAV %v", nav)
+	}
+	r := p.Reports()
+	if len(r) != 2 || r[0].Name != "trend" || r[0].Share != 0.75 || r[1].Share != 0.25 {
+		t.Fatalf("unexpected reports %+v", r)
+	}
+	if _, err := p.Account("trend", 1); err == nil {
+		t.Fatal("expected a duplicate account name to be rejected")
+	}
+	if _, err := p.Account("other", 0); err == nil {
+		t.Fatal("expected a zero weight to be rejected")
+	}
+}
+
+func TestPortfolioNetsOppositePositions(t *testing.T) {
+	p, exec := newPortfolio(t, DefaultConfig())
+	a := addAccount(t, p, "a", 1)
+	b := addAccount(t, p, "b", 1)
+
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Buy, Qty: 10, Price: 100})
+	_ = b.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 4, Price: 100})
+	p.OnBar(map[string]float64{"X": 101})
+	orders := exec.Orders()
+	if len(orders) != 1 || orders[0].Side != types.Buy || orders[0].Qty != 6 || orders[0].Comment != "portfolio_net" {
+		t.Fatalf("expected one net buy of 6, got %+v", orders)
+	}
+
+	p.OnBar(map[string]float64{"X": 102})
+	if n := len(exec.Orders()); n != 1 {
+		t.Fatalf("an unchanged net position must not trade, got %+v", exec.Orders())
+	}
+
+	_ = a.Submit(types.Order{Symbol: "X", Side: types.Sell, Qty: 10, Price: 102})
+	p.OnBar(map[string]float64{"X": 102})
+	if pos, _ := exec.Position("X"); pos != -4 {
+		t.Fatalf("real position %v, want b's short of 4", pos)
+	}
+	if pnl := p.Reports()[0].PnL; math.Abs(pnl-20) > 1e-9 {
+		t.Fatalf("account a PnL %v, want 20", pnl)
+	}
+}
+
+func TestPortfolioStrategiesSizeOffTheirAllocation(t *testing.T) {
+	p, exec := newPortfolio(t, DefaultConfig())
+	cfg := testConfig()
+	var strats []*strategy.TrendComposite
+	for _, acct := range []*Account{addAccount(t, p, "big", 3), addAccount(t, p, "small", 1)} {
+		tc, err := strategy.NewTrendComposite("X", cfg, acct, testutils.NewMockLogger())
+		if err != nil {
+			t.Fatalf("NewTrendComposite failed: %v", err)
+		}
+		strats = append(strats, tc)
+	}
+	for i := 1; i <= 15; i++ {
+		price := 100 + float64(i)
+		for _, s := range strats {
+			s.ProcessBar(price+0.5, price-0.5, price, 1000)
+		}
+		p.OnBar(map[string]float64{"X": price})
+	}
+	big, _ := strats[0].Exec.Position("X")
+	small, _ := strats[1].Exec.Position("X")
+	if big <= 0 || small <= 0 || math.Abs(big/small-3) > 0.05 {
+		t.Fatalf("positions %v and %v should follow the 3:1 allocation", big, small)
+	}
+	if real, _ := exec.Position("X"); math.Abs(real-(big+small)) > 1e-9 {
+		t.Fatalf("real position %v, want the net %v", real, big+small)
+	}
+}
+
+// holdAndMark submits every holding and then hands the price paths, all
+// of one length, to OnBar bar by bar.
+func holdAndMark(p *Portfolio, holdings map[*Account]types.Order, paths map[string][]float64) {
+	for a, o := range holdings {
+		_ = a.Submit(o)
+	}
+	bars := 0
+	for _, path := range paths {
+		bars = len(path)
+	}
+	for i := range bars {
+		closes := map[string]float64{}
+		for sym, path := range paths {
+			closes[sym] = path[i]
+		}
+		p.OnBar(closes)
+	}
+}
+
+func zigzag(n int, swing float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = 100 * (1 + swing*float64(i%2))
+	}
+	return out
+}
+
+func TestPortfolioRiskBudget(t *testing.T) {
+	p, _ := newPortfolio(t, Config{Rule: AllocRiskBudget, Window: 5, RebalanceBars: 8})
+	wild := addAccount(t, p, "wild", 1)
+	calm := addAccount(t, p, "calm", 1)
+	holdAndMark(p, map[*Account]types.Order{
+		wild: {Symbol: "W", Side: types.Buy, Qty: 10, Price: 100},
+		calm: {Symbol: "C", Side: types.Buy, Qty: 10, Price: 100},
+	}, map[string][]float64{"W": zigzag(8, 0.05), "C": zigzag(8, 0.01)})
+
+	r := p.Reports()
+	if r[0].Share >= r[1].Share || r[1].Share < 0.75 {
+		t.Fatalf("the calm account must get the larger share, got %+v", r)
+	}
+	if calm.Equity() <= wild.Equity() {
+		t.Fatalf("capital must move to the calm account, cash %v vs %v", calm.Equity(), wild.Equity())
+	}
+}
+
+func TestPortfolioPerformanceReallocation(t *testing.T) {
+	p, _ := newPortfolio(t, Config{Rule: AllocPerformance, Window: 5, RebalanceBars: 8, MinShare: 0.2})
+	winner := addAccount(t, p, "winner", 1)
+	loser := addAccount(t, p, "loser", 1)
+	rally := make([]float64, 8)
+	for i := range rally {
+		rally[i] = 100 * (1 + 0.01*float64(i) + 0.002*float64(i%2))
+	}
+	holdAndMark(p, map[*Account]types.Order{
+		winner: {Symbol: "X", Side: types.Buy, Qty: 10, Price: 100},
+		loser:  {Symbol: "X", Side: types.Sell, Qty: 10, Price: 100},
+	}, map[string][]float64{"X": rally})
+
+	r := p.Reports()
+	// The loser keeps MinShare of its fixed half.
+	if math.Abs(r[1].Share-0.1) > 1e-9 || math.Abs(r[0].Share-0.9) > 1e-9 {
+		t.Fatalf("shares %v/%v, want 0.9/0.1", r[0].Share, r[1].Share)
+	}
+	if r[0].PnL <= 0 || r[1].PnL >= 0 {
+		t.Fatalf("unexpected P&L %+v", r)
+	}
+}
+
+func TestPortfolioConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []Config{
+		{Rule: "equal", Window: 10},
+		{Rule: AllocFixed, Window: 1},
+		{Rule: AllocFixed, Window: 10, RebalanceBars: -1},
+		{Rule: AllocFixed, Window: 10, MinShare: 1.5},
+	}
+	for i, pc := range bad {
+		if err := pc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, pc)
+		}
+	}
+	if _, err := New(nil, testConfig(), DefaultConfig(), testutils.NewMockLogger()); err == nil {
+		t.Fatal("expected an error without an executor")
+	}
+}
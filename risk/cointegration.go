@@ -0,0 +1,237 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/evdnx/gots/config"
+)
+
+// Engle–Granger critical values of the residual ADF statistic for two
+// variables with a constant (MacKinnon, asymptotic).  A statistic below the
+// critical value rejects "no cointegration" at that level.
+const (
+	EngleGrangerCritical1  = -3.90
+	EngleGrangerCritical5  = -3.34
+	EngleGrangerCritical10 = -3.04
+)
+
+// OLS fits y = alpha + beta·x by ordinary least squares.
+func OLS(y, x []float64) (alpha, beta float64, err error) {
+	n := len(y)
+	if n != len(x) {
+		return 0, 0, fmt.Errorf("OLS: series lengths differ (%d vs %d)", len(y), len(x))
+	}
+	if n < 3 {
+		return 0, 0, fmt.Errorf("OLS: need at least 3 points, have %d", n)
+	}
+	var mx, my float64
+	for i := range y {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= float64(n)
+	my /= float64(n)
+	var sxx, sxy float64
+	for i := range y {
+		sxx += (x[i] - mx) * (x[i] - mx)
+		sxy += (x[i] - mx) * (y[i] - my)
+	}
+	if sxx == 0 {
+		return 0, 0, errors.New("OLS: x is constant")
+	}
+	beta = sxy / sxx
+	return my - beta*mx, beta, nil
+}
+
+// ADFStat returns the augmented Dickey–Fuller t‑statistic of series:
+//
+//	Δsₜ = γ·sₜ₋₁ + Σᵢ φᵢ·Δsₜ₋ᵢ + εₜ   (i = 1…lags)
+//
+// without constant or trend, as applied to cointegration residuals.  The
+// more negative the statistic, the stronger the evidence of mean reversion.
+func ADFStat(series []float64, lags int) (float64, error) {
+	if lags < 0 {
+		return 0, fmt.Errorf("ADF: negative lags %d", lags)
+	}
+	k := lags + 1
+	rows := len(series) - 1 - lags
+	if rows < k+2 {
+		return 0, fmt.Errorf("ADF: need at least %d points, have %d", k+3+lags, len(series))
+	}
+	diff := make([]float64, len(series)-1)
+	for i := range diff {
+		diff[i] = series[i+1] - series[i]
+	}
+	X := make([][]float64, rows)
+	Y := make([]float64, rows)
+	for r := 0; r < rows; r++ {
+		t := r + lags // index into diff of Δsₜ
+		row := make([]float64, k)
+		row[0] = series[t]
+		for i := 1; i <= lags; i++ {
+			row[i] = diff[t-i]
+		}
+		X[r] = row
+		Y[r] = diff[t]
+	}
+	coef, se, err := leastSquares(X, Y)
+	if err != nil {
+		return 0, fmt.Errorf("ADF: %w", err)
+	}
+	if se[0] == 0 {
+		return 0, errors.New("ADF: zero standard error")
+	}
+	return coef[0] / se[0], nil
+}
+
+// EngleGrangerResult is the outcome of an Engle–Granger cointegration test.
+type EngleGrangerResult struct {
+	Alpha, Beta float64 // cointegrating regression y = Alpha + Beta·x
+	Stat        float64 // ADF statistic of the residuals
+}
+
+// Cointegrated reports whether Stat rejects "no cointegration" at the
+// supplied critical value (e.g. EngleGrangerCritical5).
+func (r EngleGrangerResult) Cointegrated(critical float64) bool {
+	return r.Stat < critical
+}
+
+// EngleGranger runs the two‑step Engle–Granger test: an OLS regression of
+// y on x followed by an ADF test with lags on its residuals.
+func EngleGranger(y, x []float64, lags int) (EngleGrangerResult, error) {
+	alpha, beta, err := OLS(y, x)
+	if err != nil {
+		return EngleGrangerResult{}, err
+	}
+	resid := make([]float64, len(y))
+	for i := range y {
+		resid[i] = y[i] - alpha - beta*x[i]
+	}
+	stat, err := ADFStat(resid, lags)
+	if err != nil {
+		return EngleGrangerResult{}, err
+	}
+	return EngleGrangerResult{Alpha: alpha, Beta: beta, Stat: stat}, nil
+}
+
+// leastSquares solves Y = X·b by the normal equations and returns b with
+// the standard error of each coefficient.
+func leastSquares(X [][]float64, Y []float64) (coef, se []float64, err error) {
+	n, k := len(X), len(X[0])
+	xtx := make([][]float64, k)
+	xty := make([]float64, k)
+	for i := range xtx {
+		xtx[i] = make([]float64, k)
+	}
+	for r := 0; r < n; r++ {
+		for i := 0; i < k; i++ {
+			xty[i] += X[r][i] * Y[r]
+			for j := 0; j < k; j++ {
+				xtx[i][j] += X[r][i] * X[r][j]
+			}
+		}
+	}
+	inv, err := invert(xtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	coef = make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			coef[i] += inv[i][j] * xty[j]
+		}
+	}
+	rss := 0.0
+	for r := 0; r < n; r++ {
+		fit := 0.0
+		for i := 0; i < k; i++ {
+			fit += X[r][i] * coef[i]
+		}
+		rss += (Y[r] - fit) * (Y[r] - fit)
+	}
+	sigma2 := rss / float64(n-k)
+	se = make([]float64, k)
+	for i := range se {
+		se[i] = math.Sqrt(sigma2 * inv[i][i])
+	}
+	return coef, se, nil
+}
+
+// invert returns the inverse of a square matrix by Gauss–Jordan
+// elimination with partial pivoting.
+func invert(m [][]float64) ([][]float64, error) {
+	k := len(m)
+	a := make([][]float64, k)
+	for i := range m {
+		a[i] = make([]float64, 2*k)
+		copy(a[i], m[i])
+		a[i][k+i] = 1
+	}
+	for c := 0; c < k; c++ {
+		p := c
+		for r := c + 1; r < k; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[p][c]) {
+				p = r
+			}
+		}
+		if math.Abs(a[p][c]) < 1e-12 {
+			return nil, errors.New("singular matrix")
+		}
+		a[c], a[p] = a[p], a[c]
+		pv := a[c][c]
+		for j := range a[c] {
+			a[c][j] /= pv
+		}
+		for r := 0; r < k; r++ {
+			if r == c || a[r][c] == 0 {
+				continue
+			}
+			f := a[r][c]
+			for j := range a[r] {
+				a[r][j] -= f * a[c][j]
+			}
+		}
+	}
+	inv := make([][]float64, k)
+	for i := range a {
+		inv[i] = a[i][k:]
+	}
+	return inv, nil
+}
+
+// HedgeMode selects how the two legs of a pair are balanced.
+type HedgeMode string
+
+const (
+	// HedgeDollarNeutral puts the same notional on both legs.
+	HedgeDollarNeutral HedgeMode = "dollar"
+	// HedgeBetaNeutral holds |beta| units of x per unit of y, so the
+	// position tracks the spread y − beta·x.
+	HedgeBetaNeutral HedgeMode = "beta"
+)
+
+// PairQuantities splits a gross notional (|y leg| + |x leg|) across the legs
+// of the spread y − beta·x and returns the absolute leg quantities rounded
+// with RoundQty.  Both legs are zero when either rounds away, so a pair is
+// never left unhedged.
+func PairQuantities(gross, priceY, priceX, beta float64, mode HedgeMode, cfg config.StrategyConfig) (qtyY, qtyX float64) {
+	if gross <= 0 || priceY <= 0 || priceX <= 0 {
+		return 0, 0
+	}
+	switch mode {
+	case HedgeBetaNeutral:
+		b := math.Abs(beta)
+		qtyY = gross / (priceY + b*priceX)
+		qtyX = b * qtyY
+	default:
+		qtyY = gross / 2 / priceY
+		qtyX = gross / 2 / priceX
+	}
+	qtyY, qtyX = RoundQty(qtyY, cfg), RoundQty(qtyX, cfg)
+	if qtyY == 0 || qtyX == 0 {
+		return 0, 0
+	}
+	return qtyY, qtyX
+}
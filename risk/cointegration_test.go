@@ -0,0 +1,82 @@
+package risk
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+)
+
+func TestOLSRecoversLine(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = 3 + 1.5*v
+	}
+	a, b, err := OLS(y, x)
+	if err != nil {
+		t.Fatalf("OLS failed: %v", err)
+	}
+	if math.Abs(a-3) > 1e-12 || math.Abs(b-1.5) > 1e-12 {
+		t.Fatalf("got alpha %v beta %v, want 3 and 1.5", a, b)
+	}
+	if _, _, err := OLS([]float64{1, 2, 3}, []float64{2, 2, 2}); err == nil {
+		t.Fatal("expected an error for constant x")
+	}
+}
+
+func TestEngleGrangerSeparatesPairs(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	n := 300
+	x := make([]float64, n)
+	walk := make([]float64, n)
+	coint := make([]float64, n)
+	x[0], walk[0] = 100, 50
+	for i := 1; i < n; i++ {
+		x[i] = x[i-1] + rng.NormFloat64()
+		walk[i] = walk[i-1] + rng.NormFloat64()
+	}
+	for i := range x {
+		coint[i] = 5 + 2*x[i] + rng.NormFloat64()
+	}
+
+	res, err := EngleGranger(coint, x, 1)
+	if err != nil {
+		t.Fatalf("EngleGranger failed: %v", err)
+	}
+	if !res.Cointegrated(EngleGrangerCritical1) {
+		t.Fatalf("stationary spread not detected: stat %v", res.Stat)
+	}
+	if math.Abs(res.Beta-2) > 0.05 {
+		t.Fatalf("hedge ratio %v, want ≈2", res.Beta)
+	}
+
+	res, err = EngleGranger(walk, x, 1)
+	if err != nil {
+		t.Fatalf("EngleGranger failed: %v", err)
+	}
+	if res.Cointegrated(EngleGrangerCritical10) {
+		t.Fatalf("independent random walks reported cointegrated: stat %v", res.Stat)
+	}
+}
+
+func TestPairQuantities(t *testing.T) {
+	cfg := config.StrategyConfig{QuantityPrecision: 4, StepSize: 0.0001, MinQty: 0.001}
+
+	y, x := PairQuantities(10_000, 100, 50, 2, HedgeDollarNeutral, cfg)
+	if y != 50 || x != 100 {
+		t.Fatalf("dollar neutral: got %v/%v, want 50/100", y, x)
+	}
+
+	// Beta neutral: qY·100 + 2·qY·50 = 10 000 → qY = 50, qX = 100.
+	y, x = PairQuantities(10_000, 100, 50, -2, HedgeBetaNeutral, cfg)
+	if y != 50 || x != 100 {
+		t.Fatalf("beta neutral: got %v/%v, want 50/100", y, x)
+	}
+
+	// A leg that rounds away leaves the pair flat.
+	if y, x := PairQuantities(10_000, 100, 50, 1e-9, HedgeBetaNeutral, cfg); y != 0 || x != 0 {
+		t.Fatalf("unhedgeable pair must be flat, got %v/%v", y, x)
+	}
+}
@@ -0,0 +1,152 @@
+package risk
+
+import (
+	"math"
+	"sync"
+
+	"github.com/evdnx/gots/config"
+)
+
+// Defaults applied when the Kelly‑related config fields are left at zero.
+const (
+	DefaultKellyFraction  = 0.5                          // half Kelly
+	DefaultKellyMinTrades = config.DefaultKellyMinTrades // closed trades before the estimate is trusted
+	DefaultKellyMaxRisk   = 0.05                         // never risk more than 5 % of equity
+	DefaultKellyLookback  = config.DefaultKellyLookback  // rolling window of closed trades
+)
+
+// TradeHistory keeps a rolling window of closed‑trade returns expressed as a
+// fraction of the entry price (0.02 = +2 %, -0.01 = -1 %).  It is safe for
+// concurrent use.
+type TradeHistory struct {
+	mu      sync.RWMutex
+	max     int
+	returns []float64
+}
+
+// NewTradeHistory returns an empty history that keeps at most max trades
+// (DefaultKellyLookback when max <= 0).
+func NewTradeHistory(max int) *TradeHistory {
+	if max <= 0 {
+		max = DefaultKellyLookback
+	}
+	return &TradeHistory{max: max}
+}
+
+// Record appends a closed‑trade return and drops the oldest when full.
+func (h *TradeHistory) Record(ret float64) {
+	if math.IsNaN(ret) || math.IsInf(ret, 0) {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.returns = append(h.returns, ret)
+	if len(h.returns) > h.max {
+		h.returns = h.returns[len(h.returns)-h.max:]
+	}
+}
+
+// Len returns the number of trades currently in the window.
+func (h *TradeHistory) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.returns)
+}
+
+// Returns returns a copy of the recorded trade returns, oldest first.
+func (h *TradeHistory) Returns() []float64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]float64, len(h.returns))
+	copy(out, h.returns)
+	return out
+}
+
+// Stats returns the win rate and the payoff ratio (average win divided by
+// average loss).  Break‑even trades count as neither.  The payoff ratio is
+// 0 when there are no wins or no losses.
+func (h *TradeHistory) Stats() (winRate, payoff float64) {
+	rets := h.Returns()
+	var wins, losses int
+	var sumWin, sumLoss float64
+	for _, r := range rets {
+		switch {
+		case r > 0:
+			wins++
+			sumWin += r
+		case r < 0:
+			losses++
+			sumLoss -= r
+		}
+	}
+	if wins+losses == 0 {
+		return 0, 0
+	}
+	winRate = float64(wins) / float64(wins+losses)
+	if wins == 0 || losses == 0 {
+		return winRate, 0
+	}
+	payoff = (sumWin / float64(wins)) / (sumLoss / float64(losses))
+	return winRate, payoff
+}
+
+// KellyFraction returns the growth‑optimal fraction of equity to risk,
+// f* = W − (1 − W) / R, using the win rate W and payoff ratio R of the
+// history.  A history with only winners yields 1; one without an edge
+// yields 0.
+func KellyFraction(h *TradeHistory) float64 {
+	w, r := h.Stats()
+	if w == 0 {
+		return 0
+	}
+	if r == 0 { // no losing trades recorded
+		return 1
+	}
+	return math.Max(w-(1-w)/r, 0)
+}
+
+// OptimalF returns Ralph Vince's optimal f: the fraction of equity per unit
+// of the worst historical loss that maximises the terminal wealth relative
+//
+//	TWR(f) = Π (1 + f · rᵢ / |worst loss|)
+//
+// together with the worst loss itself.  A coarse grid search followed by a
+// local refinement is sufficient for the smooth, unimodal TWR curve.  It
+// returns (0, 0) when the history contains no losing trade.
+func OptimalF(h *TradeHistory) (f, worstLoss float64) {
+	rets := h.Returns()
+	for _, r := range rets {
+		if r < worstLoss {
+			worstLoss = r
+		}
+	}
+	if worstLoss >= 0 {
+		return 0, 0
+	}
+	worstLoss = -worstLoss
+	logTWR := func(f float64) float64 {
+		sum := 0.0
+		for _, r := range rets {
+			hpr := 1 + f*r/worstLoss
+			if hpr <= 0 {
+				return math.Inf(-1)
+			}
+			sum += math.Log(hpr)
+		}
+		return sum
+	}
+	best, bestVal := 0.0, 0.0 // f = 0 → TWR = 1 → log TWR = 0
+	for step := 0.01; step >= 0.0001; step /= 10 {
+		lo := math.Max(best-10*step, step)
+		hi := math.Min(best+10*step, 1)
+		if best == 0 {
+			lo, hi = step, 1
+		}
+		for x := lo; x <= hi+1e-12; x += step {
+			if v := logTWR(x); v > bestVal {
+				best, bestVal = x, v
+			}
+		}
+	}
+	return best, worstLoss
+}
@@ -0,0 +1,85 @@
+package risk
+
+import (
+	"math"
+	"testing"
+)
+
+func historyOf(rets ...float64) *TradeHistory {
+	h := NewTradeHistory(len(rets))
+	for _, r := range rets {
+		h.Record(r)
+	}
+	return h
+}
+
+func TestTradeHistoryRollingWindow(t *testing.T) {
+	h := NewTradeHistory(3)
+	for _, r := range []float64{0.01, -0.02, 0.03, 0.04} {
+		h.Record(r)
+	}
+	got := h.Returns()
+	if len(got) != 3 || got[0] != -0.02 || got[2] != 0.04 {
+		t.Fatalf("unexpected window: %v", got)
+	}
+}
+
+func TestKellyFraction(t *testing.T) {
+	// 60 % winners at +2 %, 40 % losers at -1 % → W=0.6, R=2 → f*=0.4.
+	h := historyOf(0.02, 0.02, 0.02, -0.01, -0.01)
+	if f := KellyFraction(h); math.Abs(f-0.4) > 1e-9 {
+		t.Fatalf("expected Kelly 0.4, got %v", f)
+	}
+	// No edge → stand aside.
+	h = historyOf(0.01, -0.01, 0.01, -0.02)
+	if f := KellyFraction(h); f != 0 {
+		t.Fatalf("expected 0 for negative edge, got %v", f)
+	}
+}
+
+func TestOptimalF(t *testing.T) {
+	h := historyOf(0.02, 0.02, 0.02, -0.01, -0.01)
+	f, worst := OptimalF(h)
+	if worst != 0.01 {
+		t.Fatalf("expected worst loss 0.01, got %v", worst)
+	}
+	// For a two‑outcome bet with payoff 2:1 and W=0.6, optimal f equals Kelly.
+	if math.Abs(f-0.4) > 0.001 {
+		t.Fatalf("expected optimal f ≈0.4, got %v", f)
+	}
+	if f, _ := OptimalF(historyOf(0.01, 0.02)); f != 0 {
+		t.Fatalf("expected 0 without losing trades, got %v", f)
+	}
+}
+
+func TestKellySizerFallsBackWithoutHistory(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.KellyMinTrades = 5
+	in := SizeInput{Equity: 10_000, Price: 100, Trades: historyOf(0.02, -0.01)}
+	want := CalcQty(10_000, cfg.MaxRiskPerTrade, cfg.StopLossPct, 100, cfg)
+	if got := (Kelly{Cfg: cfg}).Qty(in); got != want {
+		t.Fatalf("expected fixed‑fractional fallback %v, got %v", want, got)
+	}
+	if got := (OptimalFSizer{Cfg: cfg}).Qty(in); got != want {
+		t.Fatalf("expected fixed‑fractional fallback %v, got %v", want, got)
+	}
+}
+
+func TestKellySizerScalesAndCaps(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.KellyMinTrades = 5
+	cfg.KellyFraction = 0.05 // 0.4 × 0.05 = 2 % risk
+	cfg.KellyMaxRisk = 0.5
+	in := SizeInput{Equity: 10_000, Price: 100, Trades: historyOf(0.02, 0.02, 0.02, -0.01, -0.01)}
+	want := CalcQty(10_000, 0.02, cfg.StopLossPct, 100, cfg)
+	if got := (Kelly{Cfg: cfg}).Qty(in); got != want {
+		t.Fatalf("expected half‑risk qty %v, got %v", want, got)
+	}
+
+	cfg.KellyFraction = 1
+	cfg.KellyMaxRisk = 0.01 // cap below the 40 % Kelly bet
+	want = CalcQty(10_000, 0.01, cfg.StopLossPct, 100, cfg)
+	if got := (Kelly{Cfg: cfg}).Qty(in); got != want {
+		t.Fatalf("expected capped qty %v, got %v", want, got)
+	}
+}
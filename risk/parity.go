@@ -0,0 +1,232 @@
+package risk
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// StdDev returns the sample standard deviation of xs (0 for fewer than two
+// observations).
+func StdDev(xs []float64) float64 {
+	n := len(xs)
+	if n < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(n)
+	ss := 0.0
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(ss / float64(n-1))
+}
+
+// Covariance returns the sample covariance matrix of the supplied return
+// series.  Only the most recent observations common to every series are
+// used, so series of different lengths are aligned on their tails.
+func Covariance(series [][]float64) [][]float64 {
+	k := len(series)
+	cov := make([][]float64, k)
+	for i := range cov {
+		cov[i] = make([]float64, k)
+	}
+	n := -1
+	for _, s := range series {
+		if n < 0 || len(s) < n {
+			n = len(s)
+		}
+	}
+	if n < 2 {
+		return cov
+	}
+	tails := make([][]float64, k)
+	means := make([]float64, k)
+	for i, s := range series {
+		tails[i] = s[len(s)-n:]
+		for _, v := range tails[i] {
+			means[i] += v
+		}
+		means[i] /= float64(n)
+	}
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			sum := 0.0
+			for t := 0; t < n; t++ {
+				sum += (tails[i][t] - means[i]) * (tails[j][t] - means[j])
+			}
+			cov[i][j] = sum / float64(n-1)
+			cov[j][i] = cov[i][j]
+		}
+	}
+	return cov
+}
+
+// InverseVolWeights returns weights proportional to 1/σᵢ that sum to 1.
+// Assets with a non‑positive volatility receive the median inverse
+// volatility of the others; with no usable estimate the weights are equal.
+func InverseVolWeights(vols []float64) []float64 {
+	n := len(vols)
+	w := make([]float64, n)
+	if n == 0 {
+		return w
+	}
+	var known []float64
+	for _, v := range vols {
+		if v > 0 && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			known = append(known, 1/v)
+		}
+	}
+	if len(known) == 0 {
+		for i := range w {
+			w[i] = 1 / float64(n)
+		}
+		return w
+	}
+	fill := median(known)
+	sum := 0.0
+	for i, v := range vols {
+		if v > 0 && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			w[i] = 1 / v
+		} else {
+			w[i] = fill
+		}
+		sum += w[i]
+	}
+	for i := range w {
+		w[i] /= sum
+	}
+	return w
+}
+
+// EqualRiskContribution solves for long‑only weights (summing to 1) whose
+// contributions wᵢ·(Σw)ᵢ to portfolio variance are equal, using cyclical
+// coordinate descent.  It returns an error when the covariance matrix is
+// malformed or the solver does not converge.
+func EqualRiskContribution(cov [][]float64) ([]float64, error) {
+	const (
+		maxIter = 500
+		tol     = 1e-10
+	)
+	n := len(cov)
+	if n == 0 {
+		return nil, errors.New("empty covariance matrix")
+	}
+	for i := range cov {
+		if len(cov[i]) != n {
+			return nil, errors.New("covariance matrix is not square")
+		}
+		if cov[i][i] <= 0 || math.IsNaN(cov[i][i]) {
+			return nil, errors.New("covariance matrix has a non‑positive variance")
+		}
+	}
+	// Start from inverse‑volatility weights.
+	vols := make([]float64, n)
+	for i := range vols {
+		vols[i] = math.Sqrt(cov[i][i])
+	}
+	x := InverseVolWeights(vols)
+	b := 1 / float64(n)
+	for iter := 0; iter < maxIter; iter++ {
+		maxDelta := 0.0
+		for i := 0; i < n; i++ {
+			cross := 0.0
+			for j := 0; j < n; j++ {
+				if j != i {
+					cross += cov[i][j] * x[j]
+				}
+			}
+			next := (-cross + math.Sqrt(cross*cross+4*cov[i][i]*b)) / (2 * cov[i][i])
+			maxDelta = math.Max(maxDelta, math.Abs(next-x[i]))
+			x[i] = next
+		}
+		if maxDelta < tol {
+			return normalize(x), nil
+		}
+	}
+	return nil, errors.New("equal risk contribution solver did not converge")
+}
+
+// CapWeights limits every weight to maxWeight and redistributes the excess
+// pro‑rata over the uncapped weights.  When maxWeight·len(w) < 1 the result
+// sums to less than one (the remainder stays uninvested).
+func CapWeights(w []float64, maxWeight float64) []float64 {
+	out := append([]float64(nil), w...)
+	if maxWeight <= 0 || maxWeight >= 1 {
+		return out
+	}
+	capped := make([]bool, len(out))
+	for {
+		excess, free := 0.0, 0.0
+		for i, v := range out {
+			if capped[i] {
+				continue
+			}
+			if v > maxWeight {
+				excess += v - maxWeight
+				out[i] = maxWeight
+				capped[i] = true
+			} else {
+				free += v
+			}
+		}
+		if excess <= 1e-12 || free <= 0 {
+			return out
+		}
+		for i := range out {
+			if !capped[i] {
+				out[i] += excess * out[i] / free
+			}
+		}
+	}
+}
+
+// RiskContributions returns each asset's share of portfolio variance
+// wᵢ·(Σw)ᵢ / w'Σw.
+func RiskContributions(w []float64, cov [][]float64) []float64 {
+	n := len(w)
+	rc := make([]float64, n)
+	total := 0.0
+	for i := 0; i < n; i++ {
+		m := 0.0
+		for j := 0; j < n; j++ {
+			m += cov[i][j] * w[j]
+		}
+		rc[i] = w[i] * m
+		total += rc[i]
+	}
+	if total <= 0 {
+		return rc
+	}
+	for i := range rc {
+		rc[i] /= total
+	}
+	return rc
+}
+
+func normalize(x []float64) []float64 {
+	sum := 0.0
+	for _, v := range x {
+		sum += v
+	}
+	if sum <= 0 {
+		return x
+	}
+	for i := range x {
+		x[i] /= sum
+	}
+	return x
+}
+
+func median(xs []float64) float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	m := len(s) / 2
+	if len(s)%2 == 1 {
+		return s[m]
+	}
+	return (s[m-1] + s[m]) / 2
+}
@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInverseVolWeights(t *testing.T) {
+	w := InverseVolWeights([]float64{0.01, 0.02, 0})
+	// 1/0.01 = 100, 1/0.02 = 50, missing → median(100, 50) = 75.
+	want := []float64{100.0 / 225, 50.0 / 225, 75.0 / 225}
+	for i := range w {
+		if math.Abs(w[i]-want[i]) > 1e-12 {
+			t.Fatalf("weight %d: got %v want %v", i, w[i], want[i])
+		}
+	}
+}
+
+func TestEqualRiskContributionEqualisesRisk(t *testing.T) {
+	cov := [][]float64{
+		{0.0004, 0.0002, 0.0000},
+		{0.0002, 0.0009, 0.0001},
+		{0.0000, 0.0001, 0.0025},
+	}
+	w, err := EqualRiskContribution(cov)
+	if err != nil {
+		t.Fatalf("solver failed: %v", err)
+	}
+	sum := 0.0
+	for _, v := range w {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("weights should sum to 1, got %v", sum)
+	}
+	for i, rc := range RiskContributions(w, cov) {
+		if math.Abs(rc-1.0/3) > 1e-6 {
+			t.Fatalf("risk contribution %d = %v, want 1/3", i, rc)
+		}
+	}
+	// The most volatile asset gets the smallest weight.
+	if !(w[0] > w[1] && w[1] > w[2]) {
+		t.Fatalf("unexpected weight ordering: %v", w)
+	}
+}
+
+func TestEqualRiskContributionRejectsBadMatrix(t *testing.T) {
+	if _, err := EqualRiskContribution([][]float64{{0, 0}, {0, 1}}); err == nil {
+		t.Fatal("expected error for zero variance")
+	}
+}
+
+func TestCapWeightsRedistributes(t *testing.T) {
+	w := CapWeights([]float64{0.7, 0.2, 0.1}, 0.4)
+	want := []float64{0.4, 0.4, 0.2}
+	for i := range w {
+		if math.Abs(w[i]-want[i]) > 1e-12 {
+			t.Fatalf("capped weights %v, want %v", w, want)
+		}
+	}
+}
+
+func TestCovarianceAlignsTails(t *testing.T) {
+	cov := Covariance([][]float64{
+		{9, 1, 2, 3},
+		{2, 4, 6},
+	})
+	if math.Abs(cov[0][0]-1) > 1e-12 || math.Abs(cov[1][1]-4) > 1e-12 || math.Abs(cov[0][1]-2) > 1e-12 {
+		t.Fatalf("unexpected covariance: %v", cov)
+	}
+}
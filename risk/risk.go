@@ -16,8 +16,15 @@ func CalcQty(equity, maxRisk, stopLossPct, price float64, cfg config.StrategyCon
 	if slDist <= 0 {
 		return 0
 	}
-	rawQty := riskAmt / slDist
+	return RoundQty(riskAmt/slDist, cfg)
+}
 
+// RoundQty floors a raw quantity to the exchange step‑size and precision and
+// returns 0 when the result is below the configured minimum quantity.
+func RoundQty(rawQty float64, cfg config.StrategyConfig) float64 {
+	if math.IsNaN(rawQty) || math.IsInf(rawQty, 0) || rawQty <= 0 {
+		return 0
+	}
 	// Apply step‑size rounding
 	if cfg.StepSize > 0 {
 		rawQty = math.Floor(rawQty/cfg.StepSize) * cfg.StepSize
@@ -33,3 +40,19 @@ func CalcQty(equity, maxRisk, stopLossPct, price float64, cfg config.StrategyCon
 	}
 	return rawQty
 }
+
+// InventoryLimit trims an order that changes the position by signedQty
+// (positive = buy) so that position+signedQty stays within [minPos, maxPos]
+// and returns the rounded absolute quantity that may be traded, 0 when the
+// bound is already reached.  position should include the effect of any
+// resting orders on the same side.
+func InventoryLimit(position, signedQty, minPos, maxPos float64, cfg config.StrategyConfig) float64 {
+	room := 0.0
+	switch {
+	case signedQty > 0:
+		room = math.Min(signedQty, maxPos-position)
+	case signedQty < 0:
+		room = math.Min(-signedQty, position-minPos)
+	}
+	return RoundQty(room, cfg)
+}
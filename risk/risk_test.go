@@ -1,6 +1,7 @@
 package risk
 
 import (
+	"math"
 	"testing"
 
 	"github.com/evdnx/gots/config"
@@ -42,3 +43,22 @@ func TestCalcQtyZeroStepSizePanicsSafe(t *testing.T) {
 		t.Fatalf("expected positive qty despite zero StepSize, got %v", qty)
 	}
 }
+
+func TestInventoryLimit(t *testing.T) {
+	cfg := config.StrategyConfig{StepSize: 0.1, QuantityPrecision: 1, MinQty: 0.1}
+	cases := []struct {
+		pos, qty, min, max, want float64
+	}{
+		{0, 2, -5, 5, 2},      // inside the band
+		{4, 2, -5, 5, 1},      // trimmed to the cap
+		{5, 2, -5, 5, 0},      // cap reached
+		{0, -2, 0, 5, 0},      // long‑only: no short
+		{1.5, -2, 0, 5, 1.5},  // long‑only: sells down to flat
+		{-4.95, -1, -5, 5, 0}, // room below the step size rounds away
+	}
+	for _, c := range cases {
+		if got := InventoryLimit(c.pos, c.qty, c.min, c.max, cfg); math.Abs(got-c.want) > 1e-9 {
+			t.Fatalf("InventoryLimit(%v, %v, %v, %v) = %v, want %v", c.pos, c.qty, c.min, c.max, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evdnx/gots/config"
+)
+
+// Default parameters used when the config leaves them at zero.
+const (
+	DefaultBarsPerYear     = 252.0
+	DefaultATRStopMultiple = 2.0
+)
+
+// SizeInput carries the market state a Sizer may need.  Fields a sizer does
+// not use can be left at zero.
+type SizeInput struct {
+	Equity     float64 // current account equity
+	Price      float64 // intended entry price
+	ATR        float64 // average true range in price units
+	Volatility float64 // realised per‑bar volatility of returns (e.g. 0.01 = 1 %)
+	// Trades is the strategy's own closed‑trade history (Kelly, optimal f).
+	Trades *TradeHistory
+}
+
+// Sizer converts a SizeInput into an order quantity that already respects
+// the exchange rounding rules (step‑size, precision, min‑qty).
+type Sizer interface {
+	Qty(in SizeInput) float64
+}
+
+// NewSizer builds the sizer selected by cfg.Sizing.  An empty method falls
+// back to fixed‑fractional sizing, which matches CalcQty.
+func NewSizer(cfg config.StrategyConfig) (Sizer, error) {
+	switch cfg.Sizing {
+	case "", config.SizingFixedFractional:
+		return FixedFractional{Cfg: cfg}, nil
+	case config.SizingFixedNotional:
+		return FixedNotional{Cfg: cfg}, nil
+	case config.SizingVolTarget:
+		return VolTarget{Cfg: cfg}, nil
+	case config.SizingATRStop:
+		return ATRStop{Cfg: cfg}, nil
+	case config.SizingKelly:
+		return Kelly{Cfg: cfg}, nil
+	case config.SizingOptimalF:
+		return OptimalFSizer{Cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown sizing method %q", cfg.Sizing)
+	}
+}
+
+// FixedFractional risks MaxRiskPerTrade of equity against a StopLossPct stop.
+type FixedFractional struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s FixedFractional) Qty(in SizeInput) float64 {
+	return CalcQty(in.Equity, s.Cfg.MaxRiskPerTrade, s.Cfg.StopLossPct, in.Price, s.Cfg)
+}
+
+// FixedNotional buys the same quote‑currency amount on every trade, capped
+// at the available equity.
+type FixedNotional struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s FixedNotional) Qty(in SizeInput) float64 {
+	if in.Price <= 0 {
+		return 0
+	}
+	notional := math.Min(s.Cfg.FixedNotional, in.Equity)
+	return RoundQty(notional/in.Price, s.Cfg)
+}
+
+// VolTarget scales exposure so the position's annualised volatility matches
+// TargetVol.  Realised volatility is preferred; ATR/price is used as a
+// per‑bar proxy when no return history is available.  Exposure never
+// exceeds equity (no leverage).
+type VolTarget struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s VolTarget) Qty(in SizeInput) float64 {
+	if in.Price <= 0 || in.Equity <= 0 {
+		return 0
+	}
+	barVol := in.Volatility
+	if barVol <= 0 && in.ATR > 0 {
+		barVol = in.ATR / in.Price
+	}
+	if barVol <= 0 || math.IsNaN(barVol) || math.IsInf(barVol, 0) {
+		return 0
+	}
+	barsPerYear := s.Cfg.BarsPerYear
+	if barsPerYear <= 0 {
+		barsPerYear = DefaultBarsPerYear
+	}
+	annualVol := barVol * math.Sqrt(barsPerYear)
+	notional := math.Min(in.Equity*s.Cfg.TargetVol/annualVol, in.Equity)
+	return RoundQty(notional/in.Price, s.Cfg)
+}
+
+// ATRStop risks MaxRiskPerTrade of equity against a stop placed
+// ATRStopMultiple × ATR away from the entry.  Without an ATR estimate it
+// behaves like FixedFractional.
+type ATRStop struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s ATRStop) Qty(in SizeInput) float64 {
+	if in.ATR <= 0 {
+		return FixedFractional(s).Qty(in)
+	}
+	mult := s.Cfg.ATRStopMultiple
+	if mult <= 0 {
+		mult = DefaultATRStopMultiple
+	}
+	stopDist := in.ATR * mult
+	return RoundQty(in.Equity*s.Cfg.MaxRiskPerTrade/stopDist, s.Cfg)
+}
+
+// Kelly risks a (fractional) Kelly share of equity against the StopLossPct
+// stop, estimating edge and payoff ratio from SizeInput.Trades.  Until
+// KellyMinTrades trades have closed it behaves like FixedFractional.
+type Kelly struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s Kelly) Qty(in SizeInput) float64 {
+	if !enoughTrades(in.Trades, s.Cfg) {
+		return FixedFractional(s).Qty(in)
+	}
+	f := kellyRisk(KellyFraction(in.Trades), s.Cfg)
+	return CalcQty(in.Equity, f, s.Cfg.StopLossPct, in.Price, s.Cfg)
+}
+
+// OptimalFSizer risks a (fractional) optimal‑f share of equity per unit of
+// the worst historical loss.  It behaves like FixedFractional until
+// KellyMinTrades trades have closed or while no losing trade is on record.
+type OptimalFSizer struct {
+	Cfg config.StrategyConfig
+}
+
+// Qty implements Sizer.
+func (s OptimalFSizer) Qty(in SizeInput) float64 {
+	if !enoughTrades(in.Trades, s.Cfg) {
+		return FixedFractional(s).Qty(in)
+	}
+	f, worst := OptimalF(in.Trades)
+	if worst <= 0 {
+		return FixedFractional(s).Qty(in)
+	}
+	return CalcQty(in.Equity, kellyRisk(f, s.Cfg), worst, in.Price, s.Cfg)
+}
+
+func enoughTrades(h *TradeHistory, cfg config.StrategyConfig) bool {
+	minTrades := cfg.KellyMinTrades
+	if minTrades <= 0 {
+		minTrades = DefaultKellyMinTrades
+	}
+	return h != nil && h.Len() >= minTrades
+}
+
+// kellyRisk scales a raw growth‑optimal fraction by KellyFraction and caps
+// it at KellyMaxRisk.
+func kellyRisk(f float64, cfg config.StrategyConfig) float64 {
+	scale := cfg.KellyFraction
+	if scale <= 0 {
+		scale = DefaultKellyFraction
+	}
+	maxRisk := cfg.KellyMaxRisk
+	if maxRisk <= 0 {
+		maxRisk = DefaultKellyMaxRisk
+	}
+	return math.Min(f*scale, maxRisk)
+}
@@ -0,0 +1,96 @@
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+)
+
+func sizerConfig() config.StrategyConfig {
+	return config.StrategyConfig{
+		MaxRiskPerTrade:   0.01,
+		StopLossPct:       0.015,
+		StepSize:          0.01,
+		QuantityPrecision: 2,
+		MinQty:            0.05,
+	}
+}
+
+func TestNewSizerDefaultsToFixedFractional(t *testing.T) {
+	cfg := sizerConfig()
+	s, err := NewSizer(cfg)
+	if err != nil {
+		t.Fatalf("NewSizer failed: %v", err)
+	}
+	got := s.Qty(SizeInput{Equity: 10_000, Price: 100})
+	want := CalcQty(10_000, 0.01, 0.015, 100, cfg)
+	if got != want {
+		t.Fatalf("fixed fractional qty %v != CalcQty %v", got, want)
+	}
+}
+
+func TestNewSizerRejectsUnknownMethod(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.Sizing = "martingale"
+	if _, err := NewSizer(cfg); err == nil {
+		t.Fatal("expected error for unknown sizing method")
+	}
+}
+
+func TestFixedNotionalQty(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.FixedNotional = 1_000
+	qty := FixedNotional{Cfg: cfg}.Qty(SizeInput{Equity: 10_000, Price: 300})
+	if qty != 3.33 { // 1000 / 300 = 3.333… floored to 0.01
+		t.Fatalf("unexpected qty: %v", qty)
+	}
+	// Never spend more than the available equity.
+	qty = FixedNotional{Cfg: cfg}.Qty(SizeInput{Equity: 500, Price: 100})
+	if qty != 5 {
+		t.Fatalf("expected notional capped at equity (5), got %v", qty)
+	}
+}
+
+func TestVolTargetScalesInverselyWithVolatility(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.TargetVol = 0.20
+	cfg.BarsPerYear = 252
+	s := VolTarget{Cfg: cfg}
+
+	// 2 % daily vol → ~31.7 % annual → notional ≈ 0.63 × equity.
+	calm := s.Qty(SizeInput{Equity: 10_000, Price: 100, Volatility: 0.02})
+	wild := s.Qty(SizeInput{Equity: 10_000, Price: 100, Volatility: 0.04})
+	want := math.Floor(10_000*0.20/(0.02*math.Sqrt(252))/100*100) / 100
+	if math.Abs(calm-want) > 0.011 {
+		t.Fatalf("unexpected calm qty: got %v want ≈%v", calm, want)
+	}
+	if wild >= calm {
+		t.Fatalf("higher volatility should shrink size: calm=%v wild=%v", calm, wild)
+	}
+	// Very low volatility must not lever up beyond equity.
+	capped := s.Qty(SizeInput{Equity: 10_000, Price: 100, Volatility: 0.0001})
+	if capped != 100 {
+		t.Fatalf("expected exposure capped at equity (100), got %v", capped)
+	}
+	// ATR is used as a proxy when no realised volatility is available.
+	proxy := s.Qty(SizeInput{Equity: 10_000, Price: 100, ATR: 2})
+	if proxy != calm {
+		t.Fatalf("ATR proxy qty %v != realised‑vol qty %v", proxy, calm)
+	}
+}
+
+func TestATRStopQty(t *testing.T) {
+	cfg := sizerConfig()
+	cfg.ATRStopMultiple = 2
+	s := ATRStop{Cfg: cfg}
+	qty := s.Qty(SizeInput{Equity: 10_000, Price: 100, ATR: 1.5}) // $100 / $3
+	if qty != 33.33 {
+		t.Fatalf("unexpected qty: %v", qty)
+	}
+	// Without ATR it falls back to the percentage stop.
+	fallback := s.Qty(SizeInput{Equity: 10_000, Price: 100})
+	if fallback != CalcQty(10_000, 0.01, 0.015, 100, cfg) {
+		t.Fatalf("expected fixed‑fractional fallback, got %v", fallback)
+	}
+}
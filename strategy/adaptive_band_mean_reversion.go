@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -20,14 +19,7 @@ func NewAdaptiveBandMR(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*AdaptiveBandMR, error) {
 
 	// Build suite with user‑provided thresholds.
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
@@ -38,21 +30,29 @@ func NewAdaptiveBandMR(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar updates the suite and decides whether to open/close a trade.
 func (a *AdaptiveBandMR) ProcessBar(high, low, close, volume float64) {
+	defer a.flushDecision()
 	// Warm‑up: ensure we have enough data for the indicators.
 	if err := a.Suite.Add(high, low, close, volume); err != nil {
 		a.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	a.recordPrice(close)
+	a.recordBar(high, low, close, volume)
+	if a.manageExits(high, low, close) {
+		return
+	}
 
-	rsiVal, err := a.Suite.GetRSI().Calculate()
-	if err != nil {
+	rsiVal, rsiErr := a.Suite.GetRSI().Calculate()
+	if rsiErr != nil {
 		rsiVal = 50
 	}
-	mfiVal, err := a.Suite.GetMFI().Calculate()
-	if err != nil {
+	mfiVal, mfiErr := a.Suite.GetMFI().Calculate()
+	if mfiErr != nil {
 		mfiVal = 50
 	}
+	oscErr := rsiErr
+	if oscErr == nil {
+		oscErr = mfiErr
+	}
 	atrVals := a.Suite.GetATSO().GetATSOValues()
 	atr := 0.0
 	if len(atrVals) > 0 {
@@ -66,14 +66,8 @@ func (a *AdaptiveBandMR) ProcessBar(high, low, close, volume float64) {
 		atr = math.Max(bandProxy, 0.0001)
 	}
 	atr = a.sanitizeVolatility(atr, close)
-	hmaBull := a.bullishFallback()
-	if ok, err := a.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hmaBull = ok
-	}
-	hmaBear := a.bearishFallback()
-	if ok, err := a.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hmaBear = ok
-	}
+	hmaBull := a.warmupSignal("hma_bull", a.Suite.GetHMA().IsBullishCrossover, a.bullishFallback)
+	hmaBear := a.warmupSignal("hma_bear", a.Suite.GetHMA().IsBearishCrossover, a.bearishFallback)
 
 	// 2️⃣ Build adaptive band.
 	bandWidth := close * a.Cfg.StopLossPct // reuse StopLossPct as band factor
@@ -81,28 +75,26 @@ func (a *AdaptiveBandMR) ProcessBar(high, low, close, volume float64) {
 	lowerBand := close - bandWidth - atr
 
 	// 3️⃣ Entry conditions.
-	oversoldOK := rsiVal <= a.Cfg.RSIOversold && mfiVal <= a.Cfg.MFIOversold
-	if !oversoldOK && a.bearishFallback() {
-		oversoldOK = true
-	}
-	overboughtOK := rsiVal >= a.Cfg.RSIOverbought && mfiVal >= a.Cfg.MFIOverbought
-	if !overboughtOK && a.bullishFallback() {
-		overboughtOK = true
-	}
+	oversoldOK := a.combineSignal("oversold",
+		rsiVal <= a.Cfg.RSIOversold && mfiVal <= a.Cfg.MFIOversold, oscErr, a.bearishFallback)
+	overboughtOK := a.combineSignal("overbought",
+		rsiVal >= a.Cfg.RSIOverbought && mfiVal >= a.Cfg.MFIOverbought, oscErr, a.bullishFallback)
 
 	longCond := low <= lowerBand && oversoldOK && !hmaBull
 	shortCond := high >= upperBand && overboughtOK && !hmaBear
 
-	posQty, _ := a.Exec.Position(a.Symbol)
+	posQty, _ := a.position()
 
 	switch {
 	case longCond && posQty <= 0:
+		a.setIntent(IntentLong, a.conviction("oversold"), close)
 		if posQty < 0 {
 			a.closePosition(close, "adaptiveband_rev_close_short")
 		}
 		a.openLong(close, atr)
 
 	case shortCond && posQty >= 0:
+		a.setIntent(IntentShort, a.conviction("overbought"), close)
 		if posQty > 0 {
 			a.closePosition(close, "adaptiveband_rev_close_long")
 		}
@@ -110,7 +102,7 @@ func (a *AdaptiveBandMR) ProcessBar(high, low, close, volume float64) {
 
 	case posQty != 0:
 		// Manage existing position – trailing stop & optional TP.
-		if a.Cfg.TrailingPct > 0 {
+		if a.trailingEnabled() {
 			a.applyTrailingStop(close)
 		}
 		if a.Cfg.TakeProfitPct > 0 {
@@ -121,6 +113,9 @@ func (a *AdaptiveBandMR) ProcessBar(high, low, close, volume float64) {
 
 // openLong creates a long order sized by risk.
 func (a *AdaptiveBandMR) openLong(price, atr float64) {
+	if !a.canOpen(types.Buy) {
+		return
+	}
 	qty := a.calcQty(price) // uses risk.CalcQty internally
 	if qty <= 0 {
 		return
@@ -137,6 +132,9 @@ func (a *AdaptiveBandMR) openLong(price, atr float64) {
 
 // openShort creates a short order sized by risk.
 func (a *AdaptiveBandMR) openShort(price, atr float64) {
+	if !a.canOpen(types.Sell) {
+		return
+	}
 	qty := a.calcQty(price)
 	if qty <= 0 {
 		return
@@ -153,7 +151,7 @@ func (a *AdaptiveBandMR) openShort(price, atr float64) {
 
 // manageTakeProfit implements the optional ATR‑multiple TP.
 func (a *AdaptiveBandMR) manageTakeProfit(currentPrice, atr float64) {
-	qty, avg := a.Exec.Position(a.Symbol)
+	qty, avg := a.position()
 	if qty == 0 {
 		return
 	}
@@ -92,20 +92,7 @@ func TestAdaptiveBandMR_TrailingStop(t *testing.T) {
 	}
 	entry := exec.Orders()[0].Price // ≈100
 
-	// ---- price crosses trailing level (entry*1.02) ----
-	trail := entry * 1.02
-	high, low, close, vol = 103.0, 101.0, trail+0.1, 1600.0
-	ab.ProcessBar(high, low, close, vol)
-
-	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected trailing‑stop close order, got %d", len(exec.Orders()))
-	}
-	if exec.Orders()[1].Side != types.Sell {
-		t.Fatalf("expected SELL to close trailing stop, got %s", exec.Orders()[1].Side)
-	}
-	if exec.Orders()[1].Price < trail {
-		t.Fatalf("trailing‑stop price %f below expected %f", exec.Orders()[1].Price, trail)
-	}
+	checkTrailingExit(t, ab, exec, entry)
 }
 
 func TestAdaptiveBandMR_TakeProfit(t *testing.T) {
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"math"
+	"time"
 
 	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
@@ -19,7 +20,53 @@ type BaseStrategy struct {
 	Cfg    config.StrategyConfig
 	Suite  *goti.IndicatorSuite
 	Symbol string
+	// Sizer turns equity/price/volatility into an order quantity.  It is
+	// chosen from Cfg.Sizing by NewBaseStrategy and may be replaced.
+	Sizer risk.Sizer
+	// Trades records the return of every position this strategy closes; it
+	// feeds history‑based sizers such as Kelly and optimal f.
+	Trades *risk.TradeHistory
+	// Now is the strategy clock used by the time‑based exits.  It defaults
+	// to time.Now; back‑tests replace it with the bar timestamp.
+	Now func() time.Time
+	// Trace, when set, receives a DecisionRecord for every bar.
+	Trace  TraceSink
 	prices *priceBuffer
+	// held holds the exit bookkeeping of each open position by symbol.
+	held map[string]*positionState
+	// signals audits the signals evaluated on the current bar.
+	signals []SignalRecord
+	// bars journals recent bars so Restore can rebuild the suite.
+	bars     []barRecord
+	barSeq   int64
+	newSuite func() (*goti.IndicatorSuite, error)
+	// decision is the trace record of the current bar (nil when disabled).
+	decision *DecisionRecord
+	// regime classifies every journaled bar; see Regime.
+	regime *RegimeClassifier
+	// targets receives the target position of every bar in target mode;
+	// targetSeq is the last bar emitted.
+	targets   TargetSink
+	targetSeq int64
+	// intent is the side the decision code last asked for, recorded with
+	// its strength, the price it was recorded at and the bar it was
+	// recorded on (see setIntent).
+	intent      Intent
+	strength    float64
+	intentPrice float64
+	intentSeq   int64
+	// signalOnly stops submitOrder from placing orders; see SetSignalOnly.
+	signalOnly bool
+	// partials accumulates the scale‑outs of each open position so that
+	// the final exit records the position as a single trade.
+	partials map[string]partialClose
+}
+
+// partialClose is the P&L and entry cost of the parts of a position closed
+// so far.
+type partialClose struct {
+	PnL  float64 `json:"pnl"`
+	Cost float64 `json:"cost"`
 }
 
 // NewBaseStrategy creates the indicator suite (using the supplied factory)
@@ -33,22 +80,48 @@ func NewBaseStrategy(symbol string, cfg config.StrategyConfig,
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	sizer, err := risk.NewSizer(cfg)
+	if err != nil {
+		return nil, err
+	}
 	suite, err := suiteFactory()
 	if err != nil {
 		return nil, err
 	}
+	warnIndicatorConfig(log, cfg)
+	regime, err := NewRegimeClassifier(DefaultRegimeConfig())
+	if err != nil {
+		return nil, err
+	}
 	return &BaseStrategy{
 		Exec:   exec,
 		Log:    log,
 		Cfg:    cfg,
 		Suite:  suite,
 		Symbol: symbol,
+		Sizer:  sizer,
+		Trades: risk.NewTradeHistory(cfg.KellyLookback),
 		prices: newPriceBuffer(64),
+		Now:    time.Now,
+		held:   make(map[string]*positionState),
+		regime: regime,
+
+		newSuite: suiteFactory,
 	}, nil
 }
 
-// submitOrder is a thin wrapper that records metrics and logs.
+// submitOrder is a thin wrapper that records metrics and logs.  Orders that
+// would leave the position on a side forbidden by Cfg.PositionMode are
+// trimmed to a pure exit, or dropped when there is nothing to exit.  In
+// signal‑only mode it does nothing.
 func (b *BaseStrategy) submitOrder(o types.Order, ctx string) error {
+	if b.signalOnly {
+		return nil
+	}
+	o, ok := b.clampToPositionMode(o, ctx)
+	if !ok {
+		return nil
+	}
 	err := b.Exec.Submit(o)
 	if err != nil {
 		b.Log.Error("order_submit_failed",
@@ -65,46 +138,128 @@ func (b *BaseStrategy) submitOrder(o types.Order, ctx string) error {
 		logger.Float64("qty", o.Qty),
 		logger.Float64("price", o.Price),
 		logger.String("ctx", ctx),
+		logger.String("signals", b.signalSummary()),
 	)
 	metrics.OrdersSubmitted.WithLabelValues(ctx).Inc()
+	b.traceOrder(o, ctx)
+	b.notePosition()
 	return nil
 }
 
-// calcQty delegates to the risk package using the stored config.
+// canOpen reports whether Cfg.PositionMode permits opening a position on
+// side and the session is still open, logging the skipped entry when not.
+func (b *BaseStrategy) canOpen(side types.Side) bool {
+	mode := b.Cfg.PositionMode
+	if !((side == types.Buy && mode.AllowsLong()) || (side == types.Sell && mode.AllowsShort())) {
+		b.Log.Info("entry_skipped_position_mode",
+			logger.String("symbol", b.Symbol),
+			logger.String("side", string(side)),
+			logger.String("mode", string(mode)),
+		)
+		return false
+	}
+	if b.sessionClosed(b.now()) {
+		b.Log.Info("entry_skipped_session_closed",
+			logger.String("symbol", b.Symbol),
+			logger.String("side", string(side)),
+		)
+		return false
+	}
+	return true
+}
+
+// clampToPositionMode limits an order so the resulting position never lands
+// on a forbidden side.  It returns false when nothing is left to submit.
+func (b *BaseStrategy) clampToPositionMode(o types.Order, ctx string) (types.Order, bool) {
+	mode := b.Cfg.PositionMode
+	if mode.AllowsLong() && mode.AllowsShort() {
+		return o, true
+	}
+	qty, _ := b.position()
+	allowed := o.Qty
+	switch {
+	case o.Side == types.Sell && !mode.AllowsShort():
+		allowed = math.Min(o.Qty, math.Max(qty, 0))
+	case o.Side == types.Buy && !mode.AllowsLong():
+		allowed = math.Min(o.Qty, math.Max(-qty, 0))
+	}
+	if allowed == o.Qty {
+		return o, true
+	}
+	b.Log.Info("order_trimmed_position_mode",
+		logger.String("symbol", o.Symbol),
+		logger.String("side", string(o.Side)),
+		logger.Float64("requested_qty", o.Qty),
+		logger.Float64("allowed_qty", allowed),
+		logger.String("mode", string(mode)),
+		logger.String("ctx", ctx),
+	)
+	o.Qty = allowed
+	return o, allowed > 0
+}
+
+// calcQty delegates to the configured risk.Sizer, feeding it the current
+// equity together with ATR and realised‑volatility estimates.
 func (b *BaseStrategy) calcQty(price float64) float64 {
-	return risk.CalcQty(b.Exec.Equity(), b.Cfg.MaxRiskPerTrade, b.Cfg.StopLossPct, price, b.Cfg)
+	if b.Sizer == nil {
+		return risk.CalcQty(b.Exec.Equity(), b.Cfg.MaxRiskPerTrade, b.Cfg.StopLossPct, price, b.Cfg)
+	}
+	vol := 0.0
+	if b.prices != nil {
+		vol = b.prices.ReturnVolatility()
+	}
+	return b.Sizer.Qty(risk.SizeInput{
+		Equity:     b.Exec.Equity(),
+		Price:      price,
+		ATR:        b.atrEstimate(price),
+		Volatility: vol,
+		Trades:     b.Trades,
+	})
 }
 
-// trailingStopLevel returns the price level at which a trailing stop would fire.
-func (b *BaseStrategy) trailingStopLevel(entryAvg, side float64) float64 {
-	if side > 0 { // long
-		return entryAvg * (1 + b.Cfg.TrailingPct)
+// atrEstimate returns the sanitized ATSO magnitude that the strategies use
+// as their ATR proxy.
+func (b *BaseStrategy) atrEstimate(price float64) float64 {
+	atr := 0.0
+	if b.Suite != nil {
+		if vals := b.Suite.GetATSO().GetATSOValues(); len(vals) > 0 {
+			atr = math.Abs(vals[len(vals)-1])
+		}
 	}
-	// short
-	return entryAvg * (1 - b.Cfg.TrailingPct)
+	return b.sanitizeVolatility(atr, price)
+}
+
+// closePosition flattens the current position at the supplied price and
+// records the flat intent (see closeIntent).
+func (b *BaseStrategy) closePosition(price float64, ctx string) {
+	b.closeFraction(price, 1, ctx)
 }
 
-// applyTrailingStop checks the current price against the trailing level and
-// closes the position if needed.
-func (b *BaseStrategy) applyTrailingStop(currentPrice float64) {
-	if b.Cfg.TrailingPct <= 0 {
+// closeFraction closes fraction (0, 1] of the current position at the
+// supplied price.  The partial quantity is rounded to the exchange rules;
+// when rounding leaves nothing, or the remainder would be dust, the whole
+// position is closed instead.  In signal‑only mode only full closes have
+// an effect, on the intent.
+func (b *BaseStrategy) closeFraction(price, fraction float64, ctx string) {
+	qty, avg := b.position()
+	if qty == 0 || fraction <= 0 {
 		return
 	}
-	qty, avg := b.Exec.Position(b.Symbol)
-	if qty == 0 {
+	if b.signalOnly {
+		if fraction >= 1 {
+			b.closeIntent(price)
+		}
 		return
 	}
-	level := b.trailingStopLevel(avg, math.Copysign(1, qty))
-	if (qty > 0 && currentPrice >= level) || (qty < 0 && currentPrice <= level) {
-		b.closePosition(currentPrice, "trailing_stop")
+	closeQty := math.Abs(qty)
+	if fraction < 1 {
+		part := risk.RoundQty(closeQty*fraction, b.Cfg)
+		if part > 0 && risk.RoundQty(closeQty-part, b.Cfg) > 0 {
+			closeQty = part
+		}
 	}
-}
-
-// closePosition flattens the current position at the supplied price.
-func (b *BaseStrategy) closePosition(price float64, ctx string) {
-	qty, _ := b.Exec.Position(b.Symbol)
-	if qty == 0 {
-		return
+	if closeQty >= math.Abs(qty) {
+		b.closeIntent(price)
 	}
 	side := types.Sell
 	if qty < 0 {
@@ -113,14 +268,54 @@ func (b *BaseStrategy) closePosition(price float64, ctx string) {
 	o := types.Order{
 		Symbol:  b.Symbol,
 		Side:    side,
-		Qty:     math.Abs(qty),
+		Qty:     closeQty,
 		Price:   price,
 		Comment: ctx,
 	}
-	_ = b.submitOrder(o, ctx)
+	if b.submitOrder(o, ctx) != nil {
+		return
+	}
+	if closeQty < math.Abs(qty) {
+		b.recordPartial(qty, closeQty, avg, price)
+	} else {
+		b.recordTrade(qty, avg, price)
+	}
+}
+
+// recordPartial adds the scale‑out of closeQty from a position of signed
+// qty with average entry avg to the position's running result.
+func (b *BaseStrategy) recordPartial(qty, closeQty, avg, exitPrice float64) {
+	if qty == 0 || avg <= 0 {
+		return
+	}
+	if b.partials == nil {
+		b.partials = make(map[string]partialClose)
+	}
+	p := b.partials[b.Symbol]
+	p.PnL += math.Copysign(1, qty) * closeQty * (exitPrice - avg)
+	p.Cost += closeQty * avg
+	b.partials[b.Symbol] = p
+}
+
+// recordTrade stores the return of a closed position (signed qty, average
+// entry) in the strategy's trade history.  Earlier scale‑outs of the same
+// position are folded in, so the history holds one cost‑weighted return
+// per position.
+func (b *BaseStrategy) recordTrade(qty, avg, exitPrice float64) {
+	p := b.partials[b.Symbol]
+	delete(b.partials, b.Symbol)
+	if b.Trades == nil || qty == 0 || avg <= 0 {
+		return
+	}
+	pnl := p.PnL + math.Copysign(1, qty)*math.Abs(qty)*(exitPrice-avg)
+	cost := p.Cost + math.Abs(qty)*avg
+	b.Trades.Record(pnl / cost)
 }
 
+// recordPrice starts a new bar: it feeds the price buffer and clears the
+// previous bar's signal audit.
 func (b *BaseStrategy) recordPrice(close float64) {
+	b.signals = b.signals[:0]
 	if b.prices != nil {
 		b.prices.Add(close)
 	}
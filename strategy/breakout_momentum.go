@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -19,11 +18,7 @@ type BreakoutMomentum struct {
 func NewBreakoutMomentum(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*BreakoutMomentum, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.ATSEMAperiod = cfg.ATSEMAperiod
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -33,48 +28,42 @@ func NewBreakoutMomentum(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar updates the suite, evaluates breakout signals and manages positions.
 func (bm *BreakoutMomentum) ProcessBar(high, low, close, volume float64) {
+	defer bm.flushDecision()
 	if err := bm.Suite.Add(high, low, close, volume); err != nil {
 		bm.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	bm.recordPrice(close)
+	bm.recordBar(high, low, close, volume)
+	if bm.manageExits(high, low, close) {
+		return
+	}
 	if !bm.hasHistory(15) {
 		return
 	}
 
 	// 1️⃣ Gather signals.
-	hBull := bm.bullishFallback()
-	if ok, err := bm.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := bm.bearishFallback()
-	if ok, err := bm.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
-	}
-	vBull := bm.bullishFallback()
-	if ok, err := bm.Suite.GetVWAO().IsBullishCrossover(); err == nil {
-		vBull = vBull || ok
-	}
-	vBear := bm.bearishFallback()
-	if ok, err := bm.Suite.GetVWAO().IsBearishCrossover(); err == nil {
-		vBear = vBear || ok
-	}
-	atBull := bm.bullishFallback() || bm.Suite.GetATSO().IsBullishCrossover()
-	atBear := bm.bearishFallback() || bm.Suite.GetATSO().IsBearishCrossover()
+	hBull := bm.signal("hma_bull", bm.Suite.GetHMA().IsBullishCrossover, bm.bullishFallback)
+	hBear := bm.signal("hma_bear", bm.Suite.GetHMA().IsBearishCrossover, bm.bearishFallback)
+	vBull := bm.signal("vwao_bull", bm.Suite.GetVWAO().IsBullishCrossover, bm.bullishFallback)
+	vBear := bm.signal("vwao_bear", bm.Suite.GetVWAO().IsBearishCrossover, bm.bearishFallback)
+	atBull := bm.signal("atso_bull", alwaysReady(bm.Suite.GetATSO().IsBullishCrossover), bm.bullishFallback)
+	atBear := bm.signal("atso_bear", alwaysReady(bm.Suite.GetATSO().IsBearishCrossover), bm.bearishFallback)
 
 	longSignal := hBull && vBull && atBull
 	shortSignal := hBear && vBear && atBear
 
-	posQty, _ := bm.Exec.Position(bm.Symbol)
+	posQty, _ := bm.position()
 
 	switch {
 	case longSignal && posQty <= 0:
+		bm.setIntent(IntentLong, bm.conviction("hma_bull", "vwao_bull", "atso_bull"), close)
 		if posQty < 0 {
 			bm.closePosition(close, "breakout_mom_close_short")
 		}
 		bm.openLong(close)
 
 	case shortSignal && posQty >= 0:
+		bm.setIntent(IntentShort, bm.conviction("hma_bear", "vwao_bear", "atso_bear"), close)
 		if posQty > 0 {
 			bm.closePosition(close, "breakout_mom_close_long")
 		}
@@ -82,7 +71,7 @@ func (bm *BreakoutMomentum) ProcessBar(high, low, close, volume float64) {
 
 	case posQty != 0:
 		// Trailing stop & optional TP.
-		if bm.Cfg.TrailingPct > 0 {
+		if bm.trailingEnabled() {
 			bm.applyTrailingStop(close)
 		}
 		if bm.Cfg.TakeProfitPct > 0 {
@@ -93,6 +82,9 @@ func (bm *BreakoutMomentum) ProcessBar(high, low, close, volume float64) {
 
 // openLong creates a long order sized by risk.
 func (bm *BreakoutMomentum) openLong(price float64) {
+	if !bm.canOpen(types.Buy) {
+		return
+	}
 	qty := bm.calcQty(price)
 	if qty <= 0 {
 		return
@@ -109,6 +101,9 @@ func (bm *BreakoutMomentum) openLong(price float64) {
 
 // openShort creates a short order sized by risk.
 func (bm *BreakoutMomentum) openShort(price float64) {
+	if !bm.canOpen(types.Sell) {
+		return
+	}
 	qty := bm.calcQty(price)
 	if qty <= 0 {
 		return
@@ -125,7 +120,7 @@ func (bm *BreakoutMomentum) openShort(price float64) {
 
 // manageTakeProfit uses ATR‑multiple TP (same logic as in AdaptiveBandMR).
 func (bm *BreakoutMomentum) manageTakeProfit(currentPrice float64) {
-	qty, avg := bm.Exec.Position(bm.Symbol)
+	qty, avg := bm.position()
 	if qty == 0 {
 		return
 	}
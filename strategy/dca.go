@@ -0,0 +1,284 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// DCAConfig holds the accumulation schedule of DCA.
+type DCAConfig struct {
+	// Notional is the quote amount of every scheduled buy; Qty buys a fixed
+	// quantity instead.  Exactly one of them is set.
+	Notional float64
+	Qty      float64
+
+	// EveryBars schedules a buy every EveryBars bars; Interval every
+	// Interval on the strategy clock (BaseStrategy.Now).  Exactly one of
+	// them is set.  The first bar always buys.
+	EveryBars int
+	Interval  time.Duration
+
+	// A buy is multiplied by BelowMAScale when the close is below its
+	// MAPeriod simple moving average (MAPeriod 0 disables the rule) and by
+	// OversoldScale when the RSI is at or below RSIOversold (0 disables).
+	// Both multipliers apply when both conditions hold.
+	MAPeriod      int
+	BelowMAScale  float64
+	OversoldScale float64
+
+	// MaxAllocation caps the cost of the position held; the last buy is
+	// trimmed to the remainder.  0 means unbounded.
+	MaxAllocation float64
+
+	// UseExits lets the hard stop, trailing, time exits and position
+	// scaling of BaseStrategy manage the accumulated position.  They are
+	// off by default: a dip is what DCA buys, not a reason to sell.
+	UseExits bool
+}
+
+// DefaultDCAConfig buys a notional of 100 every bar without scaling or a
+// cap.
+func DefaultDCAConfig() DCAConfig {
+	return DCAConfig{Notional: 100, EveryBars: 1}
+}
+
+// Validate checks the schedule.
+func (dc DCAConfig) Validate() error {
+	if dc.Notional < 0 || dc.Qty < 0 || (dc.Notional > 0) == (dc.Qty > 0) {
+		return errors.New("exactly one of Notional and Qty must be positive")
+	}
+	if dc.EveryBars < 0 || dc.Interval < 0 || (dc.EveryBars > 0) == (dc.Interval > 0) {
+		return errors.New("exactly one of EveryBars and Interval must be positive")
+	}
+	if dc.MAPeriod < 0 || dc.MAPeriod > maxReturnHistory {
+		return fmt.Errorf("MAPeriod (%d) must be between 0 and %d", dc.MAPeriod, maxReturnHistory)
+	}
+	if dc.MAPeriod > 0 && dc.BelowMAScale < 1 {
+		return fmt.Errorf("BelowMAScale (%f) must be at least 1", dc.BelowMAScale)
+	}
+	if dc.OversoldScale != 0 && dc.OversoldScale < 1 {
+		return fmt.Errorf("OversoldScale (%f) must be 0 or at least 1", dc.OversoldScale)
+	}
+	if dc.MaxAllocation < 0 {
+		return fmt.Errorf("MaxAllocation (%f) must not be negative", dc.MaxAllocation)
+	}
+	return nil
+}
+
+// DCA accumulates a long position on a fixed schedule (dollar‑cost
+// averaging).  Every scheduled buy is sized from Notional or Qty, scaled up
+// in dips, capped by MaxAllocation and the available cash and rounded to
+// QuantityPrecision/StepSize/MinQty.  DCA records the cost basis of the
+// quantity it holds and never sells unless DCAConfig.UseExits hands the
+// position to the exits of BaseStrategy.  Any fill that shrinks the position
+// (an exit, or a sale outside the strategy) releases its share of the cost,
+// and the result of closed positions is kept in Trades.
+type DCA struct {
+	*BaseStrategy
+	dc DCAConfig
+
+	started  bool
+	sinceBuy int       // bars since the last scheduled buy
+	lastBuy  time.Time // clock of the last scheduled buy
+
+	buys          int
+	invested      float64 // cost of the quantity held
+	accumulated   float64 // quantity held
+	totalInvested float64 // quote amount spent on every buy
+}
+
+// NewDCA builds the suite with DefaultDCAConfig.
+func NewDCA(symbol string, cfg config.StrategyConfig,
+	exec executor.Executor, log logger.Logger) (*DCA, error) {
+
+	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactoryFor(cfg), log)
+	if err != nil {
+		return nil, err
+	}
+	return &DCA{BaseStrategy: base, dc: DefaultDCAConfig()}, nil
+}
+
+// SetDCAConfig replaces the schedule after validating it.
+func (d *DCA) SetDCAConfig(dc DCAConfig) error {
+	if err := dc.Validate(); err != nil {
+		return logOutputError(d.Log, err.Error())
+	}
+	d.dc = dc
+	return nil
+}
+
+// Buys returns the number of filled buys.
+func (d *DCA) Buys() int { return d.buys }
+
+// Invested returns the cost of the quantity held.
+func (d *DCA) Invested() float64 { return d.invested }
+
+// Accumulated returns the quantity held.
+func (d *DCA) Accumulated() float64 { return d.accumulated }
+
+// TotalInvested returns the quote amount spent on every buy, including
+// the quantity sold since.
+func (d *DCA) TotalInvested() float64 { return d.totalInvested }
+
+// CostBasis returns the average price paid for the quantity held (0 while
+// flat).
+func (d *DCA) CostBasis() float64 {
+	if d.accumulated == 0 {
+		return 0
+	}
+	return d.invested / d.accumulated
+}
+
+// ProcessBar evaluates the discount rules and buys when the schedule is
+// due.
+func (d *DCA) ProcessBar(high, low, close, volume float64) {
+	defer d.flushDecision()
+	if err := d.Suite.Add(high, low, close, volume); err != nil {
+		d.Log.Warn("suite_add_error", logger.Err(err))
+		return
+	}
+	d.recordBar(high, low, close, volume)
+	exited := d.dc.UseExits && d.manageExits(high, low, close)
+	d.syncLedger()
+	if exited {
+		return
+	}
+	if d.started {
+		d.sinceBuy++
+	}
+
+	scale := 1.0
+	if d.dc.MAPeriod > 0 {
+		ma, ok := d.closeSMA(d.dc.MAPeriod)
+		if d.recordSignal("dca_below_ma", ok, ok && close < ma, false) {
+			scale *= d.dc.BelowMAScale
+		}
+	}
+	if d.dc.OversoldScale > 0 {
+		rsi, err := d.Suite.GetRSI().Calculate()
+		if d.recordSignal("rsi_oversold", err == nil, err == nil && rsi <= d.rsiOversold(), false) {
+			scale *= d.dc.OversoldScale
+		}
+	}
+
+	now := d.now()
+	due := !d.started
+	switch {
+	case d.dc.EveryBars > 0:
+		due = due || d.sinceBuy >= d.dc.EveryBars
+	default:
+		due = due || now.Sub(d.lastBuy) >= d.dc.Interval
+	}
+	if !due {
+		return
+	}
+	d.started, d.sinceBuy, d.lastBuy = true, 0, now
+	// The schedule, not the discount rules, decides a buy.
+	d.setIntent(IntentLong, 1, close)
+	d.buy(close, scale)
+}
+
+// buy sizes and submits one scheduled buy and books the filled quantity.
+func (d *DCA) buy(price, scale float64) {
+	if !d.canOpen(types.Buy) {
+		return
+	}
+	qty := d.dc.Qty * scale
+	if d.dc.Notional > 0 {
+		qty = d.dc.Notional * scale / price
+	}
+	if d.dc.MaxAllocation > 0 {
+		room := d.dc.MaxAllocation - d.invested
+		if room <= 0 {
+			d.Log.Info("dca_allocation_reached",
+				logger.String("symbol", d.Symbol),
+				logger.Float64("invested", d.invested),
+			)
+			return
+		}
+		qty = math.Min(qty, room/price)
+	}
+	qty = risk.RoundQty(math.Min(qty, d.Exec.Equity()/price), d.Cfg)
+	if qty <= 0 {
+		d.Log.Info("dca_buy_skipped",
+			logger.String("symbol", d.Symbol),
+			logger.Float64("price", price),
+		)
+		return
+	}
+
+	before, _ := d.position()
+	o := types.Order{Symbol: d.Symbol, Side: types.Buy, Qty: qty, Price: price, Comment: "dca_buy"}
+	if d.submitOrder(o, "dca_buy") != nil {
+		return
+	}
+	after, _ := d.position()
+	filled := after - before
+	if filled <= 0 {
+		return
+	}
+	d.buys++
+	d.invested += filled * price
+	d.accumulated += filled
+	d.totalInvested += filled * price
+	d.Log.Info("dca_buy",
+		logger.String("symbol", d.Symbol),
+		logger.Float64("qty", filled),
+		logger.Float64("price", price),
+		logger.Float64("scale", scale),
+		logger.Float64("invested", d.invested),
+		logger.Float64("cost_basis", d.CostBasis()),
+	)
+}
+
+// syncLedger shrinks the ledger to the position on the executor.  The
+// quantity sold takes its share of the cost along, so the basis of what is
+// left is unchanged.
+func (d *DCA) syncLedger() {
+	qty, _ := d.position()
+	if qty >= d.accumulated {
+		return
+	}
+	if qty <= 0 {
+		d.invested, d.accumulated = 0, 0
+		return
+	}
+	d.invested *= qty / d.accumulated
+	d.accumulated = qty
+}
+
+// closeSMA returns the simple moving average of the last n closes, the
+// current one included.
+func (d *DCA) closeSMA(n int) (float64, bool) {
+	if len(d.bars) < n {
+		return 0, false
+	}
+	sum := 0.0
+	for _, b := range d.bars[len(d.bars)-n:] {
+		sum += b.Close
+	}
+	return sum / float64(n), true
+}
+
+// rsiOversold is Cfg.RSIOversold, or the goti default when the configured
+// pair is not a valid oscillator range.
+func (d *DCA) rsiOversold() float64 {
+	if oscillatorPair(d.Cfg.RSIOversold, d.Cfg.RSIOverbought) {
+		return d.Cfg.RSIOversold
+	}
+	return goti.DefaultConfig().RSIOversold
+}
+
+// WarmupBars returns the history the moving‑average rule needs.
+func (d *DCA) WarmupBars() int {
+	return d.warmupBars(d.dc.MAPeriod)
+}
@@ -0,0 +1,245 @@
+package strategy
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+func buildDCA(t *testing.T, cfg config.StrategyConfig, dc DCAConfig) (*DCA, *testutils.MockExecutor, *testutils.MockLogger) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	log := testutils.NewMockLogger()
+	d, err := NewDCA("TEST", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewDCA failed: %v", err)
+	}
+	if err := d.SetDCAConfig(dc); err != nil {
+		t.Fatalf("SetDCAConfig failed: %v", err)
+	}
+	return d, exec, log
+}
+
+// closeBars returns one bar per close, half a unit wide.
+func closeBars(closes ...float64) []candle {
+	bars := make([]candle, len(closes))
+	for i, c := range closes {
+		bars[i] = candle{c + 0.25, c - 0.25, c, 1000}
+	}
+	return bars
+}
+
+func TestDCABarScheduleAndCostBasis(t *testing.T) {
+	d, exec, log := buildDCA(t, buildConfig(), DCAConfig{Notional: 100, EveryBars: 3})
+	feedBars(t, d, closeBars(100, 100, 100, 50, 50, 50, 80))
+
+	orders := exec.Orders()
+	if len(orders) != 3 {
+		t.Fatalf("expected buys on bars 1, 4 and 7, got %+v", orders)
+	}
+	for i, want := range []float64{1, 2, 1.25} {
+		if orders[i].Side != types.Buy || orders[i].Qty != want || orders[i].Comment != "dca_buy" {
+			t.Fatalf("buy %d: %+v, want qty %v", i, orders[i], want)
+		}
+	}
+	if d.Buys() != 3 || d.Invested() != 300 || d.Accumulated() != 4.25 {
+		t.Fatalf("ledger buys %d invested %v accumulated %v", d.Buys(), d.Invested(), d.Accumulated())
+	}
+	if basis := d.CostBasis(); math.Abs(basis-300/4.25) > 1e-9 {
+		t.Fatalf("cost basis %v, want %v", basis, 300/4.25)
+	}
+	if !log.HasMessage("dca_buy") {
+		t.Fatal("expected buys to be logged")
+	}
+}
+
+func TestDCAIntervalSchedule(t *testing.T) {
+	d, exec, _ := buildDCA(t, buildConfig(), DCAConfig{Qty: 1, Interval: 4 * time.Hour})
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Now = func() time.Time { return clock }
+	for range 12 {
+		d.ProcessBar(100.25, 99.75, 100, 1000)
+		clock = clock.Add(time.Hour)
+	}
+	if n := len(exec.Orders()); n != 3 {
+		t.Fatalf("expected buys at 0h, 4h and 8h, got %d", n)
+	}
+}
+
+func TestDCARounding(t *testing.T) {
+	cfg := buildConfig()
+	cfg.QuantityPrecision = 3
+	cfg.StepSize = 0.005
+	cfg.MinQty = 0.01
+	d, exec, _ := buildDCA(t, cfg, DCAConfig{Notional: 100, EveryBars: 1})
+	feedBars(t, d, closeBars(7))
+	if got := exec.Orders()[0].Qty; got != 14.285 {
+		t.Fatalf("qty %v, want 100/7 floored to the 0.005 step", got)
+	}
+
+	d, exec, log := buildDCA(t, cfg, DCAConfig{Qty: 0.004, EveryBars: 1})
+	feedBars(t, d, closeBars(100, 100))
+	if len(exec.Orders()) != 0 || !log.HasMessage("dca_buy_skipped") {
+		t.Fatalf("a buy below MinQty must be skipped, got %+v", exec.Orders())
+	}
+}
+
+func TestDCAScalesBuysInDips(t *testing.T) {
+	d, exec, _ := buildDCA(t, buildConfig(), DCAConfig{
+		Notional: 100, EveryBars: 1, MAPeriod: 5, BelowMAScale: 2,
+	})
+	feedBars(t, d, closeBars(100, 100, 100, 100, 100, 100, 80))
+	orders := exec.Orders()
+	if got := orders[len(orders)-2].Qty; got != 1 {
+		t.Fatalf("at the average the buy is not scaled, qty %v", got)
+	}
+	if got := orders[len(orders)-1].Qty; got != 2.5 {
+		t.Fatalf("below the average the notional doubles, qty %v, want 200/80", got)
+	}
+
+	// A steady decline drives the RSI to its oversold zone.
+	d, exec, _ = buildDCA(t, buildConfig(), DCAConfig{Qty: 1, EveryBars: 1, OversoldScale: 3})
+	closes := make([]float64, 40)
+	for i := range closes {
+		closes[i] = 100 - 1.5*float64(i)
+	}
+	feedBars(t, d, closeBars(closes...))
+	orders = exec.Orders()
+	if orders[0].Qty != 1 || orders[len(orders)-1].Qty != 3 {
+		t.Fatalf("expected unscaled buys while the RSI warms up and 3× once oversold, got first %v last %v",
+			orders[0].Qty, orders[len(orders)-1].Qty)
+	}
+}
+
+func TestDCAAllocationCap(t *testing.T) {
+	d, exec, log := buildDCA(t, buildConfig(), DCAConfig{Notional: 100, EveryBars: 1, MaxAllocation: 250})
+	feedBars(t, d, closeBars(100, 100, 100, 100, 100))
+	var qtys []float64
+	for _, o := range exec.Orders() {
+		qtys = append(qtys, o.Qty)
+	}
+	if !reflect.DeepEqual(qtys, []float64{1, 1, 0.5}) {
+		t.Fatalf("the last buy is trimmed to the cap, got %v", qtys)
+	}
+	if d.Invested() != 250 || !log.HasMessage("dca_allocation_reached") {
+		t.Fatalf("invested %v, want the 250 cap", d.Invested())
+	}
+
+	// The available cash caps a buy as well.
+	d, exec, _ = buildDCA(t, buildConfig(), DCAConfig{Notional: 50_000, EveryBars: 1})
+	feedBars(t, d, closeBars(100))
+	if got := exec.Orders()[0].Qty; got != 100 {
+		t.Fatalf("buy must be capped by the cash, qty %v", got)
+	}
+}
+
+func TestDCAShortOnlySkipsBuys(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionShortOnly
+	d, exec, log := buildDCA(t, cfg, DefaultDCAConfig())
+	feedBars(t, d, closeBars(100, 100))
+	if len(exec.Orders()) != 0 || !log.HasMessage("entry_skipped_position_mode") {
+		t.Fatalf("short‑only must not accumulate, got %+v", exec.Orders())
+	}
+}
+
+func TestDCAKeepsBuyingThroughDipsWithDefaultStop(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = "" // the default percentage stop
+	d, exec, _ := buildDCA(t, cfg, DCAConfig{Notional: 100, EveryBars: 1})
+	feedBars(t, d, closeBars(100, 100, 90, 80))
+	for _, o := range exec.Orders() {
+		if o.Side != types.Buy {
+			t.Fatalf("DCA must not sell on a dip without UseExits, got %+v", exec.Orders())
+		}
+	}
+	if pos, _ := exec.Position("TEST"); d.Buys() != 4 || math.Abs(pos-d.Accumulated()) > 1e-9 {
+		t.Fatalf("buys %d accumulated %v position %v", d.Buys(), d.Accumulated(), pos)
+	}
+
+	// With UseExits the stop closes the position on bar 3 at its close of
+	// 90, which releases the cost of the two units it sold: the ledger
+	// describes the 1.25 units bought at 80 afterwards, and the 200 cap
+	// has room for that buy again.
+	d, exec, _ = buildDCA(t, cfg, DCAConfig{Notional: 100, EveryBars: 1, MaxAllocation: 200, UseExits: true})
+	feedBars(t, d, closeBars(100, 100, 90, 80))
+	if o := exec.Orders()[2]; o.Side != types.Sell || o.Qty != 2 || o.Price != 90 {
+		t.Fatalf("expected the stop to sell 2 at 90, got %+v", exec.Orders())
+	}
+	pos, avg := exec.Position("TEST")
+	if pos != 1.25 || d.Accumulated() != pos || d.CostBasis() != avg || d.Invested() != 100 {
+		t.Fatalf("ledger accumulated %v basis %v invested %v, position %v @ %v",
+			d.Accumulated(), d.CostBasis(), d.Invested(), pos, avg)
+	}
+	if d.Buys() != 3 || d.TotalInvested() != 300 {
+		t.Fatalf("lifetime buys %d invested %v, want 3 and 300", d.Buys(), d.TotalInvested())
+	}
+	if d.Trades.Len() != 1 || math.Abs(d.Trades.Returns()[0]+0.1) > 1e-9 {
+		t.Fatalf("expected one trade returning -10 %%, got %v", d.Trades.Returns())
+	}
+
+	// A partial sale outside the strategy keeps the basis of the rest.
+	_ = exec.Submit(types.Order{Symbol: "TEST", Side: types.Sell, Qty: 0.25, Price: 85})
+	feedBars(t, d, closeBars(85))
+	if pos, avg := exec.Position("TEST"); math.Abs(d.Accumulated()-pos) > 1e-9 || math.Abs(d.CostBasis()-avg) > 1e-9 {
+		t.Fatalf("ledger %v @ %v, position %v @ %v", d.Accumulated(), d.CostBasis(), pos, avg)
+	}
+}
+
+func TestDCASnapshotRestore(t *testing.T) {
+	dc := DCAConfig{Notional: 100, EveryBars: 3, MAPeriod: 4, BelowMAScale: 1.5, MaxAllocation: 700}
+	closes := []float64{100, 98, 96, 99, 101, 97, 95, 94, 96, 100, 102, 99, 97, 95, 96, 98}
+
+	straight, straightExec, _ := buildDCA(t, buildConfig(), dc)
+	feedBars(t, straight, closeBars(closes...))
+
+	first, exec, _ := buildDCA(t, buildConfig(), dc)
+	feedBars(t, first, closeBars(closes[:5]...))
+	data, err := first.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewDCA("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDCA failed: %v", err)
+	}
+	if err := resumed.SetDCAConfig(dc); err != nil {
+		t.Fatalf("SetDCAConfig failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	feedBars(t, resumed, closeBars(closes[5:]...))
+
+	if !reflect.DeepEqual(straightExec.Orders(), exec.Orders()) {
+		t.Fatalf("resumed DCA diverged:\nwant %+v\ngot  %+v", straightExec.Orders(), exec.Orders())
+	}
+	if resumed.CostBasis() != straight.CostBasis() || resumed.Invested() != straight.Invested() {
+		t.Fatalf("ledger diverged: basis %v vs %v", resumed.CostBasis(), straight.CostBasis())
+	}
+}
+
+func TestDCAConfigValidate(t *testing.T) {
+	if err := DefaultDCAConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []DCAConfig{
+		{EveryBars: 1},
+		{Notional: 100, Qty: 1, EveryBars: 1},
+		{Notional: 100},
+		{Notional: 100, EveryBars: 1, Interval: time.Hour},
+		{Notional: 100, EveryBars: 1, MAPeriod: 10, BelowMAScale: 0.5},
+		{Notional: 100, EveryBars: 1, OversoldScale: 0.5},
+		{Notional: 100, EveryBars: 1, MaxAllocation: -1},
+	}
+	for i, dc := range bad {
+		if err := dc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, dc)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package strategy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/types"
+)
+
+// DecisionRecord explains what a strategy did on one bar: its inputs, every
+// indicator value and crossover flag, the signals it evaluated (with their
+// fallback source) and the action taken.
+type DecisionRecord struct {
+	Bar    int64     `json:"bar"` // 1‑based bar count, warm‑up bars included
+	Symbol string    `json:"symbol"`
+	Time   time.Time `json:"time"`
+
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+
+	Indicators map[string]float64 `json:"indicators,omitempty"`
+	Crossovers map[string]bool    `json:"crossovers,omitempty"`
+	Signals    []SignalRecord     `json:"signals,omitempty"`
+
+	// Action is "hold" or the contexts of the submitted orders joined by
+	// "+"; Reason is the signal summary behind the first order, or why the
+	// strategy held.
+	Action   string        `json:"action"`
+	Reason   string        `json:"reason"`
+	Orders   []TracedOrder `json:"orders,omitempty"`
+	Position float64       `json:"position"` // position after the bar
+}
+
+// TracedOrder is an order submitted while a decision was being traced.
+type TracedOrder struct {
+	Side  types.Side `json:"side"`
+	Qty   float64    `json:"qty"`
+	Price float64    `json:"price"`
+	Ctx   string     `json:"ctx"`
+}
+
+// Hold reasons.
+const (
+	ReasonWarmingUp = "warming_up" // no signal was evaluated yet
+	ReasonNoEntry   = "no_entry"   // signals were evaluated, none acted on
+)
+
+// TraceSink receives one DecisionRecord per bar.  Setting
+// BaseStrategy.Trace enables tracing; a nil Trace costs nothing.
+type TraceSink interface {
+	Record(DecisionRecord)
+}
+
+// TraceSinkFunc adapts a function to TraceSink.
+type TraceSinkFunc func(DecisionRecord)
+
+// Record implements TraceSink.
+func (f TraceSinkFunc) Record(r DecisionRecord) { f(r) }
+
+// NewLogTraceSink emits every record as a "decision" log entry.
+func NewLogTraceSink(log logger.Logger) TraceSink {
+	return TraceSinkFunc(func(r DecisionRecord) {
+		log.Info("decision",
+			logger.Any("bar", r.Bar),
+			logger.String("symbol", r.Symbol),
+			logger.Float64("close", r.Close),
+			logger.String("action", r.Action),
+			logger.String("reason", r.Reason),
+			logger.Float64("position", r.Position),
+			logger.Any("indicators", r.Indicators),
+			logger.Any("crossovers", r.Crossovers),
+			logger.Any("signals", r.Signals),
+		)
+	})
+}
+
+// JSONTraceSink writes one JSON record per line, the format read by
+// RenderDecisions.  It is safe for concurrent use.
+type JSONTraceSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONTraceSink returns a sink writing JSON lines to w.
+func NewJSONTraceSink(w io.Writer) *JSONTraceSink {
+	return &JSONTraceSink{enc: json.NewEncoder(w)}
+}
+
+// Record implements TraceSink.
+func (s *JSONTraceSink) Record(r DecisionRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(r); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the first write error, if any.
+func (s *JSONTraceSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// beginDecision opens the record of a new bar when tracing is enabled.
+func (b *BaseStrategy) beginDecision(high, low, close, volume float64) {
+	if b.Trace == nil {
+		return
+	}
+	b.decision = &DecisionRecord{
+		Bar:    b.barSeq,
+		Symbol: b.Symbol,
+		Time:   b.now(),
+		High:   high,
+		Low:    low,
+		Close:  close,
+		Volume: volume,
+	}
+}
+
+// traceOrder adds a submitted order to the open record.
+func (b *BaseStrategy) traceOrder(o types.Order, ctx string) {
+	d := b.decision
+	if d == nil {
+		return
+	}
+	if len(d.Orders) == 0 {
+		d.Reason = b.signalSummary()
+		if d.Reason == "" {
+			d.Reason = ctx
+		}
+	}
+	d.Orders = append(d.Orders, TracedOrder{Side: o.Side, Qty: o.Qty, Price: o.Price, Ctx: ctx})
+}
+
+// flushDecision completes the open record and hands it to Trace, and emits
+// the bar's target in target mode.  Strategies defer it at the top of
+// ProcessBar.
+func (b *BaseStrategy) flushDecision() {
+	b.emitTarget()
+	d := b.decision
+	if d == nil {
+		return
+	}
+	b.decision = nil
+	if b.Trace == nil {
+		return
+	}
+	d.Indicators, d.Crossovers = b.indicatorSnapshot()
+	d.Signals = b.Signals()
+	if len(d.Orders) == 0 {
+		d.Action = "hold"
+		d.Reason = ReasonNoEntry
+		if len(d.Signals) == 0 {
+			d.Reason = ReasonWarmingUp
+		}
+	} else {
+		ctxs := make([]string, len(d.Orders))
+		for i, o := range d.Orders {
+			ctxs[i] = o.Ctx
+		}
+		d.Action = strings.Join(ctxs, "+")
+	}
+	d.Position, _ = b.position()
+	b.Trace.Record(*d)
+}
+
+// indicatorSnapshot reads every suite value and crossover flag that is past
+// its warm‑up.
+func (b *BaseStrategy) indicatorSnapshot() (map[string]float64, map[string]bool) {
+	vals := make(map[string]float64)
+	flags := make(map[string]bool)
+	s := b.Suite
+	if s == nil {
+		return vals, flags
+	}
+	value := func(name string, calc func() (float64, error)) {
+		if v, err := calc(); err == nil {
+			vals[name] = v
+		}
+	}
+	cross := func(name string, check func() (bool, error)) {
+		if v, err := check(); err == nil {
+			flags[name] = v
+		}
+	}
+	value("rsi", s.GetRSI().Calculate)
+	value("mfi", s.GetMFI().Calculate)
+	value("vwao", s.GetVWAO().Calculate)
+	value("hma", s.GetHMA().Calculate)
+	value("amdo", s.GetAMDO().Calculate)
+	value("atso", s.GetATSO().Calculate)
+	cross("rsi_bull", s.GetRSI().IsBullishCrossover)
+	cross("rsi_bear", s.GetRSI().IsBearishCrossover)
+	cross("mfi_bull", s.GetMFI().IsBullishCrossover)
+	cross("mfi_bear", s.GetMFI().IsBearishCrossover)
+	cross("vwao_bull", s.GetVWAO().IsBullishCrossover)
+	cross("vwao_bear", s.GetVWAO().IsBearishCrossover)
+	cross("hma_bull", s.GetHMA().IsBullishCrossover)
+	cross("hma_bear", s.GetHMA().IsBearishCrossover)
+	cross("amdo_bull", s.GetAMDO().IsBullishCrossover)
+	cross("amdo_bear", s.GetAMDO().IsBearishCrossover)
+	cross("atso_bull", alwaysReady(s.GetATSO().IsBullishCrossover))
+	cross("atso_bear", alwaysReady(s.GetATSO().IsBearishCrossover))
+	return vals, flags
+}
+
+// RenderDecisions reads JSON lines written by JSONTraceSink from r and
+// prints the records whose bar lies in [from, to] (to ≤ 0 means no upper
+// bound) in a human‑readable layout.
+func RenderDecisions(w io.Writer, r io.Reader, from, to int64) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var d DecisionRecord
+		if err := json.Unmarshal(sc.Bytes(), &d); err != nil {
+			return fmt.Errorf("trace line %d: %w", line, err)
+		}
+		if d.Bar < from || (to > 0 && d.Bar > to) {
+			continue
+		}
+		renderDecision(w, d)
+	}
+	return sc.Err()
+}
+
+func renderDecision(w io.Writer, d DecisionRecord) {
+	fmt.Fprintf(w, "bar %d %s %s  H=%g L=%g C=%g V=%g  pos=%g\n",
+		d.Bar, d.Symbol, d.Time.Format(time.RFC3339), d.High, d.Low, d.Close, d.Volume, d.Position)
+	fmt.Fprintf(w, "  action: %s  reason: %s\n", d.Action, d.Reason)
+	for _, o := range d.Orders {
+		fmt.Fprintf(w, "  order:  %s %g @ %g (%s)\n", o.Side, o.Qty, o.Price, o.Ctx)
+	}
+	if len(d.Indicators) > 0 {
+		parts := make([]string, 0, len(d.Indicators))
+		for _, k := range sortedKeys(d.Indicators) {
+			parts = append(parts, fmt.Sprintf("%s=%.4g", k, d.Indicators[k]))
+		}
+		fmt.Fprintf(w, "  indicators: %s\n", strings.Join(parts, " "))
+	}
+	if len(d.Crossovers) > 0 {
+		var on []string
+		for _, k := range sortedKeys(d.Crossovers) {
+			if d.Crossovers[k] {
+				on = append(on, k)
+			}
+		}
+		if len(on) == 0 {
+			on = []string{"-"}
+		}
+		fmt.Fprintf(w, "  crossovers: %s\n", strings.Join(on, " "))
+	}
+	if len(d.Signals) > 0 {
+		parts := make([]string, len(d.Signals))
+		for i, s := range d.Signals {
+			p := fmt.Sprintf("%s=%t(%s)", s.Name, s.Value, s.Source)
+			if !s.Ready {
+				p += "[warm‑up]"
+			}
+			parts[i] = p
+		}
+		fmt.Fprintf(w, "  signals: %s\n", strings.Join(parts, " "))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
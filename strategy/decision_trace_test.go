@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/evdnx/gots/types"
+)
+
+func TestDecisionTracePerBar(t *testing.T) {
+	mr, exec := buildMeanReversion(t)
+	var trace []DecisionRecord
+	mr.Trace = TraceSinkFunc(func(r DecisionRecord) { trace = append(trace, r) })
+
+	bars := waveBars(60)
+	feedBars(t, mr, bars)
+
+	if len(trace) != len(bars) {
+		t.Fatalf("expected one record per bar, got %d for %d bars", len(trace), len(bars))
+	}
+	traced := 0
+	for i, d := range trace {
+		if d.Bar != int64(i+1) || d.Close != bars[i].close {
+			t.Fatalf("record %d out of sequence: %+v", i, d)
+		}
+		traced += len(d.Orders)
+		if len(d.Orders) > 0 && (d.Action != d.Orders[0].Ctx && !strings.HasPrefix(d.Action, d.Orders[0].Ctx+"+")) {
+			t.Fatalf("bar %d: action %q does not name its orders %+v", d.Bar, d.Action, d.Orders)
+		}
+	}
+	if traced != len(exec.Orders()) || traced == 0 {
+		t.Fatalf("traced %d orders, executor saw %d", traced, len(exec.Orders()))
+	}
+	if first := trace[0]; first.Action != "hold" || first.Reason != ReasonWarmingUp {
+		t.Fatalf("first bar must hold while warming up, got %+v", first)
+	}
+	last := trace[len(trace)-1]
+	if len(last.Indicators) == 0 || len(last.Crossovers) == 0 || len(last.Signals) == 0 {
+		t.Fatalf("warm bar must carry indicators, crossovers and signals: %+v", last)
+	}
+}
+
+func TestDecisionTraceDisabledIsFree(t *testing.T) {
+	mr, _ := buildMeanReversion(t)
+	feedBars(t, mr, waveBars(30))
+	o := types.Order{Symbol: "TEST", Side: types.Buy, Qty: 1, Price: 100}
+	allocs := testing.AllocsPerRun(100, func() {
+		mr.beginDecision(101, 99, 100, 1000)
+		mr.traceOrder(o, "ctx")
+		mr.flushDecision()
+	})
+	if allocs != 0 || mr.decision != nil {
+		t.Fatalf("disabled trace allocated %.0f times (decision %v)", allocs, mr.decision)
+	}
+}
+
+func TestRenderDecisionsRange(t *testing.T) {
+	mr, _ := buildMeanReversion(t)
+	var buf bytes.Buffer
+	sink := NewJSONTraceSink(&buf)
+	mr.Trace = sink
+	feedBars(t, mr, waveBars(40))
+	if err := sink.Err(); err != nil {
+		t.Fatalf("sink error: %v", err)
+	}
+
+	var out strings.Builder
+	if err := RenderDecisions(&out, &buf, 20, 22); err != nil {
+		t.Fatalf("RenderDecisions failed: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"bar 20 TEST", "bar 21 TEST", "bar 22 TEST", "action:", "indicators:", "signals:"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("render output lacks %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"bar 19 ", "bar 23 "} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("render output contains %q outside the range:\n%s", unwanted, got)
+		}
+	}
+
+	if err := RenderDecisions(&out, strings.NewReader("{not json}\n"), 0, 0); err == nil {
+		t.Fatal("expected an error for a malformed trace line")
+	}
+}
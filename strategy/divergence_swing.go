@@ -1,7 +1,6 @@
 package strategy
 
 import (
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -17,14 +16,7 @@ type DivergenceSwing struct {
 func NewDivergenceSwing(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*DivergenceSwing, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -34,34 +26,34 @@ func NewDivergenceSwing(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar updates the suite and checks for divergence signals.
 func (d *DivergenceSwing) ProcessBar(high, low, close, volume float64) {
+	defer d.flushDecision()
 	if err := d.Suite.Add(high, low, close, volume); err != nil {
 		d.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	d.recordPrice(close)
-	if !d.hasHistory(12) {
+	d.recordBar(high, low, close, volume)
+	if d.manageExits(high, low, close) {
 		return
 	}
-	hBull := d.bullishFallback()
-	if ok, err := d.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := d.bearishFallback()
-	if ok, err := d.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
+	if !d.hasHistory(12) {
+		return
 	}
+	hBull := d.signal("hma_bull", d.Suite.GetHMA().IsBullishCrossover, d.bullishFallback)
+	hBear := d.signal("hma_bear", d.Suite.GetHMA().IsBearishCrossover, d.bearishFallback)
 
 	// Divergence checks (any oscillator may fire)
 	bullDiv, bearDiv := false, false
 
-	if ok, typ, err := d.Suite.GetRSI().IsDivergence(); err == nil && ok {
+	ok, typ, rsiErr := d.Suite.GetRSI().IsDivergence()
+	if rsiErr == nil && ok {
 		if typ == "Bullish" {
 			bullDiv = true
 		} else if typ == "Bearish" {
 			bearDiv = true
 		}
 	}
-	if dir, err := d.Suite.GetMFI().IsDivergence(); err == nil {
+	dir, mfiErr := d.Suite.GetMFI().IsDivergence()
+	if mfiErr == nil {
 		switch dir {
 		case "Bullish":
 			bullDiv = true
@@ -76,33 +68,37 @@ func (d *DivergenceSwing) ProcessBar(high, low, close, volume float64) {
 			bearDiv = true
 		}
 	}
-	if d.bullishReversal() {
-		bullDiv = true
-	}
-	if d.bearishReversal() {
-		bearDiv = true
+	// The price‑pattern reversals stand in for the oscillator divergences;
+	// the oscillators count as warming up until any of them can answer.
+	divErr := rsiErr
+	if mfiErr == nil || bullDiv || bearDiv {
+		divErr = nil
 	}
+	bullDiv = d.combineSignal("bull_divergence", bullDiv, divErr, d.bullishReversal)
+	bearDiv = d.combineSignal("bear_divergence", bearDiv, divErr, d.bearishReversal)
 
 	longCond := bullDiv && hBull
 	shortCond := bearDiv && hBear
 
-	posQty, _ := d.Exec.Position(d.Symbol)
+	posQty, _ := d.position()
 
 	switch {
 	case longCond && posQty <= 0:
+		d.setIntent(IntentLong, d.conviction("bull_divergence", "hma_bull"), close)
 		if posQty < 0 {
 			d.closePosition(close, "divergence_close_short")
 		}
 		d.openLong(close)
 
 	case shortCond && posQty >= 0:
+		d.setIntent(IntentShort, d.conviction("bear_divergence", "hma_bear"), close)
 		if posQty > 0 {
 			d.closePosition(close, "divergence_close_long")
 		}
 		d.openShort(close)
 
 	case posQty != 0:
-		if d.Cfg.TrailingPct > 0 {
+		if d.trailingEnabled() {
 			d.applyTrailingStop(close)
 		}
 	}
@@ -110,6 +106,9 @@ func (d *DivergenceSwing) ProcessBar(high, low, close, volume float64) {
 
 // openLong / openShort reuse the base helpers.
 func (d *DivergenceSwing) openLong(price float64) {
+	if !d.canOpen(types.Buy) {
+		return
+	}
 	qty := d.calcQty(price)
 	if qty <= 0 {
 		return
@@ -125,6 +124,9 @@ func (d *DivergenceSwing) openLong(price float64) {
 }
 
 func (d *DivergenceSwing) openShort(price float64) {
+	if !d.canOpen(types.Sell) {
+		return
+	}
 	qty := d.calcQty(price)
 	if qty <= 0 {
 		return
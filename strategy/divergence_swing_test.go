@@ -24,10 +24,11 @@ func buildDivergenceSwing(t *testing.T) (*DivergenceSwing, *testutils.MockExecut
 		VWAOStrongTrend:   1e9, // not used directly by this strategy
 		HMAPeriod:         9,
 		ATSEMAperiod:      5,
-		MaxRiskPerTrade:   0.01,  // 1 % of equity per trade
-		StopLossPct:       0.015, // 1.5 %
-		TakeProfitPct:     0.0,   // enable per‑test when needed
-		TrailingPct:       0.0,   // enable per‑test when needed
+		MaxRiskPerTrade:   0.01,            // 1 % of equity per trade
+		StopLossPct:       0.015,           // 1.5 %
+		TakeProfitPct:     0.0,             // enable per‑test when needed
+		TrailingPct:       0.0,             // enable per‑test when needed
+		StopType:          config.StopNone, // hard stops are tested separately
 		QuantityPrecision: 2,
 		MinQty:            0.001,
 		StepSize:          0.0001,
@@ -217,9 +218,9 @@ func TestDivergenceSwing_BearishDivergenceShort(t *testing.T) {
 -----------------------------------------------------------------------
 Test 4 – Trailing‑stop on an open long position.
 -----------------------------------------------------------------------
-After a bullish divergence opens a long, we raise the price enough to
-exceed the trailing‑stop level (`entry * (1 + TrailingPct)`).  The
-strategy should emit a SELL order that closes the position.
+After a bullish divergence opens a long, price rallies to a new high and
+then retraces more than TrailingPct from it.  The strategy should emit a
+SELL order that closes the position.
 */
 func TestDivergenceSwing_TrailingStop(t *testing.T) {
 	ds, exec := buildDivergenceSwing(t)
@@ -250,23 +251,7 @@ func TestDivergenceSwing_TrailingStop(t *testing.T) {
 	}
 	entryPrice := exec.Orders()[0].Price
 
-	// ---- Price climbs past trailing level ----
-	trailingLevel := entryPrice * 1.02
-	high := trailingLevel + 0.5
-	low := trailingLevel - 0.5
-	close := trailingLevel + 0.1
-	feedBarsDS(t, ds, []candle{{high, low, close, 1700}})
-
-	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected trailing‑stop close order, got %d (orders: %+v)", len(exec.Orders()), exec.Orders())
-	}
-	closeOrder := exec.Orders()[1]
-	if closeOrder.Side != types.Sell {
-		t.Fatalf("expected SELL to close trailing stop, got %s", closeOrder.Side)
-	}
-	if closeOrder.Price < trailingLevel {
-		t.Fatalf("trailing‑stop price %f is below expected %f", closeOrder.Price, trailingLevel)
-	}
+	checkTrailingExit(t, ds, exec, entryPrice)
 }
 
 /*
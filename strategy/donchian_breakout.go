@@ -0,0 +1,277 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// DonchianConfig holds the turtle rules of DonchianBreakout.  N is the
+// ATR over ATRPeriod bars.
+type DonchianConfig struct {
+	EntryBars int // breakout of the prior EntryBars high/low opens a unit
+	ExitBars  int // break of the prior ExitBars opposite extreme exits
+	ATRPeriod int
+
+	MaxUnits int     // units held at most, the first one included
+	AddStepN float64 // another unit every AddStepN × N beyond the last entry
+	StopN    float64 // stop StopN × N behind the last entry
+}
+
+// DefaultDonchianConfig is the classic turtle System 1: 20‑bar entries,
+// 10‑bar exits, a 20‑bar N, up to 4 units added every ½N and a 2N stop.
+func DefaultDonchianConfig() DonchianConfig {
+	return DonchianConfig{
+		EntryBars: 20,
+		ExitBars:  10,
+		ATRPeriod: 20,
+		MaxUnits:  4,
+		AddStepN:  0.5,
+		StopN:     2,
+	}
+}
+
+// Validate checks the turtle rules.
+func (dc DonchianConfig) Validate() error {
+	if dc.EntryBars < 2 || dc.EntryBars > maxReturnHistory {
+		return fmt.Errorf("EntryBars (%d) must be between 2 and %d", dc.EntryBars, maxReturnHistory)
+	}
+	if dc.ExitBars < 1 || dc.ExitBars >= dc.EntryBars {
+		return fmt.Errorf("ExitBars (%d) must be between 1 and EntryBars (%d)", dc.ExitBars, dc.EntryBars)
+	}
+	if dc.ATRPeriod < 1 || dc.ATRPeriod > 63 {
+		return fmt.Errorf("ATRPeriod (%d) must be between 1 and 63", dc.ATRPeriod)
+	}
+	if dc.MaxUnits < 1 {
+		return fmt.Errorf("MaxUnits (%d) must be at least 1", dc.MaxUnits)
+	}
+	if dc.AddStepN <= 0 || dc.StopN <= 0 {
+		return fmt.Errorf("AddStepN (%f) and StopN (%f) must be positive", dc.AddStepN, dc.StopN)
+	}
+	return nil
+}
+
+// DonchianBreakout is a classic channel (turtle) breakout: a close beyond
+// the prior EntryBars high (low) opens a long (short) unit sized so that a
+// move of N costs MaxRiskPerTrade of equity; further units are added every
+// AddStepN × N in favour up to MaxUnits, and the stop of the whole
+// position trails StopN × N behind the last entry.  The position is
+// closed when the close breaks the prior ExitBars opposite extreme.
+//
+// The hard stop and exits of BaseStrategy still apply; StopType none leaves
+// the stop to the turtle rules alone.
+type DonchianBreakout struct {
+	*BaseStrategy
+	dc  DonchianConfig
+	atr *goti.AverageTrueRange
+
+	units     int
+	lastEntry float64 // price of the last unit
+	stop      float64
+	n         float64 // N when the last unit was added
+}
+
+// NewDonchianBreakout builds the suite and the ATR with
+// DefaultDonchianConfig.
+func NewDonchianBreakout(symbol string, cfg config.StrategyConfig,
+	exec executor.Executor, log logger.Logger) (*DonchianBreakout, error) {
+
+	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactoryFor(cfg), log)
+	if err != nil {
+		return nil, err
+	}
+	d := &DonchianBreakout{BaseStrategy: base, dc: DefaultDonchianConfig()}
+	if d.atr, err = goti.NewAverageTrueRangeWithParams(d.dc.ATRPeriod); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// SetDonchianConfig replaces the turtle rules after validating them.  The
+// ATR is rebuilt from the journaled bars.
+func (d *DonchianBreakout) SetDonchianConfig(dc DonchianConfig) error {
+	if err := dc.Validate(); err != nil {
+		return logOutputError(d.Log, err.Error())
+	}
+	d.dc = dc
+	return d.replayATR()
+}
+
+// Units returns the number of units currently held.
+func (d *DonchianBreakout) Units() int { return d.units }
+
+// Stop returns the stop level of the open position (0 when flat).
+func (d *DonchianBreakout) Stop() float64 { return d.stop }
+
+// N returns the ATR the last unit was sized with (0 when flat).
+func (d *DonchianBreakout) N() float64 { return d.n }
+
+// ProcessBar updates the channels and N, then applies the stop, the exit
+// channel, pyramiding and the entry breakout in that order.
+func (d *DonchianBreakout) ProcessBar(high, low, close, volume float64) {
+	defer d.flushDecision()
+	if err := d.Suite.Add(high, low, close, volume); err != nil {
+		d.Log.Warn("suite_add_error", logger.Err(err))
+		return
+	}
+	if err := d.atr.AddCandle(high, low, close); err != nil {
+		d.Log.Warn("atr_add_error", logger.Err(err))
+	}
+	d.recordBar(high, low, close, volume)
+	if d.manageExits(high, low, close) {
+		d.resetUnits()
+		return
+	}
+	qty, _ := d.position()
+	if qty == 0 {
+		d.resetUnits()
+	}
+
+	n, err := d.atr.Calculate()
+	entryHigh, entryLow, ok := d.channel(d.dc.EntryBars)
+	exitHigh, exitLow, _ := d.channel(d.dc.ExitBars)
+	ready := ok && err == nil && n > 0
+	breakUp := d.recordSignal("donchian_high", ready, ready && close > entryHigh, false)
+	breakDown := d.recordSignal("donchian_low", ready, ready && close < entryLow, false)
+	if !ready {
+		return
+	}
+
+	switch {
+	case qty > 0 && d.units > 0:
+		switch {
+		case low <= d.stop:
+			d.exitAtStop(high, close, "donchian_stop")
+		case close < exitLow:
+			d.closePosition(close, "donchian_exit")
+			d.resetUnits()
+		case d.units < d.dc.MaxUnits && close >= d.lastEntry+d.dc.AddStepN*d.n:
+			d.addUnit(types.Buy, close, n, "donchian_add")
+		}
+	case qty < 0 && d.units > 0:
+		switch {
+		case high >= d.stop:
+			d.exitAtStop(low, close, "donchian_stop")
+		case close > exitHigh:
+			d.closePosition(close, "donchian_exit")
+			d.resetUnits()
+		case d.units < d.dc.MaxUnits && close <= d.lastEntry-d.dc.AddStepN*d.n:
+			d.addUnit(types.Sell, close, n, "donchian_add")
+		}
+	case qty == 0 && breakUp:
+		d.setIntent(IntentLong, d.conviction("donchian_high"), close)
+		d.addUnit(types.Buy, close, n, "donchian_long")
+	case qty == 0 && breakDown:
+		d.setIntent(IntentShort, d.conviction("donchian_low"), close)
+		d.addUnit(types.Sell, close, n, "donchian_short")
+	}
+}
+
+// channel returns the highest high and lowest low of the n bars before the
+// current one.
+func (d *DonchianBreakout) channel(n int) (hi, lo float64, ok bool) {
+	if len(d.bars) < n+1 {
+		return 0, 0, false
+	}
+	prior := d.bars[len(d.bars)-1-n : len(d.bars)-1]
+	hi, lo = prior[0].High, prior[0].Low
+	for _, b := range prior[1:] {
+		hi = math.Max(hi, b.High)
+		lo = math.Min(lo, b.Low)
+	}
+	return hi, lo, true
+}
+
+// unitQty sizes one unit: a move of n costs MaxRiskPerTrade of equity.
+// Longs are capped by the available cash.
+func (d *DonchianBreakout) unitQty(side types.Side, price, n float64) float64 {
+	equity := d.Exec.Equity()
+	qty := equity * d.Cfg.MaxRiskPerTrade / n
+	if side == types.Buy {
+		qty = math.Min(qty, equity/price)
+	}
+	return risk.RoundQty(qty, d.Cfg)
+}
+
+// addUnit opens the first unit or pyramids another one and moves the stop
+// of the whole position StopN × n behind it.  Signal‑only strategies
+// count the unit without sizing it.
+func (d *DonchianBreakout) addUnit(side types.Side, price, n float64, ctx string) {
+	if !d.canOpen(side) {
+		return
+	}
+	qty := d.unitQty(side, price, n)
+	if qty <= 0 && !d.signalOnly {
+		d.Log.Info("donchian_unit_skipped",
+			logger.String("symbol", d.Symbol),
+			logger.String("ctx", ctx),
+		)
+		return
+	}
+	o := types.Order{Symbol: d.Symbol, Side: side, Qty: qty, Price: price, Comment: ctx}
+	if d.submitOrder(o, ctx) != nil {
+		return
+	}
+	d.units++
+	d.lastEntry, d.n = price, n
+	if side == types.Buy {
+		d.stop = price - d.dc.StopN*n
+	} else {
+		d.stop = price + d.dc.StopN*n
+	}
+	d.Log.Info("donchian_unit",
+		logger.String("symbol", d.Symbol),
+		logger.Int("units", d.units),
+		logger.Float64("n", n),
+		logger.Float64("stop", d.stop),
+	)
+}
+
+// exitAtStop closes the position at the stop, or at the close when the
+// whole bar gapped through it (far is the bar extreme on the safe side).
+func (d *DonchianBreakout) exitAtStop(far, close float64, ctx string) {
+	fill := d.stop
+	qty, _ := d.position()
+	if (qty > 0 && far < d.stop) || (qty < 0 && far > d.stop) {
+		fill = close
+	}
+	d.closePosition(fill, ctx)
+	d.resetUnits()
+}
+
+func (d *DonchianBreakout) resetUnits() {
+	d.units, d.lastEntry, d.stop, d.n = 0, 0, 0, 0
+}
+
+// replayATR rebuilds the ATR from the bar journal.
+func (d *DonchianBreakout) replayATR() error {
+	atr, err := goti.NewAverageTrueRangeWithParams(d.dc.ATRPeriod)
+	if err != nil {
+		return err
+	}
+	for _, b := range d.bars {
+		_ = atr.AddCandle(b.High, b.Low, b.Close)
+	}
+	d.atr = atr
+	return nil
+}
+
+// WarmupBars returns the history Warmup needs before the first breakout.
+func (d *DonchianBreakout) WarmupBars() int {
+	return d.warmupBars(max(d.dc.EntryBars, d.dc.ATRPeriod) + 1)
+}
+
+// Warmup feeds historical bars to the suite, the channels and the ATR
+// without trading.
+func (d *DonchianBreakout) Warmup(bars []types.Bar) {
+	d.warmup(bars)
+	for _, b := range bars {
+		_ = d.atr.AddCandle(b.High, b.Low, b.Close)
+	}
+}
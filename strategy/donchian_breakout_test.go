@@ -0,0 +1,216 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+func buildDonchian(t *testing.T, cfg config.StrategyConfig) (*DonchianBreakout, *testutils.MockExecutor, *testutils.MockLogger) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	log := testutils.NewMockLogger()
+	d, err := NewDonchianBreakout("TEST", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewDonchianBreakout failed: %v", err)
+	}
+	return d, exec, log
+}
+
+// rangeBars returns n bars two units wide around close c, so N settles at 2.
+func rangeBars(n int, c float64) []candle {
+	bars := make([]candle, n)
+	for i := range bars {
+		bars[i] = candle{c + 1, c - 1, c, 1000}
+	}
+	return bars
+}
+
+// trendBars returns one bar per close, each two units wide.
+func trendBars(closes ...float64) []candle {
+	bars := make([]candle, len(closes))
+	for i, c := range closes {
+		bars[i] = candle{c + 1, c - 1, c, 1000}
+	}
+	return bars
+}
+
+func TestDonchianLongBreakoutPyramidsAndStops(t *testing.T) {
+	d, exec, log := buildDonchian(t, buildConfig())
+	feedBars(t, d, rangeBars(30, 100))
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("no breakout inside the range, got %+v", exec.Orders())
+	}
+
+	feedBars(t, d, trendBars(102))
+	if d.Units() != 1 || exec.Orders()[0].Comment != "donchian_long" {
+		t.Fatalf("expected the first long unit, units %d orders %+v", d.Units(), exec.Orders())
+	}
+	if n := d.N(); n < 2 || n > 2.1 {
+		t.Fatalf("N %v, want about the 2 wide bars", n)
+	}
+	if stop := d.Stop(); math.Abs(stop-(102-2*d.N())) > 1e-9 {
+		t.Fatalf("stop %v, want 2N below the entry", stop)
+	}
+
+	// One unit every ½N ≈ 1.03 in favour, up to four.
+	feedBars(t, d, trendBars(103.1, 104.2, 105.3, 106.4))
+	if d.Units() != 4 {
+		t.Fatalf("expected 4 units, got %d", d.Units())
+	}
+	adds := 0
+	for _, o := range exec.Orders() {
+		if o.Comment == "donchian_add" {
+			adds++
+			if o.Side != types.Buy {
+				t.Fatalf("pyramiding must add to the long: %+v", o)
+			}
+		}
+	}
+	if adds != 3 {
+		t.Fatalf("expected 3 adds, got %d", adds)
+	}
+	if stop := d.Stop(); math.Abs(stop-(105.3-2*d.N())) > 1e-9 {
+		t.Fatalf("stop %v must trail 2N behind the last unit at 105.3", stop)
+	}
+
+	// A bar through the stop closes the whole position at the stop.
+	stop := d.Stop()
+	d.ProcessBar(105, 100, 100.5, 1000)
+	if pos, _ := exec.Position("TEST"); pos != 0 {
+		t.Fatalf("stop must flatten the position, got %v", pos)
+	}
+	last := exec.Orders()[len(exec.Orders())-1]
+	if last.Comment != "donchian_stop" || last.Price != stop {
+		t.Fatalf("expected a stop exit at %v, got %+v", stop, last)
+	}
+	if d.Units() != 0 || d.Stop() != 0 || !log.HasMessage("donchian_unit") {
+		t.Fatalf("units must reset after the stop, units %d stop %v", d.Units(), d.Stop())
+	}
+}
+
+func TestDonchianExitChannel(t *testing.T) {
+	d, exec, _ := buildDonchian(t, buildConfig())
+	dc := DefaultDonchianConfig()
+	dc.StopN = 10 // keep the stop out of the way
+	if err := d.SetDonchianConfig(dc); err != nil {
+		t.Fatalf("SetDonchianConfig failed: %v", err)
+	}
+	feedBars(t, d, rangeBars(30, 100))
+	feedBars(t, d, trendBars(102, 102.5, 102, 102.5))
+	if d.Units() != 1 {
+		t.Fatalf("expected a single unit, got %d", d.Units())
+	}
+	// 98 is below the lowest low of the prior ten bars (99).
+	feedBars(t, d, trendBars(98))
+	if pos, _ := exec.Position("TEST"); pos != 0 {
+		t.Fatalf("exit channel must flatten the position, got %v", pos)
+	}
+	if last := exec.Orders()[len(exec.Orders())-1]; last.Comment != "donchian_exit" {
+		t.Fatalf("expected a channel exit, got %+v", last)
+	}
+}
+
+func TestDonchianShortBreakout(t *testing.T) {
+	d, exec, _ := buildDonchian(t, buildConfig())
+	feedBars(t, d, rangeBars(30, 100))
+	feedBars(t, d, trendBars(98, 96.5))
+	if pos, _ := exec.Position("TEST"); pos >= 0 || d.Units() != 2 {
+		t.Fatalf("expected two short units, position %v units %d", pos, d.Units())
+	}
+	if stop := d.Stop(); math.Abs(stop-(96.5+2*d.N())) > 1e-9 {
+		t.Fatalf("short stop %v must sit above the last entry", stop)
+	}
+
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	d, exec, log := buildDonchian(t, cfg)
+	feedBars(t, d, rangeBars(30, 100))
+	feedBars(t, d, trendBars(98, 97))
+	if len(exec.Orders()) != 0 || d.Units() != 0 {
+		t.Fatalf("long‑only must skip the short breakout, got %+v", exec.Orders())
+	}
+	if !log.HasMessage("entry_skipped_position_mode") {
+		t.Fatal("expected the skipped short to be logged")
+	}
+}
+
+func TestDonchianSnapshotRestore(t *testing.T) {
+	history := rangeBars(30, 100)
+	live := trendBars(102, 103, 104, 103.5, 105, 106, 104, 101, 99, 97, 96, 95)
+
+	straight, straightExec, _ := buildDonchian(t, buildConfig())
+	feedBars(t, straight, history)
+	feedBars(t, straight, live)
+
+	first, exec, _ := buildDonchian(t, buildConfig())
+	feedBars(t, first, history)
+	feedBars(t, first, live[:3])
+	data, err := first.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewDonchianBreakout("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDonchianBreakout failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if resumed.Units() != first.Units() || resumed.Stop() != first.Stop() {
+		t.Fatalf("restored units %d stop %v, want %d %v",
+			resumed.Units(), resumed.Stop(), first.Units(), first.Stop())
+	}
+	feedBars(t, resumed, live[3:])
+
+	want, got := straightExec.Orders(), exec.Orders()
+	if len(want) < 2 || len(want) != len(got) {
+		t.Fatalf("resumed strategy diverged:\nwant %+v\ngot  %+v", want, got)
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("order %d: want %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestDonchianWarmup(t *testing.T) {
+	d, exec, _ := buildDonchian(t, buildConfig())
+	if n := d.WarmupBars(); n < 21 {
+		t.Fatalf("WarmupBars %d must cover the entry channel", n)
+	}
+	bars := make([]types.Bar, 30)
+	for i := range bars {
+		bars[i] = types.Bar{High: 101, Low: 99, Close: 100, Volume: 1000}
+	}
+	d.Warmup(bars)
+	if len(exec.Orders()) != 0 {
+		t.Fatal("Warmup must not trade")
+	}
+	feedBars(t, d, trendBars(102))
+	if d.Units() != 1 {
+		t.Fatalf("the first live breakout after Warmup must trade, units %d", d.Units())
+	}
+}
+
+func TestDonchianConfigValidate(t *testing.T) {
+	if err := DefaultDonchianConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []DonchianConfig{
+		{EntryBars: 1, ExitBars: 1, ATRPeriod: 20, MaxUnits: 4, AddStepN: 0.5, StopN: 2},
+		{EntryBars: 20, ExitBars: 20, ATRPeriod: 20, MaxUnits: 4, AddStepN: 0.5, StopN: 2},
+		{EntryBars: 20, ExitBars: 10, ATRPeriod: 0, MaxUnits: 4, AddStepN: 0.5, StopN: 2},
+		{EntryBars: 20, ExitBars: 10, ATRPeriod: 20, MaxUnits: 0, AddStepN: 0.5, StopN: 2},
+		{EntryBars: 20, ExitBars: 10, ATRPeriod: 20, MaxUnits: 4, AddStepN: 0, StopN: 2},
+		{EntryBars: 20, ExitBars: 10, ATRPeriod: 20, MaxUnits: 4, AddStepN: 0.5, StopN: -1},
+	}
+	for i, dc := range bad {
+		if err := dc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, dc)
+		}
+	}
+}
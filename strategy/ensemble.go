@@ -0,0 +1,286 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/types"
+)
+
+// EnsembleMember is a strategy the Ensemble can run in signal‑only mode.
+// Every strategy built on BaseStrategy that decides a side to hold
+// qualifies; SetSignalOnly fails for the others.
+type EnsembleMember interface {
+	ProcessBar(high, low, close, volume float64)
+	Intent() Intent
+	SetSignalOnly() error
+}
+
+// VoteMethod selects how Ensemble combines the intents of its members.
+type VoteMethod string
+
+const (
+	// VoteMajority holds the intent most members share; ties are flat.
+	VoteMajority VoteMethod = "majority"
+	// VoteWeighted holds a side when the weighted mean intent reaches
+	// ±Threshold.
+	VoteWeighted VoteMethod = "weighted"
+	// VotePerformance is VoteWeighted with every weight scaled by the
+	// risk‑adjusted return of holding the member's intent over recent bars
+	// (negative performance counts as zero).
+	VotePerformance VoteMethod = "performance"
+)
+
+// EnsembleConfig tunes Ensemble beyond the shared StrategyConfig.
+type EnsembleConfig struct {
+	Method VoteMethod
+	// Threshold is the |weighted mean intent| in (0, 1] needed to hold a
+	// side with VoteWeighted and VotePerformance.
+	Threshold float64
+	// PerformanceBars is the window of member returns VotePerformance
+	// ranks; static weights apply until it is filled.
+	PerformanceBars int
+}
+
+// DefaultEnsembleConfig is a plain majority vote.
+func DefaultEnsembleConfig() EnsembleConfig {
+	return EnsembleConfig{Method: VoteMajority, Threshold: 0.5, PerformanceBars: 50}
+}
+
+// Validate checks the ensemble settings.
+func (ec EnsembleConfig) Validate() error {
+	switch ec.Method {
+	case VoteMajority, VoteWeighted, VotePerformance:
+	default:
+		return fmt.Errorf("unknown vote method %q", ec.Method)
+	}
+	if ec.Threshold <= 0 || ec.Threshold > 1 {
+		return fmt.Errorf("Threshold (%f) must be in (0, 1]", ec.Threshold)
+	}
+	if ec.PerformanceBars < 2 || ec.PerformanceBars > maxReturnHistory {
+		return fmt.Errorf("PerformanceBars (%d) must be between 2 and %d", ec.PerformanceBars, maxReturnHistory)
+	}
+	return nil
+}
+
+// ensembleMember is a member with its static weight and the returns its
+// intent would have earned.
+type ensembleMember struct {
+	strat   EnsembleMember
+	weight  float64
+	intent  Intent // after the previous bar
+	returns []float64
+}
+
+// Ensemble runs several strategies on one symbol in signal‑only mode,
+// combines their intents with the configured VoteMethod and manages a
+// single net position on the real executor: a change of the combined
+// intent closes the position and opens the new side with the configured
+// sizer.  The hard stop and exits of BaseStrategy apply to the net
+// position; after such an exit the ensemble waits for the combined intent
+// to change before trading again.
+type Ensemble struct {
+	*BaseStrategy
+	ec        EnsembleConfig
+	members   []*ensembleMember
+	intent    Intent
+	score     float64
+	lastClose float64
+	exited    bool // closed by the base exits under the current intent
+}
+
+// NewEnsemble builds an ensemble without members and DefaultEnsembleConfig.
+func NewEnsemble(symbol string, cfg config.StrategyConfig,
+	exec executor.Executor, log logger.Logger) (*Ensemble, error) {
+
+	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactoryFor(cfg), log)
+	if err != nil {
+		return nil, err
+	}
+	return &Ensemble{BaseStrategy: base, ec: DefaultEnsembleConfig()}, nil
+}
+
+// SetEnsembleConfig replaces the vote settings after validating them.
+func (e *Ensemble) SetEnsembleConfig(ec EnsembleConfig) error {
+	if err := ec.Validate(); err != nil {
+		return logOutputError(e.Log, err.Error())
+	}
+	e.ec = ec
+	return nil
+}
+
+// Add switches m to signal‑only mode and adds it with the given positive
+// weight.  Members must trade the ensemble's symbol; a member that cannot
+// run signal‑only is rejected.
+func (e *Ensemble) Add(m EnsembleMember, weight float64) error {
+	if m == nil || weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return logOutputError(e.Log, "ensemble member needs a strategy and a positive weight")
+	}
+	if err := m.SetSignalOnly(); err != nil {
+		e.Log.Error("configuration_error", logger.String("msg", "ensemble member rejected"), logger.Err(err))
+		return err
+	}
+	e.members = append(e.members, &ensembleMember{strat: m, weight: weight, intent: m.Intent()})
+	return nil
+}
+
+// Vote returns the combined intent of the last bar and the weighted mean
+// intent it came from (the net vote share for VoteMajority).
+func (e *Ensemble) Vote() (Intent, float64) { return e.intent, e.score }
+
+// ProcessBar feeds the bar to every member, combines their intents and
+// trades the net position towards the result.
+func (e *Ensemble) ProcessBar(high, low, close, volume float64) {
+	defer e.flushDecision()
+	for _, m := range e.members {
+		if e.lastClose > 0 {
+			m.returns = appendBounded(m.returns, float64(m.intent)*(close/e.lastClose-1), maxReturnHistory)
+		}
+		m.strat.ProcessBar(high, low, close, volume)
+		m.intent = m.strat.Intent()
+	}
+	e.lastClose = close
+	if err := e.Suite.Add(high, low, close, volume); err != nil {
+		e.Log.Warn("suite_add_error", logger.Err(err))
+		return
+	}
+	e.recordBar(high, low, close, volume)
+
+	prev := e.intent
+	e.intent, e.score = e.combine()
+	e.recordSignal("ensemble_long", len(e.members) > 0, e.intent == IntentLong, false)
+	e.recordSignal("ensemble_short", len(e.members) > 0, e.intent == IntentShort, false)
+	if e.intent != prev {
+		e.Log.Info("ensemble_vote",
+			logger.String("symbol", e.Symbol),
+			logger.String("intent", e.intent.String()),
+			logger.Float64("score", e.score),
+		)
+		e.exited = false
+	}
+	if e.manageExits(high, low, close) {
+		e.exited = true
+		return
+	}
+
+	qty, _ := e.position()
+	held := sideOf(qty)
+	if held == e.intent || e.exited {
+		return
+	}
+	e.setIntent(e.intent, math.Abs(e.score), close)
+	if held != IntentFlat {
+		e.closePosition(close, "ensemble_exit")
+	}
+	switch e.intent {
+	case IntentLong:
+		e.open(types.Buy, close, "ensemble_long")
+	case IntentShort:
+		e.open(types.Sell, close, "ensemble_short")
+	}
+}
+
+// combine applies the vote method to the members' intents.
+func (e *Ensemble) combine() (Intent, float64) {
+	if len(e.members) == 0 {
+		return IntentFlat, 0
+	}
+	if e.ec.Method == VoteMajority {
+		votes := map[Intent]int{}
+		for _, m := range e.members {
+			votes[m.intent]++
+		}
+		score := float64(votes[IntentLong]-votes[IntentShort]) / float64(len(e.members))
+		switch {
+		case votes[IntentLong] > votes[IntentShort] && votes[IntentLong] > votes[IntentFlat]:
+			return IntentLong, score
+		case votes[IntentShort] > votes[IntentLong] && votes[IntentShort] > votes[IntentFlat]:
+			return IntentShort, score
+		default:
+			return IntentFlat, score
+		}
+	}
+
+	sum, total := 0.0, 0.0
+	for _, m := range e.members {
+		w := m.weight
+		if e.ec.Method == VotePerformance {
+			w *= m.performance(e.ec.PerformanceBars)
+		}
+		sum += w * float64(m.intent)
+		total += w
+	}
+	if total == 0 {
+		return IntentFlat, 0
+	}
+	score := sum / total
+	switch {
+	case score >= e.ec.Threshold:
+		return IntentLong, score
+	case score <= -e.ec.Threshold:
+		return IntentShort, score
+	default:
+		return IntentFlat, score
+	}
+}
+
+// performance is the weight factor of VotePerformance: the mean over the
+// standard deviation of the last n returns, floored at 0, or 1 while fewer
+// than n returns are known.
+func (m *ensembleMember) performance(n int) float64 {
+	if len(m.returns) < n {
+		return 1
+	}
+	recent := m.returns[len(m.returns)-n:]
+	mean := 0.0
+	for _, r := range recent {
+		mean += r
+	}
+	mean /= float64(n)
+	variance := 0.0
+	for _, r := range recent {
+		variance += (r - mean) * (r - mean)
+	}
+	sd := math.Sqrt(variance / float64(n))
+	if sd == 0 || mean <= 0 {
+		return 0
+	}
+	return mean / sd
+}
+
+// open enters the combined intent with the configured sizer.
+func (e *Ensemble) open(side types.Side, price float64, ctx string) {
+	if !e.canOpen(side) {
+		return
+	}
+	qty := e.calcQty(price)
+	if qty <= 0 {
+		return
+	}
+	o := types.Order{Symbol: e.Symbol, Side: side, Qty: qty, Price: price, Comment: ctx}
+	_ = e.submitOrder(o, ctx)
+}
+
+// WarmupBars returns the longest warm‑up of the ensemble and its members.
+func (e *Ensemble) WarmupBars() int {
+	n := e.warmupBars(0)
+	for _, m := range e.members {
+		if w, ok := m.strat.(interface{ WarmupBars() int }); ok {
+			n = max(n, w.WarmupBars())
+		}
+	}
+	return n
+}
+
+// Warmup feeds historical bars to the ensemble and every member.
+func (e *Ensemble) Warmup(bars []types.Bar) {
+	e.warmup(bars)
+	for _, m := range e.members {
+		if w, ok := m.strat.(interface{ Warmup([]types.Bar) }); ok {
+			w.Warmup(bars)
+		}
+	}
+}
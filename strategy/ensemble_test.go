@@ -0,0 +1,218 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+// scriptedMember replays a fixed sequence of intents, one per bar.
+type scriptedMember struct {
+	script     []Intent
+	bar        int
+	signalOnly bool
+}
+
+func (s *scriptedMember) ProcessBar(high, low, close, volume float64) { s.bar++ }
+func (s *scriptedMember) SetSignalOnly() error                        { s.signalOnly = true; return nil }
+func (s *scriptedMember) Intent() Intent {
+	if s.bar == 0 {
+		return IntentFlat
+	}
+	return s.script[min(s.bar, len(s.script))-1]
+}
+
+func buildEnsemble(t *testing.T, ec EnsembleConfig, members []EnsembleMember, weights []float64) (*Ensemble, *testutils.MockExecutor) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	e, err := NewEnsemble("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewEnsemble failed: %v", err)
+	}
+	if err := e.SetEnsembleConfig(ec); err != nil {
+		t.Fatalf("SetEnsembleConfig failed: %v", err)
+	}
+	for i, m := range members {
+		if err := e.Add(m, weights[i]); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	return e, exec
+}
+
+const (
+	L = IntentLong
+	S = IntentShort
+	F = IntentFlat
+)
+
+func TestEnsembleMajorityManagesOneNetPosition(t *testing.T) {
+	members := []EnsembleMember{
+		&scriptedMember{script: []Intent{L, L, L, S, F}},
+		&scriptedMember{script: []Intent{F, L, L, S, S}},
+		&scriptedMember{script: []Intent{F, F, S, S, S}},
+	}
+	e, exec := buildEnsemble(t, DefaultEnsembleConfig(), members, []float64{1, 1, 1})
+	for _, m := range members {
+		if !m.(*scriptedMember).signalOnly {
+			t.Fatal("Add must switch members to signal‑only mode")
+		}
+	}
+
+	want := []Intent{F, L, L, S, S}
+	for i, w := range want {
+		e.ProcessBar(100.5, 99.5, 100, 1000)
+		if got, _ := e.Vote(); got != w {
+			t.Fatalf("bar %d: vote %v, want %v", i, got, w)
+		}
+		if held := e.BaseStrategy.Intent(); held != w {
+			t.Fatalf("bar %d: net position %v, want %v", i, held, w)
+		}
+	}
+	var comments []string
+	for _, o := range exec.Orders() {
+		comments = append(comments, o.Comment)
+	}
+	if len(comments) != 3 || comments[0] != "ensemble_long" || comments[1] != "ensemble_exit" || comments[2] != "ensemble_short" {
+		t.Fatalf("expected one long, its exit and one short, got %v", comments)
+	}
+}
+
+func TestEnsembleWeightedThreshold(t *testing.T) {
+	members := []EnsembleMember{
+		&scriptedMember{script: []Intent{L, L}},
+		&scriptedMember{script: []Intent{S, F}},
+		&scriptedMember{script: []Intent{F, F}},
+	}
+	ec := EnsembleConfig{Method: VoteWeighted, Threshold: 0.5, PerformanceBars: 10}
+	e, _ := buildEnsemble(t, ec, members, []float64{3, 1, 1})
+
+	e.ProcessBar(100.5, 99.5, 100, 1000)
+	if got, score := e.Vote(); got != F || score != 0.4 {
+		t.Fatalf("(3−1)/5 = 0.4 is below the threshold, got %v %v", got, score)
+	}
+	e.ProcessBar(100.5, 99.5, 100, 1000)
+	if got, score := e.Vote(); got != L || score != 0.6 {
+		t.Fatalf("3/5 = 0.6 must go long, got %v %v", got, score)
+	}
+}
+
+func TestEnsemblePerformanceWeighting(t *testing.T) {
+	// In a steady rally the member that stays long earns, the one that
+	// stays short loses; its weight drops to zero once the window fills.
+	members := []EnsembleMember{
+		&scriptedMember{script: []Intent{L}},
+		&scriptedMember{script: []Intent{S}},
+	}
+	ec := EnsembleConfig{Method: VotePerformance, Threshold: 0.5, PerformanceBars: 5}
+	e, exec := buildEnsemble(t, ec, members, []float64{1, 4})
+
+	price := 100.0
+	for i := range 10 {
+		price *= 1 + 0.002*float64(1+i%3)
+		e.ProcessBar(price+0.5, price-0.5, price, 1000)
+		got, _ := e.Vote()
+		switch {
+		case i < 5 && got != S:
+			t.Fatalf("bar %d: the static weights favour the short member, got %v", i, got)
+		case i >= 6 && got != L:
+			t.Fatalf("bar %d: performance must hand the vote to the long member, got %v", i, got)
+		}
+	}
+	if pos, _ := exec.Position("TEST"); pos <= 0 {
+		t.Fatalf("expected the net position to follow the vote, got %v", pos)
+	}
+}
+
+func TestEnsembleRealMembersTradeOnlyThroughIt(t *testing.T) {
+	exec := testutils.NewMockExecutor(10_000)
+	log := testutils.NewMockLogger()
+	e, err := NewEnsemble("TEST", buildConfig(), exec, log)
+	if err != nil {
+		t.Fatalf("NewEnsemble failed: %v", err)
+	}
+	var members []*DonchianBreakout
+	for range 2 {
+		d, err := NewDonchianBreakout("TEST", buildConfig(), exec, log)
+		if err != nil {
+			t.Fatalf("NewDonchianBreakout failed: %v", err)
+		}
+		if err := e.Add(d, 1); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		members = append(members, d)
+	}
+
+	feedBars(t, e, rangeBars(30, 100))
+	feedBars(t, e, trendBars(102))
+	for i, d := range members {
+		if d.Intent() != IntentLong || d.Units() != 1 {
+			t.Fatalf("member %d must record its breakout without trading, intent %v", i, d.Intent())
+		}
+	}
+	orders := exec.Orders()
+	if len(orders) != 1 || orders[0].Comment != "ensemble_long" || orders[0].Side != types.Buy {
+		t.Fatalf("only the ensemble may trade the real executor, got %+v", orders)
+	}
+}
+
+func TestEnsembleRejectsMembersWithOwnExecutor(t *testing.T) {
+	e, _ := buildEnsemble(t, DefaultEnsembleConfig(), nil, nil)
+	m, exec, _ := buildMarketMaker(t, buildConfig(), mmConfig())
+	if err := e.Add(m, 1); !errors.Is(err, ErrSignalOnlyUnsupported) {
+		t.Fatalf("a market maker quotes on its own executor and must be rejected, got %v", err)
+	}
+	if err := m.SetTargetMode(TargetSinkFunc(func(TargetPosition) {})); !errors.Is(err, ErrSignalOnlyUnsupported) {
+		t.Fatalf("expected target mode to be refused, got %v", err)
+	}
+	if m.Exec != executor.Executor(exec) {
+		t.Fatal("a rejected member must keep its executor")
+	}
+}
+
+func TestEnsembleWaitsAfterStop(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopPct
+	exec := testutils.NewMockExecutor(10_000)
+	e, err := NewEnsemble("TEST", cfg, exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewEnsemble failed: %v", err)
+	}
+	if err := e.Add(&scriptedMember{script: []Intent{L}}, 1); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	e.ProcessBar(100.5, 99.5, 100, 1000)
+	e.ProcessBar(100, 95, 96, 1000) // through the 1.5 % stop
+	e.ProcessBar(97, 95, 96, 1000)
+	if n := len(exec.Orders()); n != 2 {
+		t.Fatalf("expected the entry and the stop exit only, got %+v", exec.Orders())
+	}
+	if pos, _ := exec.Position("TEST"); pos != 0 {
+		t.Fatalf("no re‑entry while the vote is unchanged, position %v", pos)
+	}
+}
+
+func TestEnsembleConfigValidate(t *testing.T) {
+	if err := DefaultEnsembleConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []EnsembleConfig{
+		{Method: "unanimous", Threshold: 0.5, PerformanceBars: 10},
+		{Method: VoteWeighted, Threshold: 0, PerformanceBars: 10},
+		{Method: VoteWeighted, Threshold: 1.5, PerformanceBars: 10},
+		{Method: VotePerformance, Threshold: 0.5, PerformanceBars: 1},
+	}
+	for i, ec := range bad {
+		if err := ec.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, ec)
+		}
+	}
+	e, _ := buildEnsemble(t, DefaultEnsembleConfig(), nil, nil)
+	if err := e.Add(&scriptedMember{}, 0); err == nil {
+		t.Fatal("expected a zero weight to be rejected")
+	}
+}
@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -25,15 +24,7 @@ func NewEventDriven(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger,
 	eventThreshold float64, maxHoldingBars int) (*EventDriven, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		ic.ATSEMAperiod = cfg.ATSEMAperiod
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -55,7 +46,8 @@ func (e *EventDriven) SetEventActive(active bool) {
 	}
 	if !active {
 		e.armed = false
-		if qty, _ := e.Exec.Position(e.Symbol); qty != 0 {
+		if qty, _ := e.position(); qty != 0 {
+			e.setIntent(IntentFlat, 0, e.lastClose())
 			e.closePosition(e.lastClose(), "event_inactive_close")
 		}
 	}
@@ -63,17 +55,21 @@ func (e *EventDriven) SetEventActive(active bool) {
 
 // ProcessBar handles each incoming candle.
 func (e *EventDriven) ProcessBar(high, low, close, volume float64) {
+	defer e.flushDecision()
 	if err := e.Suite.Add(high, low, close, volume); err != nil {
 		e.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	e.recordPrice(close)
+	e.recordBar(high, low, close, volume)
+	if e.manageExits(high, low, close) {
+		return
+	}
 	if !e.hasHistory(15) {
 		return
 	}
 
 	// If we already have a position, manage it first.
-	if qty, _ := e.Exec.Position(e.Symbol); qty != 0 {
+	if qty, _ := e.position(); qty != 0 {
 		e.barSinceEntry++
 		e.manageOpenPosition(close)
 		if e.barSinceEntry >= e.maxHoldingBars {
@@ -88,14 +84,8 @@ func (e *EventDriven) ProcessBar(high, low, close, volume float64) {
 	}
 
 	// Pull signals.
-	hBull := e.bullishFallback()
-	if ok, err := e.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := e.bearishFallback()
-	if ok, err := e.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
-	}
+	hBull := e.signal("hma_bull", e.Suite.GetHMA().IsBullishCrossover, e.bullishFallback)
+	hBear := e.signal("hma_bear", e.Suite.GetHMA().IsBearishCrossover, e.bearishFallback)
 	atsoRaw, _ := e.Suite.GetATSO().Calculate()
 
 	// Volatility‑burst filter.
@@ -105,15 +95,18 @@ func (e *EventDriven) ProcessBar(high, low, close, volume float64) {
 
 	var side types.Side
 	var cond bool
+	intent, strength := IntentLong, e.conviction("hma_bull")
 	if atsoRaw > 0 {
 		cond = hBull
 		side = types.Buy
 	} else {
 		cond = hBear
 		side = types.Sell
+		intent, strength = IntentShort, e.conviction("hma_bear")
 	}
 
 	if cond {
+		e.setIntent(intent, strength, close)
 		e.barSinceEntry = 0
 		e.openPosition(side, close)
 		e.armed = false
@@ -122,6 +115,9 @@ func (e *EventDriven) ProcessBar(high, low, close, volume float64) {
 
 // openPosition creates a market order sized by risk.
 func (e *EventDriven) openPosition(side types.Side, price float64) {
+	if !e.canOpen(side) {
+		return
+	}
 	qty := e.calcQty(price)
 	if qty <= 0 {
 		return
@@ -148,7 +144,7 @@ func (e *EventDriven) manageOpenPosition(currentPrice float64) {
 	if stopDist <= 0 {
 		stopDist = 0.0001
 	}
-	qty, avg := e.Exec.Position(e.Symbol)
+	qty, avg := e.position()
 	if qty == 0 {
 		return
 	}
@@ -179,7 +175,7 @@ func (e *EventDriven) manageOpenPosition(currentPrice float64) {
 		}
 	}
 	// Trailing‑stop.
-	if e.Cfg.TrailingPct > 0 {
+	if e.trailingEnabled() {
 		e.applyTrailingStop(currentPrice)
 	}
 }
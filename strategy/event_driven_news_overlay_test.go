@@ -108,19 +108,7 @@ func TestEventDriven_TrailingStop(t *testing.T) {
 	}
 	entry := exec.Orders()[0].Price
 
-	// ---- price crosses trailing level ----
-	trail := entry * 1.02
-	ev.ProcessBar(trail+0.5, trail-0.5, trail+0.1, 1300)
-
-	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected trailing‑stop close order, got %d (orders: %+v)", len(exec.Orders()), exec.Orders())
-	}
-	if exec.Orders()[1].Side != types.Sell {
-		t.Fatalf("expected SELL to close trailing stop, got %s", exec.Orders()[1].Side)
-	}
-	if exec.Orders()[1].Price < trail {
-		t.Fatalf("trailing‑stop price %f below expected %f", exec.Orders()[1].Price, trail)
-	}
+	checkTrailingExit(t, ev, exec, entry)
 }
 
 func TestEventDriven_TakeProfit(t *testing.T) {
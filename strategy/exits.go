@@ -0,0 +1,293 @@
+package strategy
+
+import (
+	"math"
+	"time"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+)
+
+// stopLevel returns the hard‑stop price for a position of signed qty with
+// average entry avg, or false when no stop applies.  An ATR stop keeps the
+// distance fixed when the position was opened, so the stop matches the
+// risk R the position was sized and scaled with even when volatility
+// changes later.
+func (b *BaseStrategy) stopLevel(qty, avg float64) (float64, bool) {
+	if qty == 0 || avg <= 0 {
+		return 0, false
+	}
+	dist := b.stopDistance(avg)
+	if b.Cfg.StopType == config.StopATR {
+		if st, ok := b.held[b.Symbol]; ok && st.side == math.Copysign(1, qty) && st.stopDist > 0 {
+			dist = st.stopDist
+		}
+	}
+	if dist <= 0 {
+		return 0, false
+	}
+	if qty > 0 {
+		return avg - dist, true
+	}
+	return avg + dist, true
+}
+
+// stopDistance returns the distance of the configured hard stop from avg
+// at the current volatility, or 0 when no stop applies.
+func (b *BaseStrategy) stopDistance(avg float64) float64 {
+	switch b.Cfg.StopType {
+	case config.StopNone:
+		return 0
+	case config.StopATR:
+		mult := b.Cfg.ATRStopMultiple
+		if mult <= 0 {
+			mult = risk.DefaultATRStopMultiple
+		}
+		return b.atrEstimate(avg) * mult
+	case config.StopAbs:
+		return b.Cfg.StopLossAbs
+	default: // "" or StopPct
+		return avg * b.Cfg.StopLossPct
+	}
+}
+
+// manageExits is the shared per‑bar exit manager.  Every strategy calls it
+// right after recording the bar; it advances the position bookkeeping
+// (bars held, best price), applies the hard stop and the time‑based exits
+// and finally the scale‑out / scale‑in rules.  It reports whether the
+// position was closed, in which case the strategy skips its own logic for
+// the bar.  Signal‑only strategies skip the scaling rules, which size
+// rather than decide.
+func (b *BaseStrategy) manageExits(high, low, close float64) bool {
+	qty, avg := b.position()
+	if qty == 0 || avg <= 0 {
+		b.resetPosition()
+		delete(b.partials, b.Symbol) // closed outside closeFraction
+		return false
+	}
+	st := b.positionState(qty, avg)
+	st.bars++
+	st.track(high, low)
+	if b.enforceStop(qty, avg, high, low, close) {
+		return true
+	}
+	if b.enforceTimeExits(st, avg, close) {
+		return true
+	}
+	if !b.signalOnly {
+		b.scalePosition(st, qty, avg, close)
+	}
+	return false
+}
+
+// enforceStop closes the position when the bar's range reached the hard
+// stop and reports whether it did.  Longs are checked against the bar low,
+// shorts against the bar high.  The exit is filled at the stop level, or at
+// the close when the whole bar gapped through it.
+func (b *BaseStrategy) enforceStop(qty, avg, high, low, close float64) bool {
+	level, ok := b.stopLevel(qty, avg)
+	if !ok {
+		return false
+	}
+	fill := level
+	switch {
+	case qty > 0 && low <= level:
+		if high < level {
+			fill = close
+		}
+	case qty < 0 && high >= level:
+		if low > level {
+			fill = close
+		}
+	default:
+		return false
+	}
+	b.Log.Info("hard_stop_hit",
+		logger.String("symbol", b.Symbol),
+		logger.Float64("qty", qty),
+		logger.Float64("entry", avg),
+		logger.Float64("stop", level),
+		logger.Float64("fill", fill),
+	)
+	b.closePosition(fill, "hard_stop")
+	return true
+}
+
+// positionState is the exit bookkeeping of one open position.
+type positionState struct {
+	side   float64   // +1 long, ‑1 short
+	entry  float64   // average entry when the position was opened
+	best   float64   // most favourable price since entry
+	bars   int       // bars completed since entry
+	opened time.Time // strategy clock at entry
+
+	risk        float64 // R: per‑unit distance to the hard stop at entry
+	stopDist    float64 // ATR stop distance fixed at entry (0 for other stops)
+	initQty     float64 // absolute size of the initial entry
+	scaledOut   bool    // partial take‑profit already taken
+	pyramids    int     // adds into a winner so far
+	averages    int     // adds into a loser so far
+	lastAdd     float64 // price of the latest pyramid add (entry initially)
+	lastAverage float64 // price of the latest averaging add (entry initially)
+}
+
+// track records the best price reached: the bar high for longs, the bar
+// low for shorts.
+func (st *positionState) track(high, low float64) {
+	if st.side > 0 && high > st.best {
+		st.best = high
+	}
+	if st.side < 0 && low > 0 && low < st.best {
+		st.best = low
+	}
+}
+
+// positionState returns the bookkeeping of the open position, starting a
+// fresh record when none exists or the position has flipped side.
+func (b *BaseStrategy) positionState(qty, avg float64) *positionState {
+	if b.held == nil {
+		b.held = make(map[string]*positionState)
+	}
+	side := math.Copysign(1, qty)
+	st, ok := b.held[b.Symbol]
+	if !ok || st.side != side {
+		st = &positionState{
+			side:        side,
+			entry:       avg,
+			best:        avg,
+			opened:      b.now(),
+			risk:        b.riskPerUnit(qty, avg),
+			initQty:     math.Abs(qty),
+			lastAdd:     avg,
+			lastAverage: avg,
+		}
+		if b.Cfg.StopType == config.StopATR {
+			st.stopDist = b.stopDistance(avg)
+		}
+		delete(b.partials, b.Symbol) // a new position starts a new trade
+		b.held[b.Symbol] = st
+	}
+	return st
+}
+
+// notePosition refreshes the bookkeeping after an order has been filled.
+func (b *BaseStrategy) notePosition() {
+	qty, avg := b.position()
+	if qty == 0 || avg <= 0 {
+		b.resetPosition()
+		return
+	}
+	b.positionState(qty, avg)
+}
+
+// resetPosition forgets the bookkeeping of the strategy's symbol.
+func (b *BaseStrategy) resetPosition() {
+	delete(b.held, b.Symbol)
+}
+
+// now reads the strategy clock.
+func (b *BaseStrategy) now() time.Time {
+	if b.Now == nil {
+		return time.Now()
+	}
+	return b.Now()
+}
+
+// sessionClosed reports whether t is at or after Cfg.SessionEnd on its day.
+func (b *BaseStrategy) sessionClosed(t time.Time) bool {
+	if b.Cfg.SessionEnd == "" {
+		return false
+	}
+	offset, err := b.Cfg.SessionEndOffset()
+	if err != nil {
+		return false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return t.Sub(midnight) >= offset
+}
+
+// enforceTimeExits applies the session and holding‑period rules to the
+// open position and reports whether it was closed.  The exit reason is
+// used as the order comment and metrics context.
+func (b *BaseStrategy) enforceTimeExits(st *positionState, avg, close float64) bool {
+	var reason string
+	switch {
+	case b.sessionClosed(b.now()):
+		reason = "exit_session_end"
+	case b.Cfg.MaxHoldingBars > 0 && st.bars >= b.Cfg.MaxHoldingBars:
+		reason = "exit_max_bars"
+	case b.Cfg.MaxHoldingDuration > 0 && b.now().Sub(st.opened) >= b.Cfg.MaxHoldingDuration:
+		reason = "exit_max_duration"
+	case b.Cfg.ExitUnprofitableBars > 0 && st.bars >= b.Cfg.ExitUnprofitableBars &&
+		st.side*(close-avg) <= 0:
+		reason = "exit_unprofitable"
+	default:
+		return false
+	}
+	b.Log.Info("time_exit",
+		logger.String("symbol", b.Symbol),
+		logger.String("reason", reason),
+		logger.Int("bars_held", st.bars),
+		logger.Float64("price", close),
+	)
+	b.closePosition(close, reason)
+	return true
+}
+
+// trailingEnabled reports whether any trailing‑stop rule is configured.
+func (b *BaseStrategy) trailingEnabled() bool {
+	return b.Cfg.TrailingPct > 0 || b.Cfg.TrailingATRMult > 0
+}
+
+// trailingStopLevel returns the price at which the trailing stop of st
+// fires, or false while the stop is not yet armed.
+func (b *BaseStrategy) trailingStopLevel(st *positionState) (float64, bool) {
+	if act := b.Cfg.TrailingActivationPct; act > 0 {
+		if st.side*(st.best-st.entry) < st.entry*act {
+			return 0, false
+		}
+	}
+	var dist float64
+	if b.Cfg.TrailingPct > 0 {
+		dist = st.best * b.Cfg.TrailingPct
+	}
+	if b.Cfg.TrailingATRMult > 0 {
+		atrDist := b.atrEstimate(st.best) * b.Cfg.TrailingATRMult
+		if dist == 0 || atrDist < dist {
+			dist = atrDist
+		}
+	}
+	if dist <= 0 {
+		return 0, false
+	}
+	return st.best - st.side*dist, true
+}
+
+// applyTrailingStop closes the position once currentPrice has retraced from
+// the best price since entry by the configured trailing distance.
+func (b *BaseStrategy) applyTrailingStop(currentPrice float64) {
+	if !b.trailingEnabled() {
+		return
+	}
+	qty, avg := b.position()
+	if qty == 0 {
+		return
+	}
+	st := b.positionState(qty, avg)
+	st.track(currentPrice, currentPrice)
+	level, ok := b.trailingStopLevel(st)
+	if !ok {
+		return
+	}
+	if (qty > 0 && currentPrice <= level) || (qty < 0 && currentPrice >= level) {
+		b.Log.Info("trailing_stop_hit",
+			logger.String("symbol", b.Symbol),
+			logger.Float64("qty", qty),
+			logger.Float64("best", st.best),
+			logger.Float64("stop", level),
+			logger.Float64("price", currentPrice),
+		)
+		b.closePosition(currentPrice, "trailing_stop")
+	}
+}
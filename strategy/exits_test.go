@@ -0,0 +1,290 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+// buildStopped returns a MeanReversion strategy with the hard stop enabled
+// and a position of qty (signed) opened at 100 on the mock executor.
+func buildStopped(t *testing.T, cfg config.StrategyConfig, qty float64) (*MeanReversion, *testutils.MockExecutor) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	mr, err := NewMeanReversion("TEST", cfg, exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+	side := types.Buy
+	if qty < 0 {
+		side = types.Sell
+	}
+	if err := exec.Submit(types.Order{Symbol: "TEST", Side: side, Qty: math.Abs(qty), Price: 100}); err != nil {
+		t.Fatalf("seed order failed: %v", err)
+	}
+	return mr, exec
+}
+
+func lastOrder(exec *testutils.MockExecutor) types.Order {
+	orders := exec.Orders()
+	return orders[len(orders)-1]
+}
+
+func TestHardStop_LongStoppedOnBarLow(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopPct
+	mr, exec := buildStopped(t, cfg, 10)
+
+	// The close (99) is above the 98.5 stop but the low pierced it.
+	mr.ProcessBar(100, 98, 99, 1000)
+
+	if qty, _ := exec.Position("TEST"); qty != 0 {
+		t.Fatalf("expected long to be stopped out, still hold %f", qty)
+	}
+	o := lastOrder(exec)
+	if o.Side != types.Sell || o.Comment != "hard_stop" {
+		t.Fatalf("expected hard_stop SELL, got %+v", o)
+	}
+	if math.Abs(o.Price-98.5) > 1e-9 {
+		t.Fatalf("expected fill at stop level 98.5, got %f", o.Price)
+	}
+}
+
+func TestHardStop_ShortStoppedOnBarHigh(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopPct
+	mr, exec := buildStopped(t, cfg, -10)
+
+	mr.ProcessBar(102, 100.5, 101, 1000)
+
+	if qty, _ := exec.Position("TEST"); qty != 0 {
+		t.Fatalf("expected short to be stopped out, still hold %f", qty)
+	}
+	o := lastOrder(exec)
+	if o.Side != types.Buy || math.Abs(o.Price-101.5) > 1e-9 {
+		t.Fatalf("expected BUY at 101.5, got %+v", o)
+	}
+}
+
+func TestHardStop_GapFillsAtClose(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopPct
+	mr, exec := buildStopped(t, cfg, 10)
+
+	// The whole bar trades below the stop – the exit cannot be at 98.5.
+	mr.ProcessBar(96, 94, 95, 1000)
+
+	if o := lastOrder(exec); o.Price != 95 {
+		t.Fatalf("expected gap fill at close 95, got %f", o.Price)
+	}
+}
+
+func TestHardStop_AbsAndNone(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopAbs
+	cfg.StopLossAbs = 3
+	mr, exec := buildStopped(t, cfg, 10)
+
+	// 98 is inside the 3‑point stop – nothing happens.
+	mr.ProcessBar(100, 98, 99, 1000)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("abs stop fired too early, position %f", qty)
+	}
+	mr.ProcessBar(99, 96.5, 97, 1000)
+	if o := lastOrder(exec); o.Comment != "hard_stop" || o.Price != 97 {
+		t.Fatalf("expected abs stop at 97, got %+v", o)
+	}
+
+	cfg.StopType = config.StopNone
+	mr, exec = buildStopped(t, cfg, 10)
+	mr.ProcessBar(100, 50, 60, 1000)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("StopNone must not stop out, position %f", qty)
+	}
+}
+
+func TestHardStop_ATRDistanceFixedAtEntry(t *testing.T) {
+	cfg := buildConfig()
+	cfg.StopType = config.StopATR
+	cfg.ATRStopMultiple = 2
+	mr, exec := buildStopped(t, cfg, 10)
+
+	for i := 0; i < 20; i++ {
+		p := 100 + 0.1*float64(i%2)
+		mr.Suite.Add(p+0.2, p-0.2, p, 1000)
+		mr.recordBar(p+0.2, p-0.2, p, 1000)
+	}
+	st := mr.positionState(10, 100)
+	entryDist := st.stopDist
+	if entryDist <= 0 || math.Abs(st.risk-entryDist) > 1e-9 {
+		t.Fatalf("R %f must equal the ATR stop distance %f at entry", st.risk, entryDist)
+	}
+
+	// Volatility rises: the live ATR distance widens, the stop must not.
+	for i := 0; i < 10; i++ {
+		p := 100 + 3*float64(i%2)
+		mr.Suite.Add(p+2, p-2, p, 1000)
+		mr.recordBar(p+2, p-2, p, 1000)
+	}
+	if live := mr.stopDistance(100); live <= entryDist {
+		t.Fatalf("scenario needs a wider live ATR distance, got %f vs %f", live, entryDist)
+	}
+	if level, ok := mr.stopLevel(10, 100); !ok || math.Abs(level-(100-entryDist)) > 1e-9 {
+		t.Fatalf("stop %f moved from the entry level %f", level, 100-entryDist)
+	}
+	mr.ProcessBar(100, 99.9-entryDist, 100, 1000)
+	if o := lastOrder(exec); o.Comment != "hard_stop" {
+		t.Fatalf("expected the entry ATR stop to fire, got %+v", o)
+	}
+}
+
+// trailBar runs the shared exit path the strategies use on every bar.
+func trailBar(mr *MeanReversion, high, low, close float64) {
+	if !mr.manageExits(high, low, close) {
+		mr.applyTrailingStop(close)
+	}
+}
+
+func TestTrailingStop_TracksHighWaterMark(t *testing.T) {
+	cfg := buildConfig()
+	cfg.TrailingPct = 0.05
+	mr, exec := buildStopped(t, cfg, 10)
+
+	trailBar(mr, 110, 104, 108) // best = 110, level = 104.5
+	trailBar(mr, 109, 105, 105) // retrace of 4.5 % – still inside the trail
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("trailing stop fired before a 5 %% retrace, position %f", qty)
+	}
+	trailBar(mr, 106, 103, 104)
+	o := lastOrder(exec)
+	if o.Comment != "trailing_stop" || o.Side != types.Sell {
+		t.Fatalf("expected trailing_stop SELL, got %+v", o)
+	}
+	if _, ok := mr.held["TEST"]; ok {
+		t.Fatal("trailing state must be cleared once the position is closed")
+	}
+}
+
+func TestTrailingStop_ActivationThreshold(t *testing.T) {
+	cfg := buildConfig()
+	cfg.TrailingPct = 0.01
+	cfg.TrailingActivationPct = 0.05
+	mr, exec := buildStopped(t, cfg, 10)
+
+	// +3 % then a 2 % pullback: the trail is not armed yet.
+	trailBar(mr, 103, 101, 103)
+	trailBar(mr, 102, 100.5, 101)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("trailing stop fired before activation, position %f", qty)
+	}
+	// +6 % arms it; a 1 %+ pullback from the high then exits.
+	trailBar(mr, 106, 104, 106)
+	trailBar(mr, 105.5, 104, 104.5)
+	if o := lastOrder(exec); o.Comment != "trailing_stop" {
+		t.Fatalf("expected trailing exit after activation, got %+v", o)
+	}
+}
+
+func TestTrailingStop_ShortChandelier(t *testing.T) {
+	cfg := buildConfig()
+	cfg.TrailingATRMult = 1
+	mr, exec := buildStopped(t, cfg, -10)
+
+	trailBar(mr, 99, 90, 91) // best = 90
+	atr := mr.atrEstimate(90)
+	level := 90 + atr
+	if level >= 99 {
+		t.Fatalf("ATR proxy too large for the scenario: %f", atr)
+	}
+	trailBar(mr, level-0.01, 90.5, level-0.01)
+	if qty, _ := exec.Position("TEST"); qty != -10 {
+		t.Fatalf("chandelier stop fired early, position %f", qty)
+	}
+	trailBar(mr, level+1, 91, level+0.5)
+	if o := lastOrder(exec); o.Comment != "trailing_stop" || o.Side != types.Buy {
+		t.Fatalf("expected trailing_stop BUY, got %+v", o)
+	}
+}
+
+func TestTimeExit_MaxHoldingBars(t *testing.T) {
+	cfg := buildConfig()
+	cfg.MaxHoldingBars = 3
+	mr, exec := buildStopped(t, cfg, 10)
+
+	for i := 0; i < 2; i++ {
+		mr.ProcessBar(102, 100, 101, 1000)
+	}
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("position closed before MaxHoldingBars, qty %f", qty)
+	}
+	mr.ProcessBar(102, 100, 101, 1000)
+	if o := lastOrder(exec); o.Comment != "exit_max_bars" {
+		t.Fatalf("expected exit_max_bars, got %+v", o)
+	}
+}
+
+func TestTimeExit_Unprofitable(t *testing.T) {
+	cfg := buildConfig()
+	cfg.ExitUnprofitableBars = 2
+	mr, exec := buildStopped(t, cfg, 10)
+
+	mr.ProcessBar(100.5, 99.5, 99.8, 1000)
+	mr.ProcessBar(100.5, 99.5, 99.9, 1000)
+	if o := lastOrder(exec); o.Comment != "exit_unprofitable" {
+		t.Fatalf("expected exit_unprofitable, got %+v", o)
+	}
+
+	// A position in profit after the same number of bars is kept.
+	mr, exec = buildStopped(t, cfg, 10)
+	mr.ProcessBar(101, 100, 100.8, 1000)
+	mr.ProcessBar(101.5, 100.5, 101.2, 1000)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("profitable position must be kept, qty %f", qty)
+	}
+}
+
+func TestTimeExit_DurationAndSessionEnd(t *testing.T) {
+	clock := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	cfg := buildConfig()
+	cfg.MaxHoldingDuration = 2 * time.Hour
+	mr, exec := buildStopped(t, cfg, 10)
+	mr.Now = func() time.Time { return clock }
+
+	mr.ProcessBar(101, 100, 100.5, 1000) // bookkeeping starts at 10:00
+	clock = clock.Add(90 * time.Minute)
+	mr.ProcessBar(101, 100, 100.5, 1000)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("closed before MaxHoldingDuration, qty %f", qty)
+	}
+	clock = clock.Add(30 * time.Minute)
+	mr.ProcessBar(101, 100, 100.5, 1000)
+	if o := lastOrder(exec); o.Comment != "exit_max_duration" {
+		t.Fatalf("expected exit_max_duration, got %+v", o)
+	}
+
+	cfg = buildConfig()
+	cfg.SessionEnd = "15:55"
+	clock = time.Date(2024, 3, 1, 15, 50, 0, 0, time.UTC)
+	mr, exec = buildStopped(t, cfg, 10)
+	mr.Now = func() time.Time { return clock }
+	mr.ProcessBar(101, 100, 100.5, 1000)
+	if qty, _ := exec.Position("TEST"); qty != 10 {
+		t.Fatalf("closed before the session end, qty %f", qty)
+	}
+	clock = clock.Add(5 * time.Minute)
+	mr.ProcessBar(101, 100, 100.5, 1000)
+	if o := lastOrder(exec); o.Comment != "exit_session_end" {
+		t.Fatalf("expected exit_session_end, got %+v", o)
+	}
+	if mr.canOpen(types.Buy) {
+		t.Fatal("entries must be blocked after the session end")
+	}
+	clock = clock.Add(12 * time.Hour)
+	if !mr.canOpen(types.Buy) {
+		t.Fatal("entries must resume on the next day")
+	}
+}
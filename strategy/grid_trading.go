@@ -0,0 +1,353 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// GridSpacing selects how GridTrading spaces its levels.
+type GridSpacing string
+
+const (
+	// GridArithmetic spaces levels Step price units apart.
+	GridArithmetic GridSpacing = "arithmetic"
+	// GridGeometric spaces levels by a factor of 1+Step (0.01 = 1 %).
+	GridGeometric GridSpacing = "geometric"
+)
+
+// GridConfig tunes GridTrading beyond the shared StrategyConfig.
+type GridConfig struct {
+	Spacing GridSpacing
+	Step    float64 // level distance: price units or fraction, see Spacing
+	Levels  int     // levels on each side of the reference price
+
+	// Reference is the centre of the ladder; 0 uses the first close.
+	Reference float64
+
+	// OrderQty is the quantity of every level; 0 sizes it once with
+	// risk.CalcQty at the reference price.
+	OrderQty float64
+
+	// MaxInventory caps |position| in units, counting resting orders as
+	// filled; 0 means Levels × the level quantity.
+	MaxInventory float64
+}
+
+// DefaultGridConfig places five geometric levels 1 % apart on each side of
+// the first close.
+func DefaultGridConfig() GridConfig {
+	return GridConfig{
+		Spacing: GridGeometric,
+		Step:    0.01,
+		Levels:  5,
+	}
+}
+
+// Validate checks the grid settings.
+func (gc GridConfig) Validate() error {
+	switch gc.Spacing {
+	case GridArithmetic:
+		if gc.Step <= 0 {
+			return fmt.Errorf("Step (%f) must be positive", gc.Step)
+		}
+	case GridGeometric:
+		if gc.Step <= 0 || gc.Step >= 1 {
+			return fmt.Errorf("geometric Step (%f) must be between 0 and 1", gc.Step)
+		}
+	default:
+		return fmt.Errorf("unknown grid spacing %q", gc.Spacing)
+	}
+	if gc.Levels < 1 || gc.Levels > 100 {
+		return fmt.Errorf("Levels (%d) must be between 1 and 100", gc.Levels)
+	}
+	if gc.Spacing == GridArithmetic && gc.Reference > 0 && gc.Reference-float64(gc.Levels)*gc.Step <= 0 {
+		return fmt.Errorf("lowest level of the ladder below %f is not positive", gc.Reference)
+	}
+	if gc.Reference < 0 || gc.OrderQty < 0 || gc.MaxInventory < 0 {
+		return fmt.Errorf("Reference, OrderQty and MaxInventory must not be negative")
+	}
+	return nil
+}
+
+// gridOrder is the order armed at one level.  An empty ID means the order
+// could not be placed yet (inventory bound, position mode or cash) and is
+// retried every bar.
+type gridOrder struct {
+	Side types.Side `json:"side"`
+	ID   string     `json:"id,omitempty"`
+}
+
+// GridTrading keeps a ladder of buy limits below and sell limits above a
+// reference price.  Every fill re‑arms the opposite order one level away
+// (a buy filled at level k is followed by a sell at k+1 and vice versa), so
+// each round trip between neighbouring levels captures one step.  The
+// position, counting resting orders as filled, is bounded by MaxInventory
+// through risk.InventoryLimit and by the PositionMode.
+//
+// Fills are detected from the executor's open orders: an order that is no
+// longer resting was filled.  Backtests call MatchBar on the paper executor
+// before ProcessBar.
+type GridTrading struct {
+	symbol string
+	cfg    config.StrategyConfig
+	grid   GridConfig
+	exec   executor.LimitExecutor
+	log    logger.Logger
+	mu     sync.Mutex
+
+	prices []float64 // level prices, index i is level i−Levels
+	qty    float64   // quantity per level
+	maxInv float64
+	orders map[int]*gridOrder // by level; a missing level is the gap
+}
+
+// NewGridTrading trades a ladder around the first close with
+// DefaultGridConfig.
+func NewGridTrading(symbol string, cfg config.StrategyConfig,
+	exec executor.LimitExecutor, log logger.Logger) (*GridTrading, error) {
+
+	if symbol == "" {
+		return nil, logOutputError(log, "grid needs a symbol")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &GridTrading{
+		symbol: symbol,
+		cfg:    cfg,
+		grid:   DefaultGridConfig(),
+		exec:   exec,
+		log:    log,
+		orders: make(map[int]*gridOrder),
+	}, nil
+}
+
+// SetGridConfig replaces the grid settings after validating them.  A
+// ladder that is already built is cancelled and rebuilt on the next bar.
+func (g *GridTrading) SetGridConfig(gc GridConfig) error {
+	if err := gc.Validate(); err != nil {
+		return logOutputError(g.log, err.Error())
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cancelAll()
+	g.grid = gc
+	g.prices = nil
+	return nil
+}
+
+// LevelPrices returns the ladder from the lowest to the highest level,
+// reference included; nil before the first bar.
+func (g *GridTrading) LevelPrices() []float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]float64(nil), g.prices...)
+}
+
+// ProcessBar re‑arms the levels filled since the previous bar and places
+// the orders that are still waiting.  The first bar builds the ladder.
+func (g *GridTrading) ProcessBar(high, low, close, volume float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.prices == nil {
+		g.build(close)
+	} else {
+		g.syncFills()
+	}
+	g.placePending(close)
+}
+
+// Cancel removes every resting grid order, e.g. before shutting down.
+// The ladder is rebuilt around the next close.
+func (g *GridTrading) Cancel() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cancelAll()
+	g.prices = nil
+}
+
+// build lays out the ladder around the reference and arms buys below and
+// sells above it.
+func (g *GridTrading) build(close float64) {
+	ref := g.grid.Reference
+	if ref <= 0 {
+		ref = close
+	}
+	n := g.grid.Levels
+	g.prices = make([]float64, 2*n+1)
+	for i := range g.prices {
+		k := float64(i - n)
+		if g.grid.Spacing == GridArithmetic {
+			g.prices[i] = ref + k*g.grid.Step
+		} else {
+			g.prices[i] = ref * math.Pow(1+g.grid.Step, k)
+		}
+	}
+	g.qty = g.grid.OrderQty
+	if g.qty == 0 {
+		g.qty = risk.CalcQty(g.exec.Equity(), g.cfg.MaxRiskPerTrade, g.cfg.StopLossPct, ref, g.cfg)
+	}
+	g.maxInv = g.grid.MaxInventory
+	if g.maxInv == 0 {
+		g.maxInv = float64(n) * g.qty
+	}
+	g.orders = make(map[int]*gridOrder)
+	for k := -n; k <= n; k++ {
+		switch {
+		case k < 0 && g.prices[k+n] > 0:
+			g.orders[k] = &gridOrder{Side: types.Buy}
+		case k > 0:
+			g.orders[k] = &gridOrder{Side: types.Sell}
+		}
+	}
+	g.log.Info("grid_built",
+		logger.String("symbol", g.symbol),
+		logger.Float64("reference", ref),
+		logger.Float64("low", g.prices[0]),
+		logger.Float64("high", g.prices[2*n]),
+		logger.Float64("level_qty", g.qty),
+		logger.Float64("max_inventory", g.maxInv),
+	)
+}
+
+// syncFills removes the levels whose orders were filled and arms the
+// opposite order one level away.  All fills are taken off first so that a
+// bar crossing several levels can re‑arm into levels it just emptied.
+func (g *GridTrading) syncFills() {
+	open := make(map[string]bool)
+	for _, o := range g.exec.OpenOrders(g.symbol) {
+		open[o.ID] = true
+	}
+	var filled []int
+	for k, o := range g.orders {
+		if o.ID != "" && !open[o.ID] {
+			filled = append(filled, k)
+		}
+	}
+	sort.Ints(filled)
+	sides := make([]types.Side, len(filled))
+	for i, k := range filled {
+		sides[i] = g.orders[k].Side
+		delete(g.orders, k)
+		g.log.Info("grid_fill",
+			logger.String("symbol", g.symbol),
+			logger.String("side", string(sides[i])),
+			logger.Int("level", k),
+			logger.Float64("price", g.price(k)),
+		)
+	}
+	for i, k := range filled {
+		next, side := k+1, types.Sell
+		if sides[i] == types.Sell {
+			next, side = k-1, types.Buy
+		}
+		if g.inLadder(next) && g.orders[next] == nil {
+			g.orders[next] = &gridOrder{Side: side}
+		}
+	}
+}
+
+// placePending places the armed orders nearest to close first, each only
+// if the inventory bound leaves room for a full level.
+func (g *GridTrading) placePending(close float64) {
+	var pending []int
+	for k, o := range g.orders {
+		if o.ID == "" {
+			pending = append(pending, k)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		di, dj := math.Abs(g.price(pending[i])-close), math.Abs(g.price(pending[j])-close)
+		if di != dj {
+			return di < dj
+		}
+		return pending[i] < pending[j]
+	})
+
+	pos, _ := g.exec.Position(g.symbol)
+	var restingBuys, restingSells float64
+	for _, o := range g.orders {
+		if o.ID == "" {
+			continue
+		}
+		if o.Side == types.Buy {
+			restingBuys += g.qty
+		} else {
+			restingSells += g.qty
+		}
+	}
+	minPos, maxPos := 0.0, 0.0
+	if g.cfg.PositionMode.AllowsLong() {
+		maxPos = g.maxInv
+	}
+	if g.cfg.PositionMode.AllowsShort() {
+		minPos = -g.maxInv
+	}
+
+	for _, k := range pending {
+		o := g.orders[k]
+		var room float64
+		if o.Side == types.Buy {
+			room = risk.InventoryLimit(pos+restingBuys, g.qty, minPos, maxPos, g.cfg)
+		} else {
+			room = risk.InventoryLimit(pos-restingSells, -g.qty, minPos, maxPos, g.cfg)
+		}
+		if room < g.qty {
+			continue
+		}
+		id, err := g.exec.PlaceLimit(types.Order{
+			Symbol:  g.symbol,
+			Side:    o.Side,
+			Qty:     g.qty,
+			Price:   g.price(k),
+			Comment: "grid",
+		})
+		if err != nil {
+			g.log.Warn("grid_place_error",
+				logger.String("symbol", g.symbol),
+				logger.Int("level", k),
+				logger.Err(err),
+			)
+			continue
+		}
+		o.ID = id
+		if o.Side == types.Buy {
+			restingBuys += g.qty
+		} else {
+			restingSells += g.qty
+		}
+	}
+}
+
+// cancelAll cancels the resting orders and disarms every level.
+func (g *GridTrading) cancelAll() {
+	for k, o := range g.orders {
+		if o.ID != "" {
+			if err := g.exec.Cancel(o.ID); err != nil {
+				g.log.Warn("grid_cancel_error",
+					logger.String("symbol", g.symbol),
+					logger.Int("level", k),
+					logger.Err(err),
+				)
+			}
+		}
+	}
+	g.orders = make(map[int]*gridOrder)
+}
+
+func (g *GridTrading) price(k int) float64 { return g.prices[k+g.grid.Levels] }
+
+func (g *GridTrading) inLadder(k int) bool {
+	return k >= -g.grid.Levels && k <= g.grid.Levels && g.price(k) > 0
+}
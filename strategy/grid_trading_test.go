@@ -0,0 +1,221 @@
+package strategy
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+func buildGrid(t *testing.T, cfg config.StrategyConfig, gc GridConfig) (*GridTrading, *testutils.MockExecutor, *testutils.MockLogger) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(100_000)
+	log := testutils.NewMockLogger()
+	g, err := NewGridTrading("GRID", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewGridTrading failed: %v", err)
+	}
+	if err := g.SetGridConfig(gc); err != nil {
+		t.Fatalf("SetGridConfig failed: %v", err)
+	}
+	return g, exec, log
+}
+
+// runGrid matches the resting orders against every bar before handing it
+// to the strategy, as a backtest loop would, and checks the inventory
+// bound after each bar.
+func runGrid(t *testing.T, g *GridTrading, exec *testutils.MockExecutor, closes []float64, maxInv float64) {
+	t.Helper()
+	for i, c := range closes {
+		exec.MatchBar("GRID", c+0.3, c-0.3)
+		g.ProcessBar(c+0.3, c-0.3, c, 1000)
+		if pos, _ := exec.Position("GRID"); math.Abs(pos) > maxInv+1e-9 {
+			t.Fatalf("bar %d: inventory %v exceeds %v", i, pos, maxInv)
+		}
+	}
+}
+
+func arithmeticGrid() GridConfig {
+	return GridConfig{Spacing: GridArithmetic, Step: 1, Levels: 5, OrderQty: 10, MaxInventory: 30}
+}
+
+func TestGridLevels(t *testing.T) {
+	g, exec, _ := buildGrid(t, buildConfig(), GridConfig{
+		Spacing: GridArithmetic, Step: 2, Levels: 3, Reference: 100, OrderQty: 1,
+	})
+	g.ProcessBar(101, 99, 100, 1000)
+	if got, want := g.LevelPrices(), []float64{94, 96, 98, 100, 102, 104, 106}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("arithmetic levels %v, want %v", got, want)
+	}
+	open := exec.OpenOrders("GRID")
+	if len(open) != 6 {
+		t.Fatalf("expected 3 buys and 3 sells, got %+v", open)
+	}
+	for _, o := range open {
+		if (o.Side == types.Buy) != (o.Price < 100) {
+			t.Fatalf("buys must rest below and sells above the reference: %+v", o)
+		}
+	}
+
+	g, _, _ = buildGrid(t, buildConfig(), GridConfig{Spacing: GridGeometric, Step: 0.01, Levels: 2, OrderQty: 1})
+	g.ProcessBar(201, 199, 200, 1000)
+	levels := g.LevelPrices()
+	for i := 1; i < len(levels); i++ {
+		if r := levels[i] / levels[i-1]; math.Abs(r-1.01) > 1e-12 {
+			t.Fatalf("geometric levels %v: ratio %v, want 1.01", levels, r)
+		}
+	}
+	if levels[2] != 200 {
+		t.Fatalf("first close must be the reference, got %v", levels)
+	}
+}
+
+func TestGridRangingMarketHarvestsRoundTrips(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	g, exec, _ := buildGrid(t, cfg, arithmeticGrid())
+
+	closes := make([]float64, 300)
+	for i := range closes {
+		closes[i] = 100 + 3.5*math.Sin(float64(i)*0.2)
+	}
+	runGrid(t, g, exec, closes, 30)
+
+	var buys, sells int
+	for _, o := range exec.Orders() {
+		if o.Comment != "grid" {
+			t.Fatalf("unexpected order %+v", o)
+		}
+		if o.Side == types.Buy {
+			buys++
+		} else {
+			sells++
+		}
+	}
+	if buys < 10 || sells < 10 {
+		t.Fatalf("expected repeated round trips, got %d buys and %d sells", buys, sells)
+	}
+	pos, _ := exec.Position("GRID")
+	last := closes[len(closes)-1]
+	if pnl := exec.Equity() + pos*last - 100_000; pnl <= 0 {
+		t.Fatalf("a ranging market should be profitable for the grid, pnl %v", pnl)
+	}
+	// Sells are only armed one level above a filled buy, never at the
+	// bottom of the ladder.
+	for _, o := range exec.Orders() {
+		if o.Side == types.Sell && o.Price <= 95 {
+			t.Fatalf("long‑only grid sold at the bottom level: %+v", o)
+		}
+	}
+}
+
+func TestGridDowntrendBoundsInventory(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	g, exec, log := buildGrid(t, cfg, arithmeticGrid())
+
+	closes := make([]float64, 150)
+	for i := range closes {
+		closes[i] = 100 - 0.2*float64(i)
+	}
+	runGrid(t, g, exec, closes, 30)
+
+	if pos, _ := exec.Position("GRID"); pos != 30 {
+		t.Fatalf("a falling market fills the grid up to the bound, position %v", pos)
+	}
+	for _, o := range exec.OpenOrders("GRID") {
+		if o.Side == types.Buy {
+			t.Fatalf("no buy may rest once the bound is reached: %+v", o)
+		}
+	}
+	if !log.HasMessage("grid_fill") {
+		t.Fatal("expected fills to be logged")
+	}
+}
+
+func TestGridUptrendShortBound(t *testing.T) {
+	closes := make([]float64, 150)
+	for i := range closes {
+		closes[i] = 100 + 0.2*float64(i)
+	}
+
+	g, exec, _ := buildGrid(t, buildConfig(), arithmeticGrid())
+	runGrid(t, g, exec, closes, 30)
+	if pos, _ := exec.Position("GRID"); pos != -30 {
+		t.Fatalf("a rising market sells the grid down to the bound, position %v", pos)
+	}
+
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	g, exec, _ = buildGrid(t, cfg, arithmeticGrid())
+	runGrid(t, g, exec, closes, 30)
+	if n := len(exec.Orders()); n != 0 {
+		t.Fatalf("a long‑only grid without inventory has nothing to sell, got %d fills", n)
+	}
+}
+
+func TestGridSnapshotRestore(t *testing.T) {
+	closes := make([]float64, 200)
+	for i := range closes {
+		closes[i] = 100 + 3.5*math.Sin(float64(i)*0.2) - 0.02*float64(i)
+	}
+
+	straight, straightExec, _ := buildGrid(t, buildConfig(), arithmeticGrid())
+	runGrid(t, straight, straightExec, closes, 30)
+
+	first, exec, _ := buildGrid(t, buildConfig(), arithmeticGrid())
+	runGrid(t, first, exec, closes[:90], 30)
+	data, err := first.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewGridTrading("GRID", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewGridTrading failed: %v", err)
+	}
+	if err := resumed.SetGridConfig(arithmeticGrid()); err != nil {
+		t.Fatalf("SetGridConfig failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	runGrid(t, resumed, exec, closes[90:], 30)
+
+	if len(straightExec.Orders()) == 0 || !reflect.DeepEqual(straightExec.Orders(), exec.Orders()) {
+		t.Fatalf("resumed grid diverged:\nwant %+v\ngot  %+v", straightExec.Orders(), exec.Orders())
+	}
+}
+
+func TestGridCancel(t *testing.T) {
+	g, exec, _ := buildGrid(t, buildConfig(), arithmeticGrid())
+	g.ProcessBar(100.3, 99.7, 100, 1000)
+	if len(exec.OpenOrders("GRID")) == 0 {
+		t.Fatal("expected resting orders")
+	}
+	g.Cancel()
+	if open := exec.OpenOrders("GRID"); len(open) != 0 {
+		t.Fatalf("Cancel left orders resting: %+v", open)
+	}
+}
+
+func TestGridConfigValidate(t *testing.T) {
+	if err := DefaultGridConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []GridConfig{
+		{Spacing: "fibonacci", Step: 1, Levels: 3},
+		{Spacing: GridArithmetic, Step: 0, Levels: 3},
+		{Spacing: GridGeometric, Step: 1, Levels: 3},
+		{Spacing: GridGeometric, Step: 0.01, Levels: 0},
+		{Spacing: GridArithmetic, Step: 30, Levels: 4, Reference: 100},
+		{Spacing: GridGeometric, Step: 0.01, Levels: 3, OrderQty: -1},
+	}
+	for i, gc := range bad {
+		if err := gc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, gc)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -20,6 +19,9 @@ const (
 )
 
 // HybridTrendMeanReversion implements the “trend‑then‑mean‑reversion” FSM.
+// The regime of BaseStrategy gates it: trends are only entered outside
+// range and high‑volatility regimes, a range regime ends the trend phase
+// early, and contrarian entries wait until the market stops trending.
 type HybridTrendMeanReversion struct {
 	*BaseStrategy
 	state          hybridState
@@ -31,14 +33,7 @@ type HybridTrendMeanReversion struct {
 func NewHybridTrendMeanReversion(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*HybridTrendMeanReversion, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -53,24 +48,22 @@ func NewHybridTrendMeanReversion(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar drives the finite‑state machine.
 func (h *HybridTrendMeanReversion) ProcessBar(high, low, close, volume float64) {
+	defer h.flushDecision()
 	if err := h.Suite.Add(high, low, close, volume); err != nil {
 		h.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	h.recordPrice(close)
+	h.recordBar(high, low, close, volume)
+	if h.manageExits(high, low, close) {
+		return
+	}
 	if !h.hasHistory(15) {
 		return
 	}
 
 	// Pull signals.
-	hBull := h.bullishFallback()
-	if ok, err := h.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := h.bearishFallback()
-	if ok, err := h.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
-	}
+	hBull := h.signal("hma_bull", h.Suite.GetHMA().IsBullishCrossover, h.bullishFallback)
+	hBear := h.signal("hma_bear", h.Suite.GetHMA().IsBearishCrossover, h.bearishFallback)
 	rsiVal, err := h.Suite.GetRSI().Calculate()
 	if err != nil {
 		rsiVal = 50
@@ -79,11 +72,12 @@ func (h *HybridTrendMeanReversion) ProcessBar(high, low, close, volume float64)
 	if err != nil {
 		mfiVal = 50
 	}
-	posQty, _ := h.Exec.Position(h.Symbol)
+	posQty, _ := h.position()
 	trendDir := h.prices.Trend()
 	deltaRaw := h.lastPriceChange()
 	priceDelta := math.Abs(deltaRaw)
-	const flatTolerance = 0.05
+	flatTolerance := h.flatTolerance(close)
+	regime := h.Regime()
 	rsOverbought := h.Cfg.RSIOverbought
 	rsOversold := h.Cfg.RSIOversold
 	mfiOverbought := h.Cfg.MFIOverbought
@@ -99,15 +93,29 @@ func (h *HybridTrendMeanReversion) ProcessBar(high, low, close, volume float64)
 
 	switch h.state {
 	case stateIdle:
-		if hBull {
+		if (hBull || hBear) && (regime.Regime == RegimeRange || regime.Regime == RegimeHighVol) {
+			h.Log.Info("hybrid_entry_skipped_regime",
+				logger.String("symbol", h.Symbol),
+				logger.String("regime", string(regime.Regime)),
+				logger.Float64("confidence", regime.Confidence),
+			)
+		} else if hBull {
+			h.setIntent(IntentLong, h.conviction("hma_bull"), close)
 			h.enterTrend(types.Buy, close)
 		} else if hBear {
+			h.setIntent(IntentShort, h.conviction("hma_bear"), close)
 			h.enterTrend(types.Sell, close)
 		}
 	case stateTrend:
+		// A range regime ends the trend phase at once.
+		if regime.Regime == RegimeRange {
+			h.exitTrend(close)
+			h.state = stateRevert
+			h.flatBarCounter = 0
+			break
+		}
 		// Reinforce trend or count flat bars based on price momentum.
 		reinforced := false
-		const flatTolerance = 0.05
 		if h.trendSide == types.Buy {
 			reinforced = trendDir > 0 && priceDelta > flatTolerance
 		} else if h.trendSide == types.Sell {
@@ -125,27 +133,46 @@ func (h *HybridTrendMeanReversion) ProcessBar(high, low, close, volume float64)
 			}
 		}
 	case stateRevert:
-		// Look for opposite‑direction oversold/overbought signal.
-		if h.trendSide == types.Buy {
+		// Look for opposite‑direction oversold/overbought signal once the
+		// market has stopped trending.  Both oscillators must be ready to
+		// reach their thresholds, so these entries carry full strength.
+		switch {
+		case regime.Regime == RegimeTrend:
+		case h.trendSide == types.Buy:
 			if deltaRaw > flatTolerance && rsiVal >= rsOverbought && mfiVal >= mfiOverbought {
+				h.setIntent(IntentShort, 1, close)
 				h.openOpposite(types.Sell, close)
 				h.state = stateIdle
 			}
-		} else {
+		default:
 			if deltaRaw < -flatTolerance && rsiVal <= rsOversold && mfiVal <= mfiOversold {
+				h.setIntent(IntentLong, 1, close)
 				h.openOpposite(types.Buy, close)
 				h.state = stateIdle
 			}
 		}
 		// Manage any open position.
-		if posQty != 0 && h.Cfg.TrailingPct > 0 {
+		if posQty != 0 && h.trailingEnabled() {
 			h.applyTrailingStop(close)
 		}
 	}
 }
 
+// flatTolerance is the smallest close‑to‑close move that counts as trend
+// momentum: a tenth of the ATR once the regime is known, 5 bps of the price
+// before.
+func (h *HybridTrendMeanReversion) flatTolerance(close float64) float64 {
+	if atr := h.Regime().ATR; atr > 0 {
+		return 0.1 * atr
+	}
+	return 0.0005 * close
+}
+
 // enterTrend opens a position in the direction indicated by the HMA crossover.
 func (h *HybridTrendMeanReversion) enterTrend(side types.Side, price float64) {
+	if !h.canOpen(side) {
+		return
+	}
 	qty := h.calcQty(price)
 	if qty <= 0 {
 		return
@@ -165,7 +192,7 @@ func (h *HybridTrendMeanReversion) enterTrend(side types.Side, price float64) {
 
 // exitTrend closes the current trend position (if any) and stays in REVERT.
 func (h *HybridTrendMeanReversion) exitTrend(price float64) {
-	qty, _ := h.Exec.Position(h.Symbol)
+	qty, _ := h.position()
 	if qty == 0 {
 		return
 	}
@@ -174,6 +201,9 @@ func (h *HybridTrendMeanReversion) exitTrend(price float64) {
 
 // openOpposite opens a contrarian trade during the REVERT phase.
 func (h *HybridTrendMeanReversion) openOpposite(side types.Side, price float64) {
+	if !h.canOpen(side) {
+		return
+	}
 	qty := h.calcQty(price)
 	if qty <= 0 {
 		return
@@ -0,0 +1,142 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/logger"
+)
+
+// BuildIndicatorConfig derives the goti configuration for a strategy from
+// its StrategyConfig.  Fields are mapped as follows:
+//
+//	StrategyConfig    goti.IndicatorConfig  forwarded when
+//	RSIOverbought  →  RSIOverbought         0 ≤ RSIOversold < RSIOverbought ≤ 100
+//	RSIOversold    →  RSIOversold           (same pair rule)
+//	MFIOverbought  →  MFIOverbought         0 ≤ MFIOversold < MFIOverbought ≤ 100
+//	MFIOversold    →  MFIOversold           (same pair rule)
+//	ADMOOverbought →  AMDOOverbought        ADMOOversold < ADMOOverbought
+//	ADMOOversold   →  AMDOOversold          (same pair rule)
+//	VWAOStrongTrend → VWAOStrongTrend       0 < VWAOStrongTrend ≤ 100
+//	ATSEMAperiod   →  ATSEMAperiod          ATSEMAperiod > 0
+//	HMAPeriod      →  (none)                goti fixes the suite HMA at 9 bars
+//
+// A value outside its rule is not a valid goti setting, so the indicator
+// keeps the goti default and the value only acts as a strategy‑side gate.
+// IndicatorConfigWarnings reports every value dropped this way, and the
+// strategy constructors log them.  Finally cfg.IndicatorOverrides is
+// applied by goti field name; unknown names are an error.
+func BuildIndicatorConfig(cfg config.StrategyConfig) (goti.IndicatorConfig, error) {
+	ic := goti.DefaultConfig()
+	if oscillatorPair(cfg.RSIOversold, cfg.RSIOverbought) {
+		ic.RSIOverbought, ic.RSIOversold = cfg.RSIOverbought, cfg.RSIOversold
+	}
+	if oscillatorPair(cfg.MFIOversold, cfg.MFIOverbought) {
+		ic.MFIOverbought, ic.MFIOversold = cfg.MFIOverbought, cfg.MFIOversold
+	}
+	if cfg.ADMOOversold < cfg.ADMOOverbought {
+		ic.AMDOOverbought, ic.AMDOOversold = cfg.ADMOOverbought, cfg.ADMOOversold
+	}
+	if cfg.VWAOStrongTrend > 0 && cfg.VWAOStrongTrend <= 100 {
+		ic.VWAOStrongTrend = cfg.VWAOStrongTrend
+	}
+	if cfg.ATSEMAperiod > 0 {
+		ic.ATSEMAperiod = cfg.ATSEMAperiod
+	}
+	for name, v := range cfg.IndicatorOverrides {
+		if err := applyIndicatorOverride(&ic, name, v); err != nil {
+			return ic, err
+		}
+	}
+	return ic, ic.Validate()
+}
+
+// IndicatorConfigWarnings describes every StrategyConfig value that
+// BuildIndicatorConfig does not forward to goti: an HMAPeriod other than the
+// fixed suite period, and set thresholds that break their rule (unset pairs
+// are not reported).  Fields covered by cfg.IndicatorOverrides are skipped.
+func IndicatorConfigWarnings(cfg config.StrategyConfig) []string {
+	var out []string
+	if cfg.HMAPeriod != suiteHMAPeriod {
+		out = append(out, fmt.Sprintf("HMAPeriod %d is ignored: goti fixes the suite HMA at %d bars",
+			cfg.HMAPeriod, suiteHMAPeriod))
+	}
+	dropped := func(field, gotiField string, lo, hi float64, ok bool) {
+		if ok || (lo == 0 && hi == 0) {
+			return
+		}
+		if _, set := cfg.IndicatorOverrides[gotiField]; set {
+			return
+		}
+		out = append(out, fmt.Sprintf("%s (%v/%v) is out of range: goti keeps its default", field, lo, hi))
+	}
+	dropped("RSIOversold/RSIOverbought", "RSIOverbought",
+		cfg.RSIOversold, cfg.RSIOverbought, oscillatorPair(cfg.RSIOversold, cfg.RSIOverbought))
+	dropped("MFIOversold/MFIOverbought", "MFIOverbought",
+		cfg.MFIOversold, cfg.MFIOverbought, oscillatorPair(cfg.MFIOversold, cfg.MFIOverbought))
+	dropped("ADMOOversold/ADMOOverbought", "AMDOOverbought",
+		cfg.ADMOOversold, cfg.ADMOOverbought, cfg.ADMOOversold < cfg.ADMOOverbought)
+	if v := cfg.VWAOStrongTrend; v > 100 || v < 0 {
+		if _, set := cfg.IndicatorOverrides["VWAOStrongTrend"]; !set {
+			out = append(out, fmt.Sprintf("VWAOStrongTrend (%v) is out of range: goti keeps its default", v))
+		}
+	}
+	return out
+}
+
+// warnIndicatorConfig logs IndicatorConfigWarnings.
+func warnIndicatorConfig(log logger.Logger, cfg config.StrategyConfig) {
+	for _, msg := range IndicatorConfigWarnings(cfg) {
+		log.Warn("indicator_config_ignored", logger.String("msg", msg))
+	}
+}
+
+// oscillatorPair reports whether lo/hi are usable 0–100 oscillator levels.
+func oscillatorPair(lo, hi float64) bool {
+	return lo >= 0 && hi <= 100 && lo < hi
+}
+
+func applyIndicatorOverride(ic *goti.IndicatorConfig, name string, v float64) error {
+	switch name {
+	case "RSIOverbought":
+		ic.RSIOverbought = v
+	case "RSIOversold":
+		ic.RSIOversold = v
+	case "MFIOverbought":
+		ic.MFIOverbought = v
+	case "MFIOversold":
+		ic.MFIOversold = v
+	case "MFIVolumeScale":
+		ic.MFIVolumeScale = v
+	case "AMDOOverbought":
+		ic.AMDOOverbought = v
+	case "AMDOOversold":
+		ic.AMDOOversold = v
+	case "AMDOScaling":
+		ic.AMDOScaling = v
+	case "VWAOStrongTrend":
+		ic.VWAOStrongTrend = v
+	case "ATSEMAperiod":
+		if v != math.Trunc(v) {
+			return fmt.Errorf("indicator override ATSEMAperiod must be an integer, got %v", v)
+		}
+		ic.ATSEMAperiod = int(v)
+	default:
+		return fmt.Errorf("unknown indicator override %q", name)
+	}
+	return nil
+}
+
+// suiteFactoryFor returns the suite factory every strategy passes to
+// NewBaseStrategy: a goti suite configured by BuildIndicatorConfig.
+func suiteFactoryFor(cfg config.StrategyConfig) func() (*goti.IndicatorSuite, error) {
+	return func() (*goti.IndicatorSuite, error) {
+		ic, err := BuildIndicatorConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return goti.NewIndicatorSuiteWithConfig(ic)
+	}
+}
@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+)
+
+func TestBuildIndicatorConfig_MapsStrategyFields(t *testing.T) {
+	cfg := buildConfig()
+	cfg.RSIOverbought, cfg.RSIOversold = 75, 25
+	cfg.MFIOverbought, cfg.MFIOversold = 85, 15
+	cfg.ADMOOverbought, cfg.ADMOOversold = 1.5, -1.5
+	cfg.VWAOStrongTrend = 60
+	cfg.ATSEMAperiod = 7
+
+	ic, err := BuildIndicatorConfig(cfg)
+	if err != nil {
+		t.Fatalf("BuildIndicatorConfig: %v", err)
+	}
+	if ic.RSIOverbought != 75 || ic.RSIOversold != 25 ||
+		ic.MFIOverbought != 85 || ic.MFIOversold != 15 ||
+		ic.AMDOOverbought != 1.5 || ic.AMDOOversold != -1.5 ||
+		ic.VWAOStrongTrend != 60 || ic.ATSEMAperiod != 7 {
+		t.Fatalf("fields not forwarded: %+v", ic)
+	}
+}
+
+func TestBuildIndicatorConfig_GateOnlyThresholdsKeepDefaults(t *testing.T) {
+	// buildConfig inverts the RSI/MFI levels and uses a huge VWAO level so
+	// the strategy gates always pass; goti must keep its own defaults.
+	ic, err := BuildIndicatorConfig(buildConfig())
+	if err != nil {
+		t.Fatalf("BuildIndicatorConfig: %v", err)
+	}
+	def := goti.DefaultConfig()
+	if ic.RSIOverbought != def.RSIOverbought || ic.MFIOversold != def.MFIOversold ||
+		ic.VWAOStrongTrend != def.VWAOStrongTrend {
+		t.Fatalf("out‑of‑range thresholds leaked into goti: %+v", ic)
+	}
+}
+
+func TestBuildIndicatorConfig_Overrides(t *testing.T) {
+	cfg := buildConfig()
+	cfg.IndicatorOverrides = map[string]float64{
+		"MFIVolumeScale": 1000,
+		"AMDOScaling":    25,
+		"RSIOverbought":  80,
+	}
+	ic, err := BuildIndicatorConfig(cfg)
+	if err != nil {
+		t.Fatalf("BuildIndicatorConfig: %v", err)
+	}
+	if ic.MFIVolumeScale != 1000 || ic.AMDOScaling != 25 || ic.RSIOverbought != 80 {
+		t.Fatalf("overrides not applied: %+v", ic)
+	}
+
+	cfg.IndicatorOverrides = map[string]float64{"HMAPeriodTypo": 3}
+	if _, err := BuildIndicatorConfig(cfg); err == nil {
+		t.Fatal("expected error for unknown override")
+	}
+	if _, err := NewMeanReversion("TEST", cfg, testutils.NewMockExecutor(1000), testutils.NewMockLogger()); err == nil {
+		t.Fatal("strategy constructor must surface indicator config errors")
+	}
+
+	cfg.IndicatorOverrides = map[string]float64{"ATSEMAperiod": 0}
+	if _, err := BuildIndicatorConfig(cfg); err == nil {
+		t.Fatal("expected goti validation error for ATSEMAperiod 0")
+	}
+}
+
+func TestRiskParity_UsesMappedIndicatorConfig(t *testing.T) {
+	cfg := config.StrategyConfig{}
+	cfg.IndicatorOverrides = map[string]float64{"Bogus": 1}
+	if _, err := NewRiskParityRotation([]string{"A"}, cfg, testutils.NewMockExecutor(1000), 1, 1, testutils.NewMockLogger()); err == nil {
+		t.Fatal("expected RiskParityRotation to reject an unknown indicator override")
+	}
+}
+
+func TestIndicatorConfigWarnings(t *testing.T) {
+	cfg := buildConfig()
+	cfg.RSIOverbought, cfg.RSIOversold = 75, 25
+	cfg.MFIOverbought, cfg.MFIOversold = 85, 15
+	cfg.VWAOStrongTrend = 60
+	if w := IndicatorConfigWarnings(cfg); len(w) != 0 {
+		t.Fatalf("a fully forwarded config must not warn, got %v", w)
+	}
+
+	cfg.HMAPeriod = 21
+	cfg.RSIOverbought, cfg.RSIOversold = 20, 80
+	cfg.VWAOStrongTrend = 150
+	w := IndicatorConfigWarnings(cfg)
+	if len(w) != 3 || !strings.HasPrefix(w[0], "HMAPeriod 21") ||
+		!strings.HasPrefix(w[1], "RSIOversold/RSIOverbought") || !strings.HasPrefix(w[2], "VWAOStrongTrend") {
+		t.Fatalf("unexpected warnings %v", w)
+	}
+
+	cfg.IndicatorOverrides = map[string]float64{"RSIOverbought": 70}
+	if w := IndicatorConfigWarnings(cfg); len(w) != 2 {
+		t.Fatalf("an overridden threshold must not warn, got %v", w)
+	}
+
+	log := testutils.NewMockLogger()
+	if _, err := NewMeanReversion("TEST", cfg, testutils.NewMockExecutor(1000), log); err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+	if !log.HasMessage("indicator_config_ignored") {
+		t.Fatal("expected the constructor to log the dropped values")
+	}
+}
@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"errors"
+	"math"
+)
+
+// Intent is the position a strategy wants to hold.
+type Intent int
+
+const (
+	IntentShort Intent = -1
+	IntentFlat  Intent = 0
+	IntentLong  Intent = 1
+)
+
+// String implements fmt.Stringer.
+func (i Intent) String() string {
+	switch i {
+	case IntentLong:
+		return "long"
+	case IntentShort:
+		return "short"
+	default:
+		return "flat"
+	}
+}
+
+// ErrSignalOnlyUnsupported is returned by SetSignalOnly and SetTargetMode
+// of strategies whose decisions are quotes rather than a side to hold.
+var ErrSignalOnlyUnsupported = errors.New("strategy does not decide a side to hold and cannot run signal-only")
+
+// Intent returns the side the strategy's decision code last asked for.  An
+// entry is recorded when the signal fires, before sizing, cash and order
+// checks, so Intent can be long while no order went out; a full close
+// records flat.  Sides forbidden by Cfg.PositionMode are recorded as flat.
+func (b *BaseStrategy) Intent() Intent { return b.intent }
+
+// Strength returns the conviction in [0, 1] recorded with Intent; see
+// conviction.  It is 0 while the intent is flat.
+func (b *BaseStrategy) Strength() float64 { return b.strength }
+
+// SetSignalOnly stops the strategy from submitting orders: its decision
+// code still records an Intent every bar, but submitOrder does nothing and
+// the exits run against a notional one‑unit position opened at the price
+// the intent was recorded at.  MarketMaker returns
+// ErrSignalOnlyUnsupported.
+func (b *BaseStrategy) SetSignalOnly() error {
+	b.signalOnly = true
+	b.resetPosition()
+	delete(b.partials, b.Symbol)
+	return nil
+}
+
+// setIntent records the side the decision code wants to hold at price
+// together with its strength.  Strategies call it before the orders that
+// carry the decision out.
+func (b *BaseStrategy) setIntent(i Intent, strength, price float64) {
+	mode := b.Cfg.PositionMode
+	if (i == IntentLong && !mode.AllowsLong()) || (i == IntentShort && !mode.AllowsShort()) {
+		i = IntentFlat
+	}
+	if i == IntentFlat {
+		strength = 0
+	}
+	if i != b.intent {
+		b.intentPrice = price
+	}
+	b.intent = i
+	b.strength = math.Max(0, math.Min(1, strength))
+	b.intentSeq = b.barSeq
+}
+
+// closeIntent records the flat intent of a full close unless the decision
+// code already recorded a new intent on this bar, as it does before
+// closing a position it reverses.
+func (b *BaseStrategy) closeIntent(price float64) {
+	if b.intentSeq == b.barSeq && b.intent != IntentFlat {
+		return
+	}
+	b.setIntent(IntentFlat, 0, price)
+}
+
+// position returns the signed quantity and average price the exits and
+// decision code work with: the executor's position, or in signal‑only
+// mode one unit on the side of the intent at the price it was recorded.
+func (b *BaseStrategy) position() (float64, float64) {
+	if !b.signalOnly {
+		return b.Exec.Position(b.Symbol)
+	}
+	if b.intent == IntentFlat {
+		return 0, 0
+	}
+	return float64(b.intent), b.intentPrice
+}
+
+// conviction scores the named signals of the current bar: 1 for each that
+// an indicator fired, 0.5 for each only a fallback heuristic fired and 0
+// for the others, averaged over the names.
+func (b *BaseStrategy) conviction(names ...string) float64 {
+	if len(names) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, name := range names {
+		for _, s := range b.signals {
+			if s.Name != name {
+				continue
+			}
+			switch s.Source {
+			case SourceIndicator, SourceBoth:
+				sum++
+			case SourceFallback:
+				sum += 0.5
+			}
+			break
+		}
+	}
+	return sum / float64(len(names))
+}
+
+// sideOf returns the side of a signed quantity.
+func sideOf(qty float64) Intent {
+	switch {
+	case qty > 0:
+		return IntentLong
+	case qty < 0:
+		return IntentShort
+	default:
+		return IntentFlat
+	}
+}
@@ -0,0 +1,114 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/testutils"
+)
+
+func TestIntentRecordedBeforeSizing(t *testing.T) {
+	exec := testutils.NewMockExecutor(0) // nothing to size an order with
+	d, err := NewDonchianBreakout("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDonchianBreakout failed: %v", err)
+	}
+	feedBars(t, d, rangeBars(30, 100))
+	if d.Intent() != IntentFlat {
+		t.Fatalf("no breakout inside the range, intent %v", d.Intent())
+	}
+	feedBars(t, d, trendBars(102))
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("an empty account cannot trade, got %+v", exec.Orders())
+	}
+	if d.Intent() != IntentLong || d.Strength() != 1 {
+		t.Fatalf("the breakout must be recorded although it sized to 0, intent %v strength %v",
+			d.Intent(), d.Strength())
+	}
+}
+
+func TestSignalOnlySubmitsNothingAndExits(t *testing.T) {
+	exec := testutils.NewMockExecutor(10_000)
+	d, err := NewDonchianBreakout("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDonchianBreakout failed: %v", err)
+	}
+	if err := d.SetSignalOnly(); err != nil {
+		t.Fatalf("SetSignalOnly failed: %v", err)
+	}
+	feedBars(t, d, rangeBars(30, 100))
+	feedBars(t, d, trendBars(102))
+	if d.Intent() != IntentLong || d.Units() != 1 {
+		t.Fatalf("expected the breakout unit, intent %v units %d", d.Intent(), d.Units())
+	}
+	if qty, avg := d.position(); qty != 1 || avg != 102 {
+		t.Fatalf("exits must see one unit at the breakout close, got %v @ %v", qty, avg)
+	}
+
+	data, err := d.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	restored, err := NewDonchianBreakout("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDonchianBreakout failed: %v", err)
+	}
+	if err := restored.SetSignalOnly(); err != nil {
+		t.Fatalf("SetSignalOnly failed: %v", err)
+	}
+	if err := restored.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if qty, avg := restored.position(); restored.Intent() != IntentLong || qty != 1 || avg != 102 {
+		t.Fatalf("Restore must bring the intent back, got %v (%v @ %v)", restored.Intent(), qty, avg)
+	}
+
+	feedBars(t, d, trendBars(90))
+	if d.Intent() != IntentFlat || d.Units() != 0 {
+		t.Fatalf("the stop must flatten the intent, intent %v units %d", d.Intent(), d.Units())
+	}
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("signal‑only strategies must not submit, got %+v", exec.Orders())
+	}
+}
+
+func TestCloseKeepsIntentDeclaredOnTheBar(t *testing.T) {
+	exec := testutils.NewMockExecutor(10_000)
+	mr, err := NewMeanReversion("TEST", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+	if err := mr.SetSignalOnly(); err != nil {
+		t.Fatalf("SetSignalOnly failed: %v", err)
+	}
+	mr.journalBar(101, 99, 100, 1000)
+	mr.setIntent(IntentShort, 1, 100)
+
+	// A reversal records the new side before closing the old one.
+	mr.journalBar(102, 100, 101, 1000)
+	mr.setIntent(IntentLong, 0.5, 101)
+	mr.closePosition(101, "mr_close_short")
+	if mr.Intent() != IntentLong || mr.Strength() != 0.5 {
+		t.Fatalf("the close of a reversal must keep the new intent, got %v %v", mr.Intent(), mr.Strength())
+	}
+
+	// An exit on a later bar flattens it.
+	mr.journalBar(103, 101, 102, 1000)
+	mr.closePosition(102, "mr_tp")
+	if mr.Intent() != IntentFlat || mr.Strength() != 0 {
+		t.Fatalf("an exit must record flat, got %v %v", mr.Intent(), mr.Strength())
+	}
+}
+
+func TestIntentRespectsPositionMode(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	mr, err := NewMeanReversion("TEST", cfg, testutils.NewMockExecutor(10_000), testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+	mr.setIntent(IntentShort, 1, 100)
+	if mr.Intent() != IntentFlat {
+		t.Fatalf("a long‑only strategy cannot intend a short, got %v", mr.Intent())
+	}
+}
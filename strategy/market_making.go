@@ -0,0 +1,372 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// FairValue selects the price MarketMaker quotes around.
+type FairValue string
+
+const (
+	// FairMid is the midpoint of the best bid and ask.
+	FairMid FairValue = "mid"
+	// FairMicro weights the best prices by the opposite size,
+	// (bid·askSize + ask·bidSize) / (bidSize + askSize), which leans
+	// towards the side that is about to be taken out.
+	FairMicro FairValue = "micro"
+)
+
+// VolSource selects the volatility estimate that widens the spread.
+type VolSource string
+
+const (
+	// VolATR uses a goti AverageTrueRange over ATRPeriod bars.
+	VolATR VolSource = "atr"
+	// VolATSO uses the suite's ATSO magnitude, the ATR proxy of the other
+	// strategies.
+	VolATSO VolSource = "atso"
+)
+
+// MarketMakerConfig tunes MarketMaker beyond the shared StrategyConfig.
+type MarketMakerConfig struct {
+	FairValue FairValue
+	VolSource VolSource
+	ATRPeriod int
+
+	// The half‑spread is BaseSpread/2 of the fair value plus VolMultiplier
+	// times the volatility estimate.
+	BaseSpread    float64
+	VolMultiplier float64
+
+	// InventorySkew shifts both quotes against the inventory: at
+	// |position| = MaxInventory they move by InventorySkew half‑spreads.
+	InventorySkew float64
+
+	// QuoteQty is the size of each quote; 0 sizes it with risk.CalcQty.
+	QuoteQty float64
+	// MaxInventory bounds |position| in units; the quote that would grow
+	// the position beyond it is pulled.  0 means 5 × the quote size.
+	MaxInventory float64
+
+	// TickSize rounds bids down and asks up to the price grid (0 = none).
+	TickSize float64
+}
+
+// DefaultMarketMakerConfig quotes around the micro‑price with a 10 bp base
+// spread widened by half a 14‑bar ATR on each side and a full half‑spread
+// inventory skew.
+func DefaultMarketMakerConfig() MarketMakerConfig {
+	return MarketMakerConfig{
+		FairValue:     FairMicro,
+		VolSource:     VolATR,
+		ATRPeriod:     14,
+		BaseSpread:    0.001,
+		VolMultiplier: 0.5,
+		InventorySkew: 1,
+	}
+}
+
+// Validate checks the market‑making settings.
+func (mc MarketMakerConfig) Validate() error {
+	switch mc.FairValue {
+	case FairMid, FairMicro:
+	default:
+		return fmt.Errorf("unknown fair value %q", mc.FairValue)
+	}
+	switch mc.VolSource {
+	case VolATR, VolATSO:
+	default:
+		return fmt.Errorf("unknown volatility source %q", mc.VolSource)
+	}
+	if mc.ATRPeriod < 1 || mc.ATRPeriod > 63 {
+		return fmt.Errorf("ATRPeriod (%d) must be between 1 and 63", mc.ATRPeriod)
+	}
+	if mc.BaseSpread <= 0 || mc.BaseSpread >= 0.5 {
+		return fmt.Errorf("BaseSpread (%f) must be >0 and <0.5", mc.BaseSpread)
+	}
+	if mc.VolMultiplier < 0 {
+		return fmt.Errorf("VolMultiplier (%f) must not be negative", mc.VolMultiplier)
+	}
+	if mc.InventorySkew < 0 || mc.InventorySkew > 2 {
+		return fmt.Errorf("InventorySkew (%f) must be between 0 and 2", mc.InventorySkew)
+	}
+	if mc.QuoteQty < 0 || mc.MaxInventory < 0 || mc.TickSize < 0 {
+		return fmt.Errorf("QuoteQty, MaxInventory and TickSize must not be negative")
+	}
+	return nil
+}
+
+// MarketMaker quotes a bid and an ask around a fair value taken from the
+// top of the book (UpdateBook) or, without one, the last close.  Every bar
+// it replaces its quotes: the spread widens with volatility, both quotes
+// are skewed against the inventory reported by Executor.Position, and the
+// side that would push |position| past MaxInventory is pulled.
+//
+// Quotes rest on a LimitExecutor; a quote that is no longer open has been
+// filled.  Backtests call MatchBar on the paper executor before
+// ProcessBar.
+type MarketMaker struct {
+	*BaseStrategy
+	exec executor.LimitExecutor
+	mm   MarketMakerConfig
+	atr  *goti.AverageTrueRange
+
+	bid, ask         float64 // top of the book
+	bidSize, askSize float64
+	hasBook          bool
+
+	quoteBid, quoteAsk float64 // our resting quotes
+	bidID, askID       string
+}
+
+// NewMarketMaker quotes symbol with DefaultMarketMakerConfig.
+func NewMarketMaker(symbol string, cfg config.StrategyConfig,
+	exec executor.LimitExecutor, log logger.Logger) (*MarketMaker, error) {
+
+	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactoryFor(cfg), log)
+	if err != nil {
+		return nil, err
+	}
+	m := &MarketMaker{BaseStrategy: base, exec: exec, mm: DefaultMarketMakerConfig()}
+	if m.atr, err = m.newATR(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SetMarketMakerConfig replaces the market‑making settings after
+// validating them.  The ATR is rebuilt from the journaled bars.
+func (m *MarketMaker) SetMarketMakerConfig(mc MarketMakerConfig) error {
+	if err := mc.Validate(); err != nil {
+		return logOutputError(m.Log, err.Error())
+	}
+	m.mm = mc
+	return m.replayATR()
+}
+
+// UpdateBook records the best bid and ask with their sizes; the next
+// ProcessBar quotes around them.
+func (m *MarketMaker) UpdateBook(bid, ask, bidSize, askSize float64) {
+	if bid <= 0 || ask < bid || bidSize < 0 || askSize < 0 {
+		m.Log.Warn("mm_invalid_book",
+			logger.String("symbol", m.Symbol),
+			logger.Float64("bid", bid),
+			logger.Float64("ask", ask),
+		)
+		return
+	}
+	m.bid, m.ask, m.bidSize, m.askSize, m.hasBook = bid, ask, bidSize, askSize, true
+}
+
+// Quotes returns the resting bid and ask; a pulled side is 0.
+func (m *MarketMaker) Quotes() (bid, ask float64) {
+	if m.bidID != "" {
+		bid = m.quoteBid
+	}
+	if m.askID != "" {
+		ask = m.quoteAsk
+	}
+	return bid, ask
+}
+
+// fairValue returns the price the next quotes are centred on before the
+// inventory skew.
+func (m *MarketMaker) fairValue(close float64) float64 {
+	if !m.hasBook {
+		return close
+	}
+	if m.mm.FairValue == FairMicro && m.bidSize+m.askSize > 0 {
+		return (m.bid*m.askSize + m.ask*m.bidSize) / (m.bidSize + m.askSize)
+	}
+	return (m.bid + m.ask) / 2
+}
+
+// ProcessBar updates the volatility estimates, accounts for filled quotes
+// and replaces the quotes.
+func (m *MarketMaker) ProcessBar(high, low, close, volume float64) {
+	defer m.flushDecision()
+	if err := m.Suite.Add(high, low, close, volume); err != nil {
+		m.Log.Warn("suite_add_error", logger.Err(err))
+		return
+	}
+	if err := m.atr.AddCandle(high, low, close); err != nil {
+		m.Log.Warn("atr_add_error", logger.Err(err))
+	}
+	m.recordBar(high, low, close, volume)
+	m.syncFills()
+	m.cancelQuotes()
+	if !m.hasHistory(m.mm.ATRPeriod + 1) {
+		return
+	}
+	m.quote(close)
+}
+
+// Cancel pulls both quotes, e.g. before shutting down.
+func (m *MarketMaker) Cancel() {
+	m.syncFills()
+	m.cancelQuotes()
+}
+
+// SetSignalOnly returns ErrSignalOnlyUnsupported: the strategy decides
+// quotes on its limit executor rather than a side to hold.
+func (m *MarketMaker) SetSignalOnly() error { return ErrSignalOnlyUnsupported }
+
+// SetTargetMode returns ErrSignalOnlyUnsupported, see SetSignalOnly.
+func (m *MarketMaker) SetTargetMode(TargetSink) error { return ErrSignalOnlyUnsupported }
+
+// volatility returns the per‑bar volatility estimate in price units.
+func (m *MarketMaker) volatility(price float64) float64 {
+	if m.mm.VolSource == VolATSO {
+		return m.atrEstimate(price)
+	}
+	atr, err := m.atr.Calculate()
+	if err != nil {
+		return 0
+	}
+	return atr
+}
+
+// quote places a bid and an ask around the skewed fair value.
+func (m *MarketMaker) quote(close float64) {
+	fair := m.fairValue(close)
+	half := fair*m.mm.BaseSpread/2 + m.mm.VolMultiplier*m.volatility(fair)
+
+	qty := m.mm.QuoteQty
+	if qty == 0 {
+		qty = risk.CalcQty(m.Exec.Equity(), m.Cfg.MaxRiskPerTrade, m.Cfg.StopLossPct, fair, m.Cfg)
+	}
+	maxInv := m.mm.MaxInventory
+	if maxInv == 0 {
+		maxInv = 5 * qty
+	}
+	pos, _ := m.Exec.Position(m.Symbol)
+	inv := 0.0
+	if maxInv > 0 {
+		inv = math.Max(-1, math.Min(1, pos/maxInv))
+	}
+	reservation := fair - inv*m.mm.InventorySkew*half
+	bid, ask := reservation-half, reservation+half
+	if t := m.mm.TickSize; t > 0 {
+		bid = math.Floor(bid/t) * t
+		ask = math.Ceil(ask/t) * t
+	}
+
+	minPos, maxPos := 0.0, 0.0
+	if m.Cfg.PositionMode.AllowsLong() {
+		maxPos = maxInv
+	}
+	if m.Cfg.PositionMode.AllowsShort() {
+		minPos = -maxInv
+	}
+	bidQty := risk.InventoryLimit(pos, qty, minPos, maxPos, m.Cfg)
+	askQty := risk.InventoryLimit(pos, -qty, minPos, maxPos, m.Cfg)
+
+	m.bidID = m.place(types.Buy, bidQty, bid, "mm_bid", pos)
+	m.quoteBid = bid
+	m.askID = m.place(types.Sell, askQty, ask, "mm_ask", pos)
+	m.quoteAsk = ask
+}
+
+// place rests one quote and returns its id, or "" when the side is pulled.
+func (m *MarketMaker) place(side types.Side, qty, price float64, ctx string, pos float64) string {
+	if qty <= 0 || price <= 0 {
+		m.Log.Info("mm_quote_pulled",
+			logger.String("symbol", m.Symbol),
+			logger.String("side", string(side)),
+			logger.Float64("position", pos),
+		)
+		return ""
+	}
+	o := types.Order{Symbol: m.Symbol, Side: side, Qty: qty, Price: price, Comment: ctx}
+	id, err := m.exec.PlaceLimit(o)
+	if err != nil {
+		m.Log.Warn("mm_quote_error",
+			logger.String("symbol", m.Symbol),
+			logger.String("side", string(side)),
+			logger.Err(err),
+		)
+		return ""
+	}
+	m.traceOrder(o, ctx)
+	return id
+}
+
+// syncFills logs the quotes filled since the previous bar.
+func (m *MarketMaker) syncFills() {
+	if m.bidID == "" && m.askID == "" {
+		return
+	}
+	open := make(map[string]bool)
+	for _, o := range m.exec.OpenOrders(m.Symbol) {
+		open[o.ID] = true
+	}
+	for _, q := range []struct {
+		id    *string
+		side  types.Side
+		price float64
+	}{{&m.bidID, types.Buy, m.quoteBid}, {&m.askID, types.Sell, m.quoteAsk}} {
+		if *q.id == "" || open[*q.id] {
+			continue
+		}
+		*q.id = ""
+		pos, _ := m.Exec.Position(m.Symbol)
+		m.Log.Info("mm_fill",
+			logger.String("symbol", m.Symbol),
+			logger.String("side", string(q.side)),
+			logger.Float64("price", q.price),
+			logger.Float64("position", pos),
+		)
+	}
+}
+
+// cancelQuotes cancels the quotes that are still resting.
+func (m *MarketMaker) cancelQuotes() {
+	for _, id := range []*string{&m.bidID, &m.askID} {
+		if *id == "" {
+			continue
+		}
+		if err := m.exec.Cancel(*id); err != nil {
+			m.Log.Warn("mm_cancel_error",
+				logger.String("symbol", m.Symbol),
+				logger.Err(err),
+			)
+		}
+		*id = ""
+	}
+}
+
+func (m *MarketMaker) newATR() (*goti.AverageTrueRange, error) {
+	return goti.NewAverageTrueRangeWithParams(m.mm.ATRPeriod)
+}
+
+// replayATR rebuilds the ATR from the bar journal.
+func (m *MarketMaker) replayATR() error {
+	atr, err := m.newATR()
+	if err != nil {
+		return err
+	}
+	for _, b := range m.bars {
+		_ = atr.AddCandle(b.High, b.Low, b.Close)
+	}
+	m.atr = atr
+	return nil
+}
+
+// WarmupBars returns the history Warmup needs before the first quote.
+func (m *MarketMaker) WarmupBars() int { return m.warmupBars(m.mm.ATRPeriod + 1) }
+
+// Warmup feeds historical bars to the suite and the ATR without quoting.
+func (m *MarketMaker) Warmup(bars []types.Bar) {
+	m.warmup(bars)
+	for _, b := range bars {
+		_ = m.atr.AddCandle(b.High, b.Low, b.Close)
+	}
+}
@@ -0,0 +1,293 @@
+package strategy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+func mmConfig() MarketMakerConfig {
+	mc := DefaultMarketMakerConfig()
+	mc.QuoteQty = 10
+	mc.MaxInventory = 30
+	return mc
+}
+
+func buildMarketMaker(t *testing.T, cfg config.StrategyConfig, mc MarketMakerConfig) (*MarketMaker, *executor.PaperExecutor, *testutils.MockLogger) {
+	t.Helper()
+	exec := executor.NewPaperExecutor(100_000)
+	log := testutils.NewMockLogger()
+	m, err := NewMarketMaker("MM", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewMarketMaker failed: %v", err)
+	}
+	if err := m.SetMarketMakerConfig(mc); err != nil {
+		t.Fatalf("SetMarketMakerConfig failed: %v", err)
+	}
+	return m, exec, log
+}
+
+// flowBar is one bar of simulated order flow: the book before the bar and
+// the range traded through it.
+type flowBar struct {
+	bid, ask, bidSize, askSize float64
+	high, low, close           float64
+}
+
+// orderFlow simulates a mean‑reverting fair price around 100 with a one
+// cent wide book, random book imbalance and takers sweeping a random
+// distance through either side every bar.
+func orderFlow(n int, seed int64, sweep float64) []flowBar {
+	rng := rand.New(rand.NewSource(seed))
+	bars := make([]flowBar, n)
+	p := 100.0
+	for i := range bars {
+		b := flowBar{
+			bid:     p - 0.05,
+			ask:     p + 0.05,
+			bidSize: 1 + 9*rng.Float64(),
+			askSize: 1 + 9*rng.Float64(),
+		}
+		next := p + 0.2*(100-p) + 0.4*rng.NormFloat64()
+		b.high = math.Max(p, next) + sweep*math.Abs(rng.NormFloat64())
+		b.low = math.Min(p, next) - sweep*math.Abs(rng.NormFloat64())
+		b.close = next
+		bars[i] = b
+		p = next
+	}
+	return bars
+}
+
+// calmFlow is a flat book at 100 with a constant bar range.
+func calmFlow(n int, rangeHalf float64) []flowBar {
+	bars := make([]flowBar, n)
+	for i := range bars {
+		bars[i] = flowBar{bid: 99.95, ask: 100.05, bidSize: 5, askSize: 5,
+			high: 100 + rangeHalf, low: 100 - rangeHalf, close: 100}
+	}
+	return bars
+}
+
+// runFlow replays the flow as a backtest loop would: the book is updated,
+// the bar is matched against the resting quotes and then processed.
+func runFlow(t *testing.T, m *MarketMaker, exec *executor.PaperExecutor, bars []flowBar, maxInv float64) {
+	t.Helper()
+	for i, b := range bars {
+		m.UpdateBook(b.bid, b.ask, b.bidSize, b.askSize)
+		exec.MatchBar("MM", b.high, b.low)
+		m.ProcessBar(b.high, b.low, b.close, 1000)
+		if pos, _ := exec.Position("MM"); math.Abs(pos) > maxInv+1e-9 {
+			t.Fatalf("bar %d: inventory %v exceeds %v", i, pos, maxInv)
+		}
+	}
+}
+
+func TestMarketMakerQuotesAroundFairValue(t *testing.T) {
+	m, exec, _ := buildMarketMaker(t, buildConfig(), mmConfig())
+	runFlow(t, m, exec, calmFlow(20, 0.2), 30)
+
+	bid, ask := m.Quotes()
+	if bid == 0 || ask == 0 {
+		t.Fatalf("expected two‑sided quotes, got %v/%v", bid, ask)
+	}
+	if mid := (bid + ask) / 2; math.Abs(mid-100) > 1e-9 || bid >= 99.95 || ask <= 100.05 {
+		t.Fatalf("flat inventory quotes %v/%v should straddle the book symmetrically", bid, ask)
+	}
+	// Half‑spread: 5 bp of the fair value plus half the 0.4 ATR.
+	if half := (ask - bid) / 2; math.Abs(half-0.25) > 1e-9 {
+		t.Fatalf("half‑spread %v, want 0.25", half)
+	}
+
+	// A heavy bid leans the micro‑price towards the ask.
+	m.UpdateBook(99.95, 100.05, 9, 1)
+	m.ProcessBar(100.2, 99.8, 100, 1000)
+	if bid, ask := m.Quotes(); math.Abs((bid+ask)/2-100.04) > 1e-9 {
+		t.Fatalf("micro‑price quotes %v/%v should centre on 100.04", bid, ask)
+	}
+
+	mc := mmConfig()
+	mc.FairValue = FairMid
+	m, exec, _ = buildMarketMaker(t, buildConfig(), mc)
+	runFlow(t, m, exec, calmFlow(20, 0.2), 30)
+	m.UpdateBook(99.95, 100.05, 9, 1)
+	m.ProcessBar(100.2, 99.8, 100, 1000)
+	if bid, ask := m.Quotes(); math.Abs((bid+ask)/2-100) > 1e-9 {
+		t.Fatalf("mid quotes %v/%v should centre on 100", bid, ask)
+	}
+}
+
+func TestMarketMakerSkewsByInventory(t *testing.T) {
+	m, exec, _ := buildMarketMaker(t, buildConfig(), mmConfig())
+	runFlow(t, m, exec, calmFlow(20, 0.2), 30)
+	_ = exec.Submit(types.Order{Symbol: "MM", Side: types.Buy, Qty: 15, Price: 100})
+	runFlow(t, m, exec, calmFlow(1, 0.2)[:1], 30)
+
+	// Half the inventory limit moves both quotes half a half‑spread down.
+	bid, ask := m.Quotes()
+	if math.Abs(bid-(100-0.125-0.25)) > 1e-9 || math.Abs(ask-(100-0.125+0.25)) > 1e-9 {
+		t.Fatalf("long inventory should skew quotes down, got %v/%v", bid, ask)
+	}
+	if ask-100 >= 100-bid {
+		t.Fatalf("a long book must quote the ask closer to fair: %v/%v", bid, ask)
+	}
+}
+
+func TestMarketMakerWidensWithVolatility(t *testing.T) {
+	spread := func(mc MarketMakerConfig, bars []flowBar) float64 {
+		m, exec, _ := buildMarketMaker(t, buildConfig(), mc)
+		runFlow(t, m, exec, bars, mc.MaxInventory)
+		bid, ask := m.Quotes()
+		if bid == 0 || ask == 0 {
+			t.Fatalf("expected two‑sided quotes, got %v/%v", bid, ask)
+		}
+		return ask - bid
+	}
+	mc := mmConfig()
+	mc.MaxInventory = 1e6 // keep both sides quoted
+
+	// ATR follows the traded range.
+	if calm, wild := spread(mc, calmFlow(30, 0.2)), spread(mc, calmFlow(30, 1.5)); wild <= calm {
+		t.Fatalf("atr: volatile spread %v should exceed calm spread %v", wild, calm)
+	}
+
+	// ATSO follows the swings of the close.
+	mc.VolSource = VolATSO
+	calmBars, wildBars := orderFlow(60, 3, 0.05), orderFlow(60, 3, 0.05)
+	for i := range wildBars {
+		b := &wildBars[i]
+		b.high, b.low, b.close = 100+4*(b.high-100), 100+4*(b.low-100), 100+4*(b.close-100)
+	}
+	if calm, wild := spread(mc, calmBars), spread(mc, wildBars); wild <= calm {
+		t.Fatalf("atso: volatile spread %v should exceed calm spread %v", wild, calm)
+	}
+}
+
+func TestMarketMakerPullsQuotesAtInventoryLimit(t *testing.T) {
+	m, exec, log := buildMarketMaker(t, buildConfig(), mmConfig())
+	runFlow(t, m, exec, calmFlow(20, 0.2), 30)
+	_ = exec.Submit(types.Order{Symbol: "MM", Side: types.Buy, Qty: 30, Price: 100})
+	runFlow(t, m, exec, calmFlow(1, 0.01), 30)
+	if bid, ask := m.Quotes(); bid != 0 || ask == 0 {
+		t.Fatalf("at the long limit only the ask may rest, got %v/%v", bid, ask)
+	}
+	if !log.HasMessage("mm_quote_pulled") {
+		t.Fatal("expected the pulled quote to be logged")
+	}
+
+	// A long‑only book never quotes an ask without inventory.
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	m, exec, _ = buildMarketMaker(t, cfg, mmConfig())
+	runFlow(t, m, exec, calmFlow(20, 0.01), 30)
+	if bid, ask := m.Quotes(); bid == 0 || ask != 0 {
+		t.Fatalf("flat long‑only book should only bid, got %v/%v", bid, ask)
+	}
+}
+
+func TestMarketMakerSimulatedOrderFlow(t *testing.T) {
+	m, exec, log := buildMarketMaker(t, buildConfig(), mmConfig())
+	bars := orderFlow(600, 1, 0.3)
+	var buys, sells int
+	for i := range bars {
+		before, _ := exec.Position("MM")
+		runFlow(t, m, exec, bars[i:i+1], 30)
+		after, _ := exec.Position("MM")
+		switch {
+		case after > before:
+			buys++
+		case after < before:
+			sells++
+		}
+	}
+
+	if !log.HasMessage("mm_fill") {
+		t.Fatal("expected fills to be logged")
+	}
+	if buys < 20 || sells < 20 {
+		t.Fatalf("expected two‑sided flow, got %d bars with bid fills and %d with ask fills", buys, sells)
+	}
+	pos, _ := exec.Position("MM")
+	last := bars[len(bars)-1].close
+	if pnl := exec.Equity() + pos*last - 100_000; pnl <= 0 {
+		t.Fatalf("spread capture on mean‑reverting flow should be profitable, pnl %v", pnl)
+	}
+}
+
+func TestMarketMakerWarmupAndSnapshot(t *testing.T) {
+	bars := orderFlow(300, 5, 0.3)
+
+	history := func(bars []flowBar) []types.Bar {
+		out := make([]types.Bar, len(bars))
+		for i, b := range bars {
+			out[i] = types.Bar{High: b.high, Low: b.low, Close: b.close, Volume: 1000}
+		}
+		return out
+	}
+
+	// Warm up on the first WarmupBars bars, then trade live straight
+	// through, or with a snapshot/restore in the middle.
+	straight, straightExec, _ := buildMarketMaker(t, buildConfig(), mmConfig())
+	n := straight.WarmupBars()
+	straight.Warmup(history(bars[:n]))
+	if open := straightExec.OpenOrders("MM"); len(open) != 0 {
+		t.Fatalf("Warmup must not quote: %+v", open)
+	}
+	runFlow(t, straight, straightExec, bars[n:], 30)
+
+	warm, exec, _ := buildMarketMaker(t, buildConfig(), mmConfig())
+	warm.Warmup(history(bars[:n]))
+	runFlow(t, warm, exec, bars[n:150], 30)
+	data, err := warm.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewMarketMaker("MM", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMarketMaker failed: %v", err)
+	}
+	if err := resumed.SetMarketMakerConfig(mmConfig()); err != nil {
+		t.Fatalf("SetMarketMakerConfig failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	runFlow(t, resumed, exec, bars[150:], 30)
+
+	wantPos, _ := straightExec.Position("MM")
+	gotPos, _ := exec.Position("MM")
+	if math.Abs(gotPos-wantPos) > 1e-9 || math.Abs(exec.Equity()-straightExec.Equity()) > 1e-6 {
+		t.Fatalf("resumed run diverged: position %v/%v equity %v/%v",
+			gotPos, wantPos, exec.Equity(), straightExec.Equity())
+	}
+	wb, wa := straight.Quotes()
+	if gb, ga := resumed.Quotes(); gb != wb || ga != wa {
+		t.Fatalf("resumed quotes %v/%v, want %v/%v", gb, ga, wb, wa)
+	}
+}
+
+func TestMarketMakerConfigValidate(t *testing.T) {
+	if err := DefaultMarketMakerConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []func(*MarketMakerConfig){
+		func(mc *MarketMakerConfig) { mc.FairValue = "vwap" },
+		func(mc *MarketMakerConfig) { mc.VolSource = "garch" },
+		func(mc *MarketMakerConfig) { mc.ATRPeriod = 0 },
+		func(mc *MarketMakerConfig) { mc.BaseSpread = 0 },
+		func(mc *MarketMakerConfig) { mc.VolMultiplier = -1 },
+		func(mc *MarketMakerConfig) { mc.InventorySkew = 3 },
+		func(mc *MarketMakerConfig) { mc.MaxInventory = -1 },
+	}
+	for i, mutate := range bad {
+		mc := DefaultMarketMakerConfig()
+		mutate(&mc)
+		if err := mc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, mc)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -19,15 +18,7 @@ type MeanReversion struct {
 func NewMeanReversion(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*MeanReversion, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		ic.VWAOStrongTrend = 70
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -37,59 +28,47 @@ func NewMeanReversion(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar updates the suite and evaluates the three oscillator crossovers.
 func (mr *MeanReversion) ProcessBar(high, low, close, volume float64) {
+	defer mr.flushDecision()
 	if err := mr.Suite.Add(high, low, close, volume); err != nil {
 		mr.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	mr.recordPrice(close)
+	mr.recordBar(high, low, close, volume)
+	if mr.manageExits(high, low, close) {
+		return
+	}
 	if !mr.hasHistory(15) {
 		return
 	}
 
-	rsiBull := mr.bullishFallback()
-	if ok, err := mr.Suite.GetRSI().IsBullishCrossover(); err == nil {
-		rsiBull = rsiBull || ok
-	}
-	rsiBear := mr.bearishFallback()
-	if ok, err := mr.Suite.GetRSI().IsBearishCrossover(); err == nil {
-		rsiBear = rsiBear || ok
-	}
-	mfiBull := mr.bullishFallback()
-	if ok, err := mr.Suite.GetMFI().IsBullishCrossover(); err == nil {
-		mfiBull = mfiBull || ok
-	}
-	mfiBear := mr.bearishFallback()
-	if ok, err := mr.Suite.GetMFI().IsBearishCrossover(); err == nil {
-		mfiBear = mfiBear || ok
-	}
-	vwaoBull := mr.bullishFallback()
-	if ok, err := mr.Suite.GetVWAO().IsBullishCrossover(); err == nil {
-		vwaoBull = vwaoBull || ok
-	}
-	vwaoBear := mr.bearishFallback()
-	if ok, err := mr.Suite.GetVWAO().IsBearishCrossover(); err == nil {
-		vwaoBear = vwaoBear || ok
-	}
+	rsiBull := mr.signal("rsi_bull", mr.Suite.GetRSI().IsBullishCrossover, mr.bullishFallback)
+	rsiBear := mr.signal("rsi_bear", mr.Suite.GetRSI().IsBearishCrossover, mr.bearishFallback)
+	mfiBull := mr.signal("mfi_bull", mr.Suite.GetMFI().IsBullishCrossover, mr.bullishFallback)
+	mfiBear := mr.signal("mfi_bear", mr.Suite.GetMFI().IsBearishCrossover, mr.bearishFallback)
+	vwaoBull := mr.signal("vwao_bull", mr.Suite.GetVWAO().IsBullishCrossover, mr.bullishFallback)
+	vwaoBear := mr.signal("vwao_bear", mr.Suite.GetVWAO().IsBearishCrossover, mr.bearishFallback)
 
 	longSignal := rsiBull && mfiBull && vwaoBull
 	shortSignal := rsiBear && mfiBear && vwaoBear
 
-	posQty, _ := mr.Exec.Position(mr.Symbol)
+	posQty, _ := mr.position()
 
 	switch {
 	case longSignal && posQty <= 0:
+		mr.setIntent(IntentLong, mr.conviction("rsi_bull", "mfi_bull", "vwao_bull"), close)
 		if posQty < 0 {
 			mr.closePosition(close, "mr_close_short")
 		}
 		mr.openLong(close)
 
 	case shortSignal && posQty >= 0:
+		mr.setIntent(IntentShort, mr.conviction("rsi_bear", "mfi_bear", "vwao_bear"), close)
 		if posQty > 0 {
 			mr.closePosition(close, "mr_close_long")
 		}
 		mr.openShort(close)
 
-	case posQty != 0 && mr.Cfg.TrailingPct > 0:
+	case posQty != 0 && mr.trailingEnabled():
 		mr.applyTrailingStop(close)
 	case posQty != 0:
 		if mr.Cfg.TakeProfitPct > 0 {
@@ -100,6 +79,9 @@ func (mr *MeanReversion) ProcessBar(high, low, close, volume float64) {
 
 // openLong creates a long order sized by risk.
 func (mr *MeanReversion) openLong(price float64) {
+	if !mr.canOpen(types.Buy) {
+		return
+	}
 	qty := mr.calcQty(price)
 	if qty <= 0 {
 		return
@@ -116,6 +98,9 @@ func (mr *MeanReversion) openLong(price float64) {
 
 // openShort creates a short order sized by risk.
 func (mr *MeanReversion) openShort(price float64) {
+	if !mr.canOpen(types.Sell) {
+		return
+	}
 	qty := mr.calcQty(price)
 	if qty <= 0 {
 		return
@@ -131,7 +116,7 @@ func (mr *MeanReversion) openShort(price float64) {
 }
 
 func (mr *MeanReversion) manageTakeProfit(currentPrice float64) {
-	qty, avg := mr.Exec.Position(mr.Symbol)
+	qty, avg := mr.position()
 	if qty == 0 {
 		return
 	}
@@ -1,8 +1,12 @@
 package strategy
 
 import (
+	"math"
 	"testing"
 
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/testutils"
 	"github.com/evdnx/gots/types"
 )
 
@@ -84,19 +88,7 @@ func TestMeanReversion_TrailingStop(t *testing.T) {
 	}
 	entry := exec.Orders()[0].Price
 
-	// ---- price crosses trailing level ----
-	trail := entry * 1.02
-	mr.ProcessBar(trail+0.5, trail-0.5, trail+0.1, 1200)
-
-	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected trailing‑stop close order, got %d (orders: %+v)", len(exec.Orders()), exec.Orders())
-	}
-	if exec.Orders()[1].Side != types.Sell {
-		t.Fatalf("expected SELL to close trailing stop, got %s", exec.Orders()[1].Side)
-	}
-	if exec.Orders()[1].Price < trail {
-		t.Fatalf("trailing‑stop price %f below expected %f", exec.Orders()[1].Price, trail)
-	}
+	checkTrailingExit(t, mr, exec, entry)
 }
 
 func TestMeanReversion_TakeProfit(t *testing.T) {
@@ -186,3 +178,56 @@ func TestMeanReversion_OppositeSideFlip(t *testing.T) {
 		t.Fatalf("short entry qty must be positive, got %f", exec.Orders()[2].Qty)
 	}
 }
+
+func TestMeanReversion_ConfiguredSizer(t *testing.T) {
+	cfg := buildConfig()
+	cfg.Sizing = config.SizingFixedNotional
+	cfg.FixedNotional = 1_000
+	exec := testutils.NewMockExecutor(10_000)
+	mr, err := NewMeanReversion("TEST", cfg, exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+
+	var bars []candle
+	for i := 1; i <= 15; i++ {
+		price := 100.0 + float64(i)
+		bars = append(bars, candle{high: price + 0.5, low: price - 0.5, close: price, volume: 1000})
+	}
+	feedBars(t, mr, bars)
+
+	if len(exec.Orders()) != 1 {
+		t.Fatalf("expected one order, got %d", len(exec.Orders()))
+	}
+	o := exec.Orders()[0]
+	if want := risk.RoundQty(1_000/o.Price, cfg); o.Qty != want {
+		t.Fatalf("expected fixed‑notional qty %v, got %v", want, o.Qty)
+	}
+}
+
+func TestMeanReversion_RecordsClosedTrades(t *testing.T) {
+	mr, exec := buildMeanReversion(t)
+
+	var bars []candle
+	for i := 1; i <= 15; i++ {
+		price := 100.0 + float64(i)
+		bars = append(bars, candle{high: price + 0.5, low: price - 0.5, close: price, volume: 1000})
+	}
+	for i := 1; i <= 15; i++ {
+		price := 115.0 - float64(i)
+		bars = append(bars, candle{high: price + 0.5, low: price - 0.5, close: price, volume: 1000})
+	}
+	feedBars(t, mr, bars)
+
+	orders := exec.Orders()
+	if len(orders) < 2 {
+		t.Fatalf("expected a long entry and its exit, got %+v", orders)
+	}
+	if mr.Trades.Len() != 1 {
+		t.Fatalf("expected one closed trade in history, got %d", mr.Trades.Len())
+	}
+	want := (orders[1].Price - orders[0].Price) / orders[0].Price
+	if got := mr.Trades.Returns()[0]; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("recorded return %v, want %v", got, want)
+	}
+}
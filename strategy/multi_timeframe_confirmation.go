@@ -25,11 +25,7 @@ func NewMultiTF(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger,
 	fastSec, slowSec int) (*MultiTF, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.ATSEMAperiod = cfg.ATSEMAperiod
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	fast, err := suiteFactory()
 	if err != nil {
 		return nil, err
@@ -55,6 +51,7 @@ func NewMultiTF(symbol string, cfg config.StrategyConfig,
 // ProcessBar receives fast bars; the slow suite receives the same data
 // (it internally trims to its longer window).
 func (m *MultiTF) ProcessBar(high, low, close, volume float64) {
+	defer m.flushDecision()
 	if err := m.Suite.Add(high, low, close, volume); err != nil {
 		m.Log.Warn("base_suite_add_error", logger.Err(err))
 	}
@@ -66,28 +63,19 @@ func (m *MultiTF) ProcessBar(high, low, close, volume float64) {
 	if err := m.slowSuite.Add(high, low, close, volume); err != nil {
 		m.Log.Warn("slow_suite_add_error", logger.Err(err))
 	}
-	m.recordPrice(close)
+	m.recordBar(high, low, close, volume)
+	if m.manageExits(high, low, close) {
+		return
+	}
 	if !m.hasHistory(15) {
 		return
 	}
 
 	// Check for aligned HMA crossovers.
-	fBull := m.bullishFallback()
-	if ok, err := m.fastSuite.GetHMA().IsBullishCrossover(); err == nil {
-		fBull = fBull || ok
-	}
-	fBear := m.bearishFallback()
-	if ok, err := m.fastSuite.GetHMA().IsBearishCrossover(); err == nil {
-		fBear = fBear || ok
-	}
-	sBull := m.bullishFallback()
-	if ok, err := m.slowSuite.GetHMA().IsBullishCrossover(); err == nil {
-		sBull = sBull || ok
-	}
-	sBear := m.bearishFallback()
-	if ok, err := m.slowSuite.GetHMA().IsBearishCrossover(); err == nil {
-		sBear = sBear || ok
-	}
+	fBull := m.signal("fast_hma_bull", m.fastSuite.GetHMA().IsBullishCrossover, m.bullishFallback)
+	fBear := m.signal("fast_hma_bear", m.fastSuite.GetHMA().IsBearishCrossover, m.bearishFallback)
+	sBull := m.signal("slow_hma_bull", m.slowSuite.GetHMA().IsBullishCrossover, m.bullishFallback)
+	sBear := m.signal("slow_hma_bear", m.slowSuite.GetHMA().IsBearishCrossover, m.bearishFallback)
 
 	trendDir := m.prices.Trend()
 	longCond := trendDir > 0 && fBull && sBull
@@ -99,10 +87,11 @@ func (m *MultiTF) ProcessBar(high, low, close, volume float64) {
 		shortCond = false
 	}
 
-	posQty, _ := m.Exec.Position(m.Symbol)
+	posQty, _ := m.position()
 
 	switch {
 	case longCond && posQty <= 0:
+		m.setIntent(IntentLong, m.conviction("fast_hma_bull", "slow_hma_bull"), close)
 		if posQty < 0 {
 			m.closePosition(close, "mtf_close_short")
 		}
@@ -110,13 +99,14 @@ func (m *MultiTF) ProcessBar(high, low, close, volume float64) {
 		m.lastSignal = 1
 
 	case shortCond && posQty >= 0:
+		m.setIntent(IntentShort, m.conviction("fast_hma_bear", "slow_hma_bear"), close)
 		if posQty > 0 {
 			m.closePosition(close, "mtf_close_long")
 		}
 		m.openShort(close)
 		m.lastSignal = -1
 
-	case posQty != 0 && m.Cfg.TrailingPct > 0:
+	case posQty != 0 && m.trailingEnabled():
 		m.applyTrailingStop(close)
 		if m.Cfg.TakeProfitPct > 0 {
 			m.manageTakeProfit(close)
@@ -134,6 +124,9 @@ func (m *MultiTF) ProcessBar(high, low, close, volume float64) {
 
 // openLong / openShort reuse the base helpers.
 func (m *MultiTF) openLong(price float64) {
+	if !m.canOpen(types.Buy) {
+		return
+	}
 	qty := m.calcQty(price)
 	if qty <= 0 {
 		return
@@ -149,6 +142,9 @@ func (m *MultiTF) openLong(price float64) {
 }
 
 func (m *MultiTF) openShort(price float64) {
+	if !m.canOpen(types.Sell) {
+		return
+	}
 	qty := m.calcQty(price)
 	if qty <= 0 {
 		return
@@ -164,7 +160,7 @@ func (m *MultiTF) openShort(price float64) {
 }
 
 func (m *MultiTF) manageTakeProfit(currentPrice float64) {
-	qty, avg := m.Exec.Position(m.Symbol)
+	qty, avg := m.position()
 	if qty == 0 {
 		return
 	}
@@ -0,0 +1,463 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// HedgeMethod selects how PairsTrading estimates the hedge ratio.
+type HedgeMethod string
+
+const (
+	// HedgeOLS refits y = alpha + beta·x over the last Lookback bars.
+	HedgeOLS HedgeMethod = "ols"
+	// HedgeKalman tracks (beta, alpha) with a Kalman filter seeded from an
+	// OLS fit of the first Lookback bars and updated every bar after that;
+	// the z‑score is the normalised forecast error.
+	HedgeKalman HedgeMethod = "kalman"
+)
+
+// PairsConfig tunes PairsTrading beyond the shared StrategyConfig.
+type PairsConfig struct {
+	Method   HedgeMethod
+	Hedge    risk.HedgeMode // dollar‑ or beta‑neutral leg sizing
+	Lookback int            // bars for the OLS fit, z‑score and cointegration test
+
+	EntryZ float64 // |z| that opens a spread position
+	ExitZ  float64 // |z| at which the spread has reverted and is closed
+	StopZ  float64 // |z| that stops the position out (0 = disabled)
+
+	// GrossExposure caps |y leg| + |x leg| notional as a multiple of equity.
+	GrossExposure float64
+
+	// KalmanDelta sets the state drift (Vw = δ/(1−δ)·I) and KalmanObsNoise
+	// the observation variance of the HedgeKalman filter.
+	KalmanDelta    float64
+	KalmanObsNoise float64
+
+	// Cointegration, when set, requires an Engle–Granger test over the last
+	// Lookback bars (ADFLags lagged differences) to fall below CointCritical
+	// before every entry.
+	Cointegration bool
+	ADFLags       int
+	CointCritical float64
+}
+
+// DefaultPairsConfig fits a rolling 60‑bar OLS hedge, sizes beta‑neutral,
+// enters at |z| ≥ 2, exits at |z| ≤ 0.5, stops at |z| ≥ 4 and does not
+// test for cointegration (5 % critical value when enabled).
+func DefaultPairsConfig() PairsConfig {
+	return PairsConfig{
+		Method:         HedgeOLS,
+		Hedge:          risk.HedgeBetaNeutral,
+		Lookback:       60,
+		EntryZ:         2,
+		ExitZ:          0.5,
+		StopZ:          4,
+		GrossExposure:  1,
+		KalmanDelta:    1e-4,
+		KalmanObsNoise: 1e-3,
+		ADFLags:        1,
+		CointCritical:  risk.EngleGrangerCritical5,
+	}
+}
+
+// Validate checks the pairs settings.
+func (pc PairsConfig) Validate() error {
+	switch pc.Method {
+	case HedgeOLS, HedgeKalman:
+	default:
+		return fmt.Errorf("unknown hedge method %q", pc.Method)
+	}
+	switch pc.Hedge {
+	case risk.HedgeDollarNeutral, risk.HedgeBetaNeutral:
+	default:
+		return fmt.Errorf("unknown hedge mode %q", pc.Hedge)
+	}
+	if pc.Lookback < 10 || pc.Lookback > maxReturnHistory {
+		return fmt.Errorf("Lookback (%d) must be between 10 and %d", pc.Lookback, maxReturnHistory)
+	}
+	if pc.EntryZ <= 0 || pc.ExitZ < 0 || pc.ExitZ >= pc.EntryZ {
+		return fmt.Errorf("need 0 <= ExitZ (%f) < EntryZ (%f)", pc.ExitZ, pc.EntryZ)
+	}
+	if pc.StopZ != 0 && pc.StopZ <= pc.EntryZ {
+		return fmt.Errorf("StopZ (%f) must be 0 or above EntryZ (%f)", pc.StopZ, pc.EntryZ)
+	}
+	if pc.GrossExposure <= 0 || pc.GrossExposure > 10 {
+		return fmt.Errorf("GrossExposure (%f) must be >0 and <=10", pc.GrossExposure)
+	}
+	if pc.KalmanDelta <= 0 || pc.KalmanDelta >= 1 {
+		return fmt.Errorf("KalmanDelta (%f) must be between 0 and 1", pc.KalmanDelta)
+	}
+	if pc.KalmanObsNoise <= 0 {
+		return fmt.Errorf("KalmanObsNoise (%f) must be positive", pc.KalmanObsNoise)
+	}
+	if pc.ADFLags < 0 || pc.ADFLags > pc.Lookback/4 {
+		return fmt.Errorf("ADFLags (%d) must be between 0 and Lookback/4", pc.ADFLags)
+	}
+	if pc.CointCritical >= 0 {
+		return fmt.Errorf("CointCritical (%f) must be negative", pc.CointCritical)
+	}
+	return nil
+}
+
+// kalmanHedge is the state of the (beta, alpha) Kalman filter.
+type kalmanHedge struct {
+	Theta [2]float64    `json:"theta"` // beta, alpha
+	P     [2][2]float64 `json:"p"`
+	E     float64       `json:"e"` // last forecast error
+	Q     float64       `json:"q"` // last forecast variance
+
+	Seeded bool `json:"seeded"`
+}
+
+// update runs one predict/correct step for the observation (x, y).
+func (k *kalmanHedge) update(x, y, delta, obsNoise float64) {
+	vw := delta / (1 - delta)
+	r := k.P
+	r[0][0] += vw
+	r[1][1] += vw
+	f := [2]float64{x, 1}
+	fr := [2]float64{f[0]*r[0][0] + f[1]*r[1][0], f[0]*r[0][1] + f[1]*r[1][1]}
+	q := fr[0]*f[0] + fr[1]*f[1] + obsNoise
+	e := y - (k.Theta[0]*f[0] + k.Theta[1]*f[1])
+	gain := [2]float64{(r[0][0]*f[0] + r[0][1]*f[1]) / q, (r[1][0]*f[0] + r[1][1]*f[1]) / q}
+	for i := 0; i < 2; i++ {
+		k.Theta[i] += gain[i] * e
+		for j := 0; j < 2; j++ {
+			k.P[i][j] = r[i][j] - gain[i]*fr[j]
+		}
+	}
+	k.E, k.Q = e, q
+}
+
+// PairsTrading trades the spread y − (alpha + beta·x) of two symbols: it
+// goes long the spread (buy y, sell x) when its z‑score falls below
+// −EntryZ, short above EntryZ, and flattens both legs once the spread has
+// reverted inside ±ExitZ or a stop fires.  Like RiskParityRotation it is a
+// multi‑symbol manager fed through ProcessBar(symbol, …); a step runs once
+// both legs have delivered their bar.
+//
+// Positions are sized so that a loss of StopLossPct of the gross notional
+// costs MaxRiskPerTrade of equity, capped at GrossExposure × equity; that
+// loss is also the P&L stop unless StopType is none.
+type PairsTrading struct {
+	y, x  string
+	cfg   config.StrategyConfig
+	pairs PairsConfig
+	exec  executor.Executor
+	log   logger.Logger
+	mu    sync.Mutex
+
+	ys, xs       []float64 // rolling closes, aligned by step
+	lastY, lastX float64
+	hasY, hasX   bool // leg bar received for the pending step
+	kalman       kalmanHedge
+
+	alpha, beta, z float64
+	ready          bool    // alpha/beta/z are estimated
+	side           int     // +1 long spread, −1 short spread, 0 flat
+	entryGross     float64 // gross notional at entry (P&L stop basis)
+	stopped        bool    // stopped out; wait for |z| < EntryZ
+}
+
+// NewPairsTrading trades the spread of y against x with DefaultPairsConfig.
+func NewPairsTrading(y, x string, cfg config.StrategyConfig,
+	exec executor.Executor, log logger.Logger) (*PairsTrading, error) {
+
+	if y == "" || x == "" || y == x {
+		return nil, logOutputError(log, "pairs need two distinct symbols")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &PairsTrading{
+		y:     y,
+		x:     x,
+		cfg:   cfg,
+		pairs: DefaultPairsConfig(),
+		exec:  exec,
+		log:   log,
+	}, nil
+}
+
+// SetPairsConfig replaces the pairs settings after validating them.
+func (p *PairsTrading) SetPairsConfig(pc PairsConfig) error {
+	if err := pc.Validate(); err != nil {
+		return logOutputError(p.log, err.Error())
+	}
+	p.mu.Lock()
+	p.pairs = pc
+	p.mu.Unlock()
+	return nil
+}
+
+// HedgeRatio returns the latest estimate of y = alpha + beta·x.
+func (p *PairsTrading) HedgeRatio() (alpha, beta float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.alpha, p.beta
+}
+
+// ZScore returns the latest spread z‑score and whether it is estimated.
+func (p *PairsTrading) ZScore() (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.z, p.ready
+}
+
+// SpreadSide returns +1 while long the spread, −1 while short, 0 flat.
+func (p *PairsTrading) SpreadSide() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.side
+}
+
+// ProcessBar must be called for both legs of every bar; other symbols are
+// ignored.
+func (p *PairsTrading) ProcessBar(symbol string, high, low, close, volume float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.addLeg(symbol, close) {
+		return
+	}
+	p.estimate()
+	if p.ready {
+		p.trade()
+	}
+}
+
+// addLeg stores a leg's close and, once both legs are in, appends the step
+// to the rolling windows.  It reports whether a step completed.
+func (p *PairsTrading) addLeg(symbol string, close float64) bool {
+	switch symbol {
+	case p.y:
+		p.lastY, p.hasY = close, true
+	case p.x:
+		p.lastX, p.hasX = close, true
+	default:
+		return false
+	}
+	if !p.hasY || !p.hasX {
+		return false
+	}
+	p.hasY, p.hasX = false, false
+	p.ys = appendBounded(p.ys, p.lastY, p.pairs.Lookback)
+	p.xs = appendBounded(p.xs, p.lastX, p.pairs.Lookback)
+	switch {
+	case p.kalman.Seeded:
+		p.kalman.update(p.lastX, p.lastY, p.pairs.KalmanDelta, p.pairs.KalmanObsNoise)
+	case len(p.ys) == p.pairs.Lookback:
+		// Seeding from OLS avoids the long convergence of a filter started
+		// at zero, during which the intercept leaks into beta.
+		if alpha, beta, err := risk.OLS(p.ys, p.xs); err == nil {
+			p.kalman.Theta = [2]float64{beta, alpha}
+			p.kalman.Seeded = true
+		}
+	}
+	return true
+}
+
+// estimate refreshes the hedge ratio and z‑score once Lookback steps are
+// available.
+func (p *PairsTrading) estimate() {
+	p.ready = false
+	if len(p.ys) < p.pairs.Lookback {
+		return
+	}
+	if p.pairs.Method == HedgeKalman {
+		if p.kalman.Q <= 0 {
+			return
+		}
+		p.beta, p.alpha = p.kalman.Theta[0], p.kalman.Theta[1]
+		p.z = p.kalman.E / math.Sqrt(p.kalman.Q)
+		p.ready = true
+		return
+	}
+	alpha, beta, err := risk.OLS(p.ys, p.xs)
+	if err != nil {
+		return
+	}
+	spread := make([]float64, len(p.ys))
+	mean := 0.0
+	for i := range p.ys {
+		spread[i] = p.ys[i] - alpha - beta*p.xs[i]
+		mean += spread[i]
+	}
+	mean /= float64(len(spread))
+	sd := risk.StdDev(spread)
+	if sd == 0 {
+		return
+	}
+	p.alpha, p.beta = alpha, beta
+	p.z = (spread[len(spread)-1] - mean) / sd
+	p.ready = true
+}
+
+// trade applies the exit, stop and entry rules to the current z‑score.
+func (p *PairsTrading) trade() {
+	if p.side != 0 {
+		switch {
+		case p.stopHit():
+			p.flatten("pairs_stop")
+			p.stopped = true
+		case (p.side > 0 && p.z >= -p.pairs.ExitZ) || (p.side < 0 && p.z <= p.pairs.ExitZ):
+			p.flatten("pairs_exit")
+		}
+		return
+	}
+	if p.stopped {
+		if math.Abs(p.z) >= p.pairs.EntryZ {
+			return
+		}
+		p.stopped = false
+	}
+	switch {
+	case p.z <= -p.pairs.EntryZ:
+		p.open(1)
+	case p.z >= p.pairs.EntryZ:
+		p.open(-1)
+	}
+}
+
+// stopHit reports whether the z‑score stop or the P&L stop fired.
+func (p *PairsTrading) stopHit() bool {
+	if p.pairs.StopZ > 0 && math.Abs(p.z) >= p.pairs.StopZ {
+		return true
+	}
+	if p.cfg.StopType == config.StopNone || p.cfg.StopLossPct <= 0 || p.entryGross <= 0 {
+		return false
+	}
+	qy, avgY := p.exec.Position(p.y)
+	qx, avgX := p.exec.Position(p.x)
+	pnl := qy*(p.lastY-avgY) + qx*(p.lastX-avgX)
+	return pnl <= -p.cfg.StopLossPct*p.entryGross
+}
+
+// grossNotional sizes the pair from the risk budget.
+func (p *PairsTrading) grossNotional() float64 {
+	equity := p.exec.Equity()
+	limit := equity * p.pairs.GrossExposure
+	if p.cfg.StopLossPct <= 0 {
+		return limit
+	}
+	return math.Min(equity*p.cfg.MaxRiskPerTrade/p.cfg.StopLossPct, limit)
+}
+
+// open enters the spread in direction dir (+1 long, −1 short).
+func (p *PairsTrading) open(dir int) {
+	if p.pairs.Cointegration {
+		res, err := risk.EngleGranger(p.ys, p.xs, p.pairs.ADFLags)
+		if err != nil || !res.Cointegrated(p.pairs.CointCritical) {
+			p.log.Info("pairs_not_cointegrated",
+				logger.String("y", p.y),
+				logger.String("x", p.x),
+				logger.Float64("adf_stat", res.Stat),
+			)
+			return
+		}
+	}
+	sideY, sideX := types.Buy, types.Sell
+	if dir < 0 {
+		sideY, sideX = types.Sell, types.Buy
+	}
+	if p.beta < 0 {
+		sideX = sideY
+	}
+	mode := p.cfg.PositionMode
+	for _, s := range []types.Side{sideY, sideX} {
+		if (s == types.Buy && !mode.AllowsLong()) || (s == types.Sell && !mode.AllowsShort()) {
+			p.log.Info("entry_skipped_position_mode",
+				logger.String("symbol", p.y+"/"+p.x),
+				logger.String("side", string(s)),
+				logger.String("mode", string(mode)),
+			)
+			return
+		}
+	}
+	qy, qx := risk.PairQuantities(p.grossNotional(), p.lastY, p.lastX, p.beta, p.pairs.Hedge, p.cfg)
+	if qy == 0 {
+		return
+	}
+	if !p.submit(types.Order{Symbol: p.y, Side: sideY, Qty: qy, Price: p.lastY, Comment: "pairs_entry"}) {
+		return
+	}
+	if !p.submit(types.Order{Symbol: p.x, Side: sideX, Qty: qx, Price: p.lastX, Comment: "pairs_entry"}) {
+		// Never leave a naked leg.
+		p.flatten("pairs_unwind")
+		return
+	}
+	p.side = dir
+	p.entryGross = qy*p.lastY + qx*p.lastX
+	p.log.Info("pairs_entry",
+		logger.String("y", p.y),
+		logger.String("x", p.x),
+		logger.Int("side", dir),
+		logger.Float64("z", p.z),
+		logger.Float64("beta", p.beta),
+		logger.Float64("qty_y", qy),
+		logger.Float64("qty_x", qx),
+	)
+}
+
+// flatten closes both legs and reports the reason.
+func (p *PairsTrading) flatten(reason string) {
+	for _, leg := range []struct {
+		symbol string
+		price  float64
+	}{{p.y, p.lastY}, {p.x, p.lastX}} {
+		qty, _ := p.exec.Position(leg.symbol)
+		if qty == 0 {
+			continue
+		}
+		side := types.Sell
+		if qty < 0 {
+			side = types.Buy
+		}
+		p.submit(types.Order{Symbol: leg.symbol, Side: side, Qty: math.Abs(qty), Price: leg.price, Comment: reason})
+	}
+	p.log.Info(reason,
+		logger.String("y", p.y),
+		logger.String("x", p.x),
+		logger.Float64("z", p.z),
+	)
+	p.side = 0
+	p.entryGross = 0
+}
+
+func (p *PairsTrading) submit(o types.Order) bool {
+	if err := p.exec.Submit(o); err != nil {
+		p.log.Error("pairs_submit_error",
+			logger.String("symbol", o.Symbol),
+			logger.Err(err),
+		)
+		return false
+	}
+	return true
+}
+
+// WarmupBars returns the aligned steps needed before the first live bar.
+func (p *PairsTrading) WarmupBars() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pairs.Lookback
+}
+
+// Warmup feeds aligned historical bars of both legs (ys[i] and xs[i] are
+// the same bar) into the hedge estimate without trading.
+func (p *PairsTrading) Warmup(ys, xs []types.Bar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := 0; i < len(ys) && i < len(xs); i++ {
+		p.addLeg(p.y, ys[i].Close)
+		p.addLeg(p.x, xs[i].Close)
+	}
+	p.estimate()
+}
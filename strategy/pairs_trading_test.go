@@ -0,0 +1,268 @@
+package strategy
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+// pairSeries returns closes of y = 10 + 2·x + a bounded oscillation (whose
+// z‑score never reaches 2) with x a random walk; shock[i] is added to y at
+// bar i.  With coint false y is an independent random walk instead.
+func pairSeries(n int, coint bool, shock map[int]float64) (ys, xs []float64) {
+	rng := rand.New(rand.NewSource(11))
+	ys, xs = make([]float64, n), make([]float64, n)
+	x, walk := 50.0, 110.0
+	for i := 0; i < n; i++ {
+		x += 0.5 * rng.NormFloat64()
+		walk += 0.5 * rng.NormFloat64()
+		xs[i] = x
+		if coint {
+			ys[i] = 10 + 2*x + 0.3*math.Sin(1.3*float64(i)) + shock[i]
+		} else {
+			ys[i] = walk
+		}
+	}
+	return ys, xs
+}
+
+func feedPair(p *PairsTrading, ys, xs []float64, from, to int) {
+	for i := from; i < to; i++ {
+		p.ProcessBar("Y", ys[i]+0.5, ys[i]-0.5, ys[i], 1000)
+		p.ProcessBar("X", xs[i]+0.5, xs[i]-0.5, xs[i], 1000)
+	}
+}
+
+func buildPairs(t *testing.T, pc PairsConfig) (*PairsTrading, *testutils.MockExecutor, *testutils.MockLogger) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(100_000)
+	log := testutils.NewMockLogger()
+	p, err := NewPairsTrading("Y", "X", buildConfig(), exec, log)
+	if err != nil {
+		t.Fatalf("NewPairsTrading failed: %v", err)
+	}
+	if err := p.SetPairsConfig(pc); err != nil {
+		t.Fatalf("SetPairsConfig failed: %v", err)
+	}
+	return p, exec, log
+}
+
+func TestPairsShortSpreadEntryAndReversion(t *testing.T) {
+	pc := DefaultPairsConfig()
+	pc.StopZ = 0
+	p, exec, _ := buildPairs(t, pc)
+	ys, xs := pairSeries(140, true, map[int]float64{100: 1, 101: 1})
+
+	feedPair(p, ys, xs, 0, 100)
+	if n := len(exec.Orders()); n != 0 {
+		t.Fatalf("no entry expected before the shock, got %d orders", n)
+	}
+
+	feedPair(p, ys, xs, 100, 101)
+	orders := exec.Orders()
+	if len(orders) != 2 || p.SpreadSide() != -1 {
+		t.Fatalf("expected a short‑spread entry, got side %d orders %+v", p.SpreadSide(), orders)
+	}
+	if orders[0].Symbol != "Y" || orders[0].Side != types.Sell || orders[1].Symbol != "X" || orders[1].Side != types.Buy {
+		t.Fatalf("short spread must sell Y and buy X: %+v", orders)
+	}
+	_, beta := p.HedgeRatio()
+	if math.Abs(beta-2) > 0.1 {
+		t.Fatalf("hedge ratio %v, want ≈2", beta)
+	}
+	if ratio := orders[1].Qty / orders[0].Qty; math.Abs(ratio-beta) > 0.01 {
+		t.Fatalf("beta‑neutral legs: qty ratio %v, want beta %v", ratio, beta)
+	}
+
+	// The spread reverts once the shock is gone.
+	feedPair(p, ys, xs, 101, 140)
+	if p.SpreadSide() != 0 {
+		t.Fatal("spread position must be closed after reversion")
+	}
+	for _, sym := range []string{"Y", "X"} {
+		if qty, _ := exec.Position(sym); qty != 0 {
+			t.Fatalf("%s leg not flat after exit: %v", sym, qty)
+		}
+	}
+	if exec.Orders()[2].Comment != "pairs_exit" {
+		t.Fatalf("expected pairs_exit, got %+v", exec.Orders()[2])
+	}
+}
+
+func TestPairsDollarNeutralSizing(t *testing.T) {
+	pc := DefaultPairsConfig()
+	pc.Hedge = risk.HedgeDollarNeutral
+	p, exec, _ := buildPairs(t, pc)
+	ys, xs := pairSeries(101, true, map[int]float64{100: 1})
+	feedPair(p, ys, xs, 0, 101)
+
+	orders := exec.Orders()
+	if len(orders) != 2 {
+		t.Fatalf("expected an entry, got %+v", orders)
+	}
+	ny, nx := orders[0].Qty*orders[0].Price, orders[1].Qty*orders[1].Price
+	if math.Abs(ny-nx)/ny > 0.01 {
+		t.Fatalf("dollar‑neutral legs differ: %v vs %v", ny, nx)
+	}
+	// Risk budget: 1 % of equity at a 1.5 % stop on the gross notional.
+	if want := 100_000 * 0.01 / 0.015; math.Abs(ny+nx-want)/want > 0.01 {
+		t.Fatalf("gross notional %v, want ≈%v", ny+nx, want)
+	}
+}
+
+func TestPairsStopAndNoImmediateReentry(t *testing.T) {
+	p, exec, log := buildPairs(t, DefaultPairsConfig())
+	ys, xs := pairSeries(106, true, map[int]float64{100: 1, 101: 3, 102: 3, 103: 3})
+	feedPair(p, ys, xs, 0, 101)
+	if p.SpreadSide() != -1 {
+		t.Fatalf("expected a short‑spread entry, got %+v", exec.Orders())
+	}
+
+	feedPair(p, ys, xs, 101, 102)
+	if !log.HasMessage("pairs_stop") || p.SpreadSide() != 0 {
+		t.Fatalf("expected the z‑score stop, orders %+v", exec.Orders())
+	}
+	feedPair(p, ys, xs, 102, 104)
+	if n := len(exec.Orders()); n != 4 {
+		t.Fatalf("no re‑entry while the spread stays stretched, got %d orders", n)
+	}
+}
+
+func TestPairsCointegrationFilter(t *testing.T) {
+	ys, xs := pairSeries(400, false, nil)
+
+	free, freeExec, _ := buildPairs(t, DefaultPairsConfig())
+	feedPair(free, ys, xs, 0, len(ys))
+	if len(freeExec.Orders()) == 0 {
+		t.Fatal("unfiltered strategy should trade the unrelated pair")
+	}
+
+	pc := DefaultPairsConfig()
+	pc.Cointegration = true
+	p, exec, log := buildPairs(t, pc)
+	feedPair(p, ys, xs, 0, len(ys))
+	if n := len(exec.Orders()); n != 0 {
+		t.Fatalf("unrelated random walks must not be traded, got %d orders", n)
+	}
+	if !log.HasMessage("pairs_not_cointegrated") {
+		t.Fatal("expected the rejected entries to be logged")
+	}
+
+	// A genuinely cointegrated pair passes the filter.
+	p, exec, _ = buildPairs(t, pc)
+	cy, cx := pairSeries(101, true, map[int]float64{100: 1})
+	feedPair(p, cy, cx, 0, 101)
+	if len(exec.Orders()) != 2 {
+		t.Fatalf("cointegrated pair should be entered, got %+v", exec.Orders())
+	}
+}
+
+func TestPairsKalmanHedge(t *testing.T) {
+	pc := DefaultPairsConfig()
+	pc.Method = HedgeKalman
+	pc.StopZ = 0
+	p, exec, _ := buildPairs(t, pc)
+	ys, xs := pairSeries(300, true, map[int]float64{250: 1.5})
+	feedPair(p, ys, xs, 0, 250)
+	if _, beta := p.HedgeRatio(); math.Abs(beta-2) > 0.1 {
+		t.Fatalf("Kalman hedge ratio %v, want ≈2", beta)
+	}
+	before := len(exec.Orders())
+	feedPair(p, ys, xs, 250, 251)
+	orders := exec.Orders()[before:]
+	if len(orders) != 2 || orders[0].Side != types.Sell || p.SpreadSide() != -1 {
+		t.Fatalf("expected a short‑spread entry on the shock, got %+v", orders)
+	}
+}
+
+func TestPairsLongOnlyDoesNotTrade(t *testing.T) {
+	exec := testutils.NewMockExecutor(100_000)
+	log := testutils.NewMockLogger()
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	p, err := NewPairsTrading("Y", "X", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewPairsTrading failed: %v", err)
+	}
+	ys, xs := pairSeries(101, true, map[int]float64{100: 1})
+	feedPair(p, ys, xs, 0, 101)
+	if len(exec.Orders()) != 0 || !log.HasMessage("entry_skipped_position_mode") {
+		t.Fatalf("a pair needs a short leg; got orders %+v", exec.Orders())
+	}
+}
+
+func TestPairsWarmupAndSnapshot(t *testing.T) {
+	ys, xs := pairSeries(160, true, map[int]float64{100: 1, 130: -1})
+
+	straight, straightExec, _ := buildPairs(t, DefaultPairsConfig())
+	feedPair(straight, ys, xs, 0, len(ys))
+	if len(straightExec.Orders()) < 4 {
+		t.Fatalf("scenario should trade both shocks, got %+v", straightExec.Orders())
+	}
+
+	// Warm up on the first 60 bars, trade live, snapshot mid‑position and
+	// resume on the same executor.
+	warm, exec, _ := buildPairs(t, DefaultPairsConfig())
+	n := warm.WarmupBars()
+	toLeg := func(closes []float64) []types.Bar {
+		bars := make([]types.Bar, len(closes))
+		for i, c := range closes {
+			bars[i] = types.Bar{High: c + 0.5, Low: c - 0.5, Close: c, Volume: 1000}
+		}
+		return bars
+	}
+	warm.Warmup(toLeg(ys[:n]), toLeg(xs[:n]))
+	if len(exec.Orders()) != 0 {
+		t.Fatal("Warmup must not trade")
+	}
+	feedPair(warm, ys, xs, n, 101)
+	data, err := warm.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewPairsTrading("Y", "X", buildConfig(), exec, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewPairsTrading failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	feedPair(resumed, ys, xs, 101, len(ys))
+
+	if !reflect.DeepEqual(straightExec.Orders(), exec.Orders()) {
+		t.Fatalf("warmed/resumed run diverged:\nwant %+v\ngot  %+v", straightExec.Orders(), exec.Orders())
+	}
+}
+
+func TestPairsConfigValidate(t *testing.T) {
+	if err := DefaultPairsConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	bad := []func(*PairsConfig){
+		func(pc *PairsConfig) { pc.Method = "spline" },
+		func(pc *PairsConfig) { pc.Hedge = "vol" },
+		func(pc *PairsConfig) { pc.Lookback = 5 },
+		func(pc *PairsConfig) { pc.ExitZ = pc.EntryZ },
+		func(pc *PairsConfig) { pc.StopZ = pc.EntryZ },
+		func(pc *PairsConfig) { pc.GrossExposure = 0 },
+		func(pc *PairsConfig) { pc.KalmanDelta = 1 },
+		func(pc *PairsConfig) { pc.ADFLags = 100 },
+		func(pc *PairsConfig) { pc.CointCritical = 1 },
+	}
+	for i, mutate := range bad {
+		pc := DefaultPairsConfig()
+		mutate(&pc)
+		if err := pc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, pc)
+		}
+	}
+	if _, err := NewPairsTrading("A", "A", buildConfig(), testutils.NewMockExecutor(1), testutils.NewMockLogger()); err == nil {
+		t.Fatal("expected an error for identical legs")
+	}
+}
@@ -0,0 +1,200 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+type barProcessor interface {
+	ProcessBar(high, low, close, volume float64)
+}
+
+// singleSymbolCtors lists every BaseStrategy‑based strategy with a
+// constructor that accepts an explicit config.
+func singleSymbolCtors() map[string]func(config.StrategyConfig, executor.Executor, logger.Logger) (barProcessor, error) {
+	return map[string]func(config.StrategyConfig, executor.Executor, logger.Logger) (barProcessor, error){
+		"AdaptiveBandMR": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewAdaptiveBandMR("TEST", c, e, l)
+		},
+		"BreakoutMomentum": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewBreakoutMomentum("TEST", c, e, l)
+		},
+		"DivergenceSwing": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewDivergenceSwing("TEST", c, e, l)
+		},
+		"EventDriven": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			ev, err := NewEventDriven("TEST", c, e, l, 0.0, 5)
+			if err == nil {
+				ev.SetEventActive(true)
+			}
+			return ev, err
+		},
+		"HybridTrendMeanReversion": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewHybridTrendMeanReversion("TEST", c, e, l)
+		},
+		"MeanReversion": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewMeanReversion("TEST", c, e, l)
+		},
+		"MultiTF": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewMultiTF("TEST", c, e, l, 60, 300)
+		},
+		"TrendComposite": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewTrendComposite("TEST", c, e, l)
+		},
+		"VolScaledPos": func(c config.StrategyConfig, e executor.Executor, l logger.Logger) (barProcessor, error) {
+			return NewVolScaledPos("TEST", c, e, l)
+		},
+	}
+}
+
+// choppyBars alternates up‑ and down‑ramps, starting in the direction of
+// first (+1 or -1), so every strategy sees both long and short signals.
+// The last bar of every leg carries a 4 % wick against the leg, which the
+// adaptive band fades.
+func choppyBars(first float64) []candle {
+	var bars []candle
+	price := 100.0
+	for leg := 0; leg < 6; leg++ {
+		step := first
+		if leg%2 == 1 {
+			step = -first
+		}
+		for i := 0; i < 15; i++ {
+			price += step
+			c := candle{high: price + 0.5, low: price - 0.5, close: price, volume: 1000}
+			if i == 14 && step > 0 {
+				c.high = price * 1.04
+			} else if i == 14 {
+				c.low = price * 0.96
+			}
+			bars = append(bars, c)
+		}
+	}
+	return bars
+}
+
+// runPositionMode feeds every strategy choppy bars starting up and starting
+// down.  The position must never take the forbidden side, every order on
+// the forbidden side must be a pure exit, and across both runs each
+// strategy must both open on the allowed side and log a skipped entry, so
+// the test cannot pass for a strategy that never trades.
+func runPositionMode(t *testing.T, mode config.PositionMode, forbidden func(qty float64) bool, forbiddenEntry types.Side) {
+	for name, ctor := range singleSymbolCtors() {
+		t.Run(name, func(t *testing.T) {
+			entries, skipped := 0, false
+			for _, first := range []float64{1, -1} {
+				cfg := buildConfig()
+				cfg.PositionMode = mode
+				exec := testutils.NewMockExecutor(1_000_000)
+				log := testutils.NewMockLogger()
+				strat, err := ctor(cfg, exec, log)
+				if err != nil {
+					t.Fatalf("constructor failed: %v", err)
+				}
+				for i, b := range choppyBars(first) {
+					strat.ProcessBar(b.high, b.low, b.close, b.volume)
+					if qty, _ := exec.Position("TEST"); forbidden(qty) {
+						t.Fatalf("bar %d: position %v violates %s (orders %+v)", i, qty, mode, exec.Orders())
+					}
+				}
+				held := 0.0
+				for _, o := range exec.Orders() {
+					if o.Side == forbiddenEntry && o.Qty > abs(held)+1e-9 {
+						t.Fatalf("order %+v exceeds the position it exits (%v)", o, held)
+					}
+					if o.Side != forbiddenEntry && held == 0 {
+						entries++
+					}
+					if o.Side == types.Buy {
+						held += o.Qty
+					} else {
+						held -= o.Qty
+					}
+				}
+				skipped = skipped || log.HasMessage("entry_skipped_position_mode")
+			}
+			if entries == 0 {
+				t.Fatalf("no entry on the side %s allows; the bars do not exercise the strategy", mode)
+			}
+			if !skipped {
+				t.Fatal("expected an entry on the forbidden side to be skipped and logged")
+			}
+		})
+	}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestPositionMode_LongOnlyNeverShort(t *testing.T) {
+	runPositionMode(t, config.PositionLongOnly, func(q float64) bool { return q < 0 }, types.Sell)
+}
+
+func TestPositionMode_ShortOnlyNeverLong(t *testing.T) {
+	runPositionMode(t, config.PositionShortOnly, func(q float64) bool { return q > 0 }, types.Buy)
+}
+
+func TestPositionMode_ShortSignalExitsLong(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	exec := testutils.NewMockExecutor(10_000)
+	log := testutils.NewMockLogger()
+	mr, err := NewMeanReversion("TEST", cfg, exec, log)
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+
+	var bars []candle
+	for i := 1; i <= 15; i++ {
+		p := 100.0 + float64(i)
+		bars = append(bars, candle{high: p + 0.5, low: p - 0.5, close: p, volume: 1000})
+	}
+	for i := 1; i <= 15; i++ {
+		p := 115.0 - float64(i)
+		bars = append(bars, candle{high: p + 0.5, low: p - 0.5, close: p, volume: 1000})
+	}
+	feedBars(t, mr, bars)
+
+	orders := exec.Orders()
+	if len(orders) != 2 {
+		t.Fatalf("expected long entry and exit only, got %+v", orders)
+	}
+	if orders[0].Side != types.Buy || orders[1].Side != types.Sell || orders[1].Qty != orders[0].Qty {
+		t.Fatalf("expected BUY then matching SELL exit, got %+v", orders)
+	}
+	if !log.HasMessage("entry_skipped_position_mode") {
+		t.Fatal("expected the skipped short entry to be logged")
+	}
+}
+
+func TestPositionMode_RiskParityLongOnly(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	exec := testutils.NewMockExecutor(10_000)
+	log := testutils.NewMockLogger()
+	rp, err := NewRiskParityRotation([]string{"AAA", "BBB"}, cfg, exec, 1, 1, log)
+	if err != nil {
+		t.Fatalf("NewRiskParityRotation failed: %v", err)
+	}
+	for i, b := range choppyBars(1) {
+		rp.ProcessBar("AAA", b.high*1.05, b.low*0.95, b.close, b.volume)
+		rp.ProcessBar("BBB", 101, 99, 100, 1500)
+		for _, sym := range []string{"AAA", "BBB"} {
+			if qty, _ := exec.Position(sym); qty < 0 {
+				t.Fatalf("bar %d: %s short position %v in long‑only mode", i, sym, qty)
+			}
+		}
+	}
+	if !log.HasMessage("entry_skipped_position_mode") {
+		t.Fatal("expected blocked short entries to be logged")
+	}
+}
@@ -0,0 +1,114 @@
+package strategy
+
+import (
+	"math"
+
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// defaultPyramidScale is the size of each add relative to the previous one
+// when Cfg.PyramidScale is zero.
+const defaultPyramidScale = 0.5
+
+// riskPerUnit returns R, the distance from avg to the hard stop, falling
+// back to StopLossPct when no hard stop is configured.
+func (b *BaseStrategy) riskPerUnit(qty, avg float64) float64 {
+	if level, ok := b.stopLevel(qty, avg); ok {
+		return math.Abs(avg - level)
+	}
+	return avg * b.Cfg.StopLossPct
+}
+
+// scalePosition applies the scale‑out, pyramiding and averaging‑down rules
+// to the open position described by st.  It is driven by manageExits once
+// per bar; at most one scaling order is placed per bar.
+func (b *BaseStrategy) scalePosition(st *positionState, qty, avg, close float64) {
+	if st.risk <= 0 {
+		return
+	}
+	progress := st.side * (close - st.entry) / st.risk
+
+	if b.Cfg.ScaleOutR > 0 && !st.scaledOut && progress >= b.Cfg.ScaleOutR {
+		st.scaledOut = true
+		b.Log.Info("scale_out",
+			logger.String("symbol", b.Symbol),
+			logger.Float64("r_multiple", progress),
+			logger.Float64("fraction", b.Cfg.ScaleOutFraction),
+		)
+		b.closeFraction(close, b.Cfg.ScaleOutFraction, "scale_out")
+		return
+	}
+
+	if b.Cfg.PyramidMaxAdds > 0 && st.pyramids < b.Cfg.PyramidMaxAdds &&
+		st.side*(close-st.lastAdd)/st.risk >= b.Cfg.PyramidStepR {
+		if b.addToPosition(st, qty, avg, close, "pyramid_add") {
+			st.pyramids++
+			st.lastAdd = close
+		}
+		return
+	}
+
+	if b.Cfg.AverageDownMaxAdds > 0 && st.averages < b.Cfg.AverageDownMaxAdds &&
+		st.side*(st.lastAverage-close)/st.risk >= b.Cfg.AverageDownStepR {
+		if b.addToPosition(st, qty, avg, close, "average_down_add") {
+			st.averages++
+			st.lastAverage = close
+		}
+	}
+}
+
+// addToPosition buys (or sells, for shorts) a decreasing fraction of the
+// initial size, trimmed so the open risk of the whole position stays within
+// MaxPositionRisk.  It reports whether an order was placed.
+func (b *BaseStrategy) addToPosition(st *positionState, qty, avg, price float64, ctx string) bool {
+	side := types.Buy
+	if st.side < 0 {
+		side = types.Sell
+	}
+	if !b.canOpen(side) {
+		return false
+	}
+	scale := b.Cfg.PyramidScale
+	if scale <= 0 {
+		scale = defaultPyramidScale
+	}
+	adds := st.pyramids + st.averages + 1
+	add := st.initQty * math.Pow(scale, float64(adds))
+
+	maxRisk := b.Cfg.MaxPositionRisk
+	if maxRisk <= 0 {
+		maxRisk = 2 * b.Cfg.MaxRiskPerTrade
+	}
+	if unit := b.riskPerUnit(st.side, price); unit > 0 {
+		room := b.Exec.Equity()*maxRisk/unit - math.Abs(qty)
+		add = math.Min(add, room)
+	}
+	if cash := b.Exec.Equity() / price; side == types.Buy && add > cash {
+		add = cash
+	}
+	add = risk.RoundQty(add, b.Cfg)
+	if add <= 0 {
+		b.Log.Info("scale_in_skipped_risk_limit",
+			logger.String("symbol", b.Symbol),
+			logger.String("ctx", ctx),
+		)
+		return false
+	}
+	o := types.Order{
+		Symbol:  b.Symbol,
+		Side:    side,
+		Qty:     add,
+		Price:   price,
+		Comment: ctx,
+	}
+	b.Log.Info("scale_in",
+		logger.String("symbol", b.Symbol),
+		logger.String("ctx", ctx),
+		logger.Float64("qty", add),
+		logger.Float64("new_avg", executor.NextAvgPrice(qty, avg, st.side*add, price)),
+	)
+	return b.submitOrder(o, ctx) == nil
+}
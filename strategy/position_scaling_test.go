@@ -0,0 +1,142 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/types"
+)
+
+// The scenarios below seed a 10‑unit long at 100 (see buildStopped).  With
+// no hard stop configured R falls back to StopLossPct, i.e. 1.5 per unit.
+
+func TestScaling_PartialTakeProfitThenTrail(t *testing.T) {
+	cfg := buildConfig()
+	cfg.ScaleOutR = 1
+	cfg.ScaleOutFraction = 0.5
+	cfg.TrailingPct = 0.02
+	mr, exec := buildStopped(t, cfg, 10)
+
+	mr.ProcessBar(101, 100, 101, 1000) // 0.67 R – nothing yet
+	if n := len(exec.Orders()); n != 1 {
+		t.Fatalf("scaled out too early: %+v", exec.Orders())
+	}
+	mr.ProcessBar(102, 101, 101.6, 1000) // 1.07 R
+	o := lastOrder(exec)
+	if o.Comment != "scale_out" || o.Side != types.Sell || o.Qty != 5 {
+		t.Fatalf("expected scale_out SELL 5, got %+v", o)
+	}
+	mr.ProcessBar(104, 102, 103.5, 1000)
+	if n := len(exec.Orders()); n != 2 {
+		t.Fatalf("scale‑out must happen only once: %+v", exec.Orders())
+	}
+
+	// The remainder is left to the trailing stop.
+	mr.ProcessBar(104, 101, 101.5, 1000)
+	mr.applyTrailingStop(101.5)
+	if qty, _ := exec.Position("TEST"); qty != 0 {
+		t.Fatalf("remainder should have trailed out, qty %f", qty)
+	}
+	if o := lastOrder(exec); o.Comment != "trailing_stop" || o.Qty != 5 {
+		t.Fatalf("expected trailing_stop on the remaining 5, got %+v", o)
+	}
+}
+
+func TestScaling_PyramidDecreasingSize(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PyramidMaxAdds = 2
+	cfg.PyramidStepR = 1
+	mr, exec := buildStopped(t, cfg, 10)
+
+	for _, c := range []float64{101.5, 103, 104.5, 106} {
+		mr.ProcessBar(c+0.5, c-0.5, c, 1000)
+	}
+	var adds []float64
+	for _, o := range exec.Orders() {
+		if o.Comment == "pyramid_add" {
+			adds = append(adds, o.Qty)
+		}
+	}
+	if len(adds) != 2 || adds[0] != 5 || adds[1] != 2.5 {
+		t.Fatalf("expected adds of 5 then 2.5, got %v", adds)
+	}
+	if qty, _ := exec.Position("TEST"); qty != 17.5 {
+		t.Fatalf("expected 17.5 units after pyramiding, got %f", qty)
+	}
+}
+
+func TestScaling_AddsRespectPositionRisk(t *testing.T) {
+	cfg := buildConfig()
+	cfg.PyramidMaxAdds = 1
+	cfg.PyramidStepR = 1
+	cfg.MaxPositionRisk = 0.002 // ≈ 18 of open risk on 9 000 of equity
+	mr, exec := buildStopped(t, cfg, 10)
+
+	mr.ProcessBar(102, 101, 101.5, 1000)
+	o := lastOrder(exec)
+	if o.Comment != "pyramid_add" {
+		t.Fatalf("expected a trimmed pyramid add, got %+v", o)
+	}
+	qty, _ := exec.Position("TEST")
+	if o.Qty >= 5 || qty*101.5*cfg.StopLossPct > 9000*cfg.MaxPositionRisk+1e-9 {
+		t.Fatalf("add of %f breaches MaxPositionRisk (position %f)", o.Qty, qty)
+	}
+}
+
+func TestScaling_AverageDown(t *testing.T) {
+	cfg := buildConfig()
+	cfg.AverageDownMaxAdds = 1
+	cfg.AverageDownStepR = 1
+	mr, exec := buildStopped(t, cfg, 10)
+
+	mr.ProcessBar(99, 98, 98.4, 1000)
+	mr.ProcessBar(97.5, 96.5, 96.8, 1000)
+	qty, avg := exec.Position("TEST")
+	if qty != 15 {
+		t.Fatalf("expected one averaging add of 5, position %f", qty)
+	}
+	if want := (1000 + 5*98.4) / 15; math.Abs(avg-want) > 1e-9 {
+		t.Fatalf("average entry %f, want %f", avg, want)
+	}
+}
+
+func TestCloseFraction_DustClosesEverything(t *testing.T) {
+	cfg := buildConfig()
+	cfg.MinQty = 4
+	mr, exec := buildStopped(t, cfg, 10)
+
+	// 70 % leaves 3 units, below MinQty – the whole position goes.
+	mr.closeFraction(101, 0.7, "test_partial")
+	if qty, _ := exec.Position("TEST"); qty != 0 {
+		t.Fatalf("expected full close when the remainder is dust, qty %f", qty)
+	}
+}
+
+func TestCloseFraction_ScaleOutThenExitIsOneTrade(t *testing.T) {
+	mr, _ := buildStopped(t, buildConfig(), 10)
+
+	mr.closeFraction(104, 0.5, "scale_out")
+	if n := mr.Trades.Len(); n != 0 {
+		t.Fatalf("a scale‑out must not record a trade on its own, got %d", n)
+	}
+	mr.closePosition(98, "exit")
+	if n := mr.Trades.Len(); n != 1 {
+		t.Fatalf("expected one trade for the position, got %d", n)
+	}
+	// 5 × +4 and 5 × ‑2 on a 1 000 entry cost.
+	if got := mr.Trades.Returns()[0]; math.Abs(got-0.01) > 1e-12 {
+		t.Fatalf("expected the blended return 0.01, got %f", got)
+	}
+
+	// The next position starts from a clean slate.
+	mr, exec := buildStopped(t, buildConfig(), 10)
+	mr.closeFraction(104, 0.5, "scale_out")
+	_ = exec.Submit(types.Order{Symbol: "TEST", Side: types.Sell, Qty: 5, Price: 100})
+	mr.manageExits(100, 100, 100) // a bar while flat
+	_ = exec.Submit(types.Order{Symbol: "TEST", Side: types.Buy, Qty: 10, Price: 100})
+	mr.notePosition()
+	mr.closePosition(101, "exit")
+	if got := mr.Trades.Returns()[0]; math.Abs(got-0.01) > 1e-12 {
+		t.Fatalf("a stale scale‑out leaked into the next trade: %f", got)
+	}
+}
@@ -139,3 +139,31 @@ func (p *priceBuffer) Volatility() float64 {
 	}
 	return diffSum / float64(count)
 }
+
+// ReturnVolatility is the sample standard deviation of simple close‑to‑close
+// returns over the whole buffer (a per‑bar realised volatility).
+func (p *priceBuffer) ReturnVolatility() float64 {
+	n := len(p.buf)
+	if n < 3 {
+		return 0
+	}
+	rets := make([]float64, 0, n-1)
+	mean := 0.0
+	for i := 1; i < n; i++ {
+		if p.buf[i-1] <= 0 {
+			continue
+		}
+		r := p.buf[i]/p.buf[i-1] - 1
+		rets = append(rets, r)
+		mean += r
+	}
+	if len(rets) < 2 {
+		return 0
+	}
+	mean /= float64(len(rets))
+	ss := 0.0
+	for _, r := range rets {
+		ss += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(ss / float64(len(rets)-1))
+}
@@ -0,0 +1,277 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Regime labels the market state found by RegimeClassifier.
+type Regime string
+
+const (
+	RegimeUnknown Regime = "unknown"         // not enough bars yet
+	RegimeTrend   Regime = "trend"           // directional, efficient price path
+	RegimeRange   Regime = "range"           // choppy, mean‑reverting price path
+	RegimeHighVol Regime = "high_volatility" // volatility in the top of its history
+)
+
+// RegimeConfig tunes RegimeClassifier.
+type RegimeConfig struct {
+	// Lookback is the window of the efficiency ratio, the ADX smoothing and
+	// the realised volatility.
+	Lookback int
+	// VolWindow is the history of realised volatility the current value is
+	// ranked against.
+	VolWindow int
+
+	// TrendER and TrendADX are the efficiency ratio and ADX at which the
+	// trend score reaches ½, the boundary between trend and range.
+	TrendER  float64
+	TrendADX float64
+	// HighVolPct is the volatility percentile, in (0, 1), from which the
+	// regime is high volatility whatever the trend score.
+	HighVolPct float64
+}
+
+// DefaultRegimeConfig uses a 14‑bar lookback ranked against 100 bars of
+// volatility, an efficiency ratio of 0.3, an ADX of 25 and the 90th
+// volatility percentile.
+func DefaultRegimeConfig() RegimeConfig {
+	return RegimeConfig{
+		Lookback:   14,
+		VolWindow:  100,
+		TrendER:    0.3,
+		TrendADX:   25,
+		HighVolPct: 0.9,
+	}
+}
+
+// Validate checks the classifier settings.  The windows are bounded so that
+// the bar journal of a snapshot can rebuild the classifier.
+func (rc RegimeConfig) Validate() error {
+	if rc.Lookback < 2 || rc.Lookback > 256 {
+		return fmt.Errorf("Lookback (%d) must be between 2 and 256", rc.Lookback)
+	}
+	if rc.VolWindow < 2 || rc.VolWindow > 1024 {
+		return fmt.Errorf("VolWindow (%d) must be between 2 and 1024", rc.VolWindow)
+	}
+	if rc.TrendER <= 0 || rc.TrendER >= 1 {
+		return fmt.Errorf("TrendER (%f) must be between 0 and 1", rc.TrendER)
+	}
+	if rc.TrendADX <= 0 || rc.TrendADX >= 100 {
+		return fmt.Errorf("TrendADX (%f) must be between 0 and 100", rc.TrendADX)
+	}
+	if rc.HighVolPct <= 0 || rc.HighVolPct >= 1 {
+		return fmt.Errorf("HighVolPct (%f) must be between 0 and 1", rc.HighVolPct)
+	}
+	return nil
+}
+
+// RegimeState is the classification of the latest bar together with the
+// measures it was derived from.
+type RegimeState struct {
+	Regime     Regime
+	Confidence float64 // in [0.5, 1] once known, 0 while unknown
+	Direction  int     // sign of the net move over Lookback bars
+
+	EfficiencyRatio float64 // |net move| / path length over Lookback bars
+	ADX             float64 // Wilder‑style average directional index
+	VolPercentile   float64 // rank of the realised volatility in VolWindow
+	ATR             float64 // Wilder average true range over Lookback bars
+}
+
+// Is reports whether the regime is r with at least the given confidence.
+func (s RegimeState) Is(r Regime, minConfidence float64) bool {
+	return s.Regime == r && s.Confidence >= minConfidence
+}
+
+type regimeBar struct{ high, low, close float64 }
+
+// RegimeClassifier labels every bar as trend, range or high volatility.
+// The trend score averages the efficiency ratio and the ADX, each scaled
+// so that its threshold maps to ½ and twice the threshold to 1; a score of
+// at least ½ is a trend, below it a range, and the confidence is the score
+// (or its complement for a range).  A realised volatility at or above the
+// HighVolPct percentile of its history overrides both.
+//
+// The classifier is not safe for concurrent use.
+type RegimeClassifier struct {
+	rc    RegimeConfig
+	bars  []regimeBar // the last 2×Lookback+1 bars
+	vols  []float64   // realised volatility history, VolWindow long
+	state RegimeState
+}
+
+// NewRegimeClassifier validates rc and returns an empty classifier.
+func NewRegimeClassifier(rc RegimeConfig) (*RegimeClassifier, error) {
+	if err := rc.Validate(); err != nil {
+		return nil, err
+	}
+	return &RegimeClassifier{rc: rc, state: RegimeState{Regime: RegimeUnknown}}, nil
+}
+
+// Bars returns the number of bars Update needs before the regime is known.
+func (c *RegimeClassifier) Bars() int { return 2*c.rc.Lookback + 1 }
+
+// State returns the classification of the latest bar.
+func (c *RegimeClassifier) State() RegimeState { return c.state }
+
+// Update adds a bar and returns the new classification.
+func (c *RegimeClassifier) Update(high, low, close float64) RegimeState {
+	n := c.rc.Lookback
+	c.bars = append(c.bars, regimeBar{high, low, close})
+	if len(c.bars) > c.Bars() {
+		c.bars = c.bars[len(c.bars)-c.Bars():]
+	}
+	if len(c.bars) > n {
+		if vol, ok := c.realisedVol(); ok {
+			c.vols = appendBounded(c.vols, vol, c.rc.VolWindow)
+		}
+	}
+	if len(c.bars) < c.Bars() {
+		c.state = RegimeState{Regime: RegimeUnknown}
+		return c.state
+	}
+
+	s := RegimeState{EfficiencyRatio: c.efficiencyRatio()}
+	s.ADX, s.ATR = c.adx()
+	if net := close - c.bars[len(c.bars)-1-n].close; net > 0 {
+		s.Direction = 1
+	} else if net < 0 {
+		s.Direction = -1
+	}
+
+	score := 0.5*math.Min(s.EfficiencyRatio/(2*c.rc.TrendER), 1) +
+		0.5*math.Min(s.ADX/(2*c.rc.TrendADX), 1)
+	if score >= 0.5 {
+		s.Regime, s.Confidence = RegimeTrend, score
+	} else {
+		s.Regime, s.Confidence = RegimeRange, 1-score
+	}
+	if len(c.vols) > n {
+		s.VolPercentile = percentileRank(c.vols)
+		if s.VolPercentile >= c.rc.HighVolPct {
+			s.Regime = RegimeHighVol
+			s.Confidence = 0.5 + 0.5*(s.VolPercentile-c.rc.HighVolPct)/(1-c.rc.HighVolPct)
+		}
+	}
+	c.state = s
+	return s
+}
+
+// efficiencyRatio is Kaufman's ratio of the net move to the path length
+// over the last Lookback bars.
+func (c *RegimeClassifier) efficiencyRatio() float64 {
+	n := c.rc.Lookback
+	recent := c.bars[len(c.bars)-1-n:]
+	path := 0.0
+	for i := 1; i < len(recent); i++ {
+		path += math.Abs(recent[i].close - recent[i-1].close)
+	}
+	if path == 0 {
+		return 0
+	}
+	return math.Abs(recent[n].close-recent[0].close) / path
+}
+
+// adx smooths true range and directional movement with Wilder's method over
+// the first Lookback moves of the window and averages the DX of the
+// remaining ones.  It also returns the smoothed ATR.
+func (c *RegimeClassifier) adx() (adx, atr float64) {
+	n := float64(c.rc.Lookback)
+	var sTR, sPlus, sMinus, dxSum float64
+	dxCount := 0
+	for i := 1; i < len(c.bars); i++ {
+		cur, prev := c.bars[i], c.bars[i-1]
+		tr := math.Max(cur.high-cur.low, math.Max(math.Abs(cur.high-prev.close), math.Abs(cur.low-prev.close)))
+		up, down := cur.high-prev.high, prev.low-cur.low
+		plus, minus := 0.0, 0.0
+		if up > down && up > 0 {
+			plus = up
+		}
+		if down > up && down > 0 {
+			minus = down
+		}
+		if i <= c.rc.Lookback {
+			sTR, sPlus, sMinus = sTR+tr, sPlus+plus, sMinus+minus
+			if i < c.rc.Lookback {
+				continue
+			}
+		} else {
+			sTR = sTR - sTR/n + tr
+			sPlus = sPlus - sPlus/n + plus
+			sMinus = sMinus - sMinus/n + minus
+		}
+		if sum := sPlus + sMinus; sum > 0 {
+			dxSum += 100 * math.Abs(sPlus-sMinus) / sum
+		}
+		dxCount++
+	}
+	return dxSum / float64(dxCount), sTR / n
+}
+
+// realisedVol is the standard deviation of the last Lookback log returns.
+func (c *RegimeClassifier) realisedVol() (float64, bool) {
+	n := c.rc.Lookback
+	recent := c.bars[len(c.bars)-1-n:]
+	rets := make([]float64, 0, n)
+	for i := 1; i < len(recent); i++ {
+		if recent[i-1].close <= 0 || recent[i].close <= 0 {
+			return 0, false
+		}
+		rets = append(rets, math.Log(recent[i].close/recent[i-1].close))
+	}
+	mean := 0.0
+	for _, r := range rets {
+		mean += r
+	}
+	mean /= float64(len(rets))
+	variance := 0.0
+	for _, r := range rets {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(rets))), true
+}
+
+// percentileRank is the share of the earlier values below the last one.
+func percentileRank(values []float64) float64 {
+	last := values[len(values)-1]
+	below := 0
+	for _, v := range values[:len(values)-1] {
+		if v < last {
+			below++
+		}
+	}
+	return float64(below) / float64(len(values)-1)
+}
+
+// Regime returns the regime of the latest bar.
+func (b *BaseStrategy) Regime() RegimeState {
+	if b.regime == nil {
+		return RegimeState{Regime: RegimeUnknown}
+	}
+	return b.regime.State()
+}
+
+// SetRegimeConfig replaces the classifier settings after validating them.
+// The classifier is rebuilt from the journaled bars.
+func (b *BaseStrategy) SetRegimeConfig(rc RegimeConfig) error {
+	c, err := NewRegimeClassifier(rc)
+	if err != nil {
+		return logOutputError(b.Log, err.Error())
+	}
+	b.regime = c
+	b.replayRegime()
+	return nil
+}
+
+// replayRegime rebuilds the classifier from the bar journal.
+func (b *BaseStrategy) replayRegime() {
+	if b.regime == nil {
+		return
+	}
+	b.regime, _ = NewRegimeClassifier(b.regime.rc)
+	for _, bar := range b.bars {
+		b.regime.Update(bar.High, bar.Low, bar.Close)
+	}
+}
@@ -0,0 +1,161 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+func newRegime(t *testing.T) *RegimeClassifier {
+	t.Helper()
+	c, err := NewRegimeClassifier(DefaultRegimeConfig())
+	if err != nil {
+		t.Fatalf("NewRegimeClassifier failed: %v", err)
+	}
+	return c
+}
+
+// sineCloses oscillates around 100 with the given amplitude and period.
+func sineCloses(n int, amplitude, period float64) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = 100 + amplitude*math.Sin(2*math.Pi*float64(i)/period)
+	}
+	return closes
+}
+
+func classify(c *RegimeClassifier, closes []float64, halfRange float64) RegimeState {
+	var s RegimeState
+	for _, p := range closes {
+		s = c.Update(p+halfRange, p-halfRange, p)
+	}
+	return s
+}
+
+func TestRegimeUnknownUntilReady(t *testing.T) {
+	c := newRegime(t)
+	closes := make([]float64, c.Bars())
+	for i := range closes {
+		closes[i] = 100 + float64(i)
+	}
+	if s := classify(c, closes[:len(closes)-1], 0.5); s.Regime != RegimeUnknown || s.Confidence != 0 {
+		t.Fatalf("expected unknown before %d bars, got %+v", c.Bars(), s)
+	}
+	if s := classify(c, closes[len(closes)-1:], 0.5); s.Regime == RegimeUnknown {
+		t.Fatalf("expected a regime after %d bars, got %+v", c.Bars(), s)
+	}
+}
+
+func TestRegimeTrend(t *testing.T) {
+	c := newRegime(t)
+	closes := make([]float64, 120)
+	for i := range closes {
+		closes[i] = 100 + 0.8*float64(i) + 0.6*math.Sin(float64(i))
+	}
+	s := classify(c, closes, 0.5)
+	if !s.Is(RegimeTrend, 0.7) || s.Direction != 1 {
+		t.Fatalf("expected a confident up‑trend, got %+v", s)
+	}
+	if s.EfficiencyRatio < 0.6 || s.ADX < 25 {
+		t.Fatalf("trend measures too weak: %+v", s)
+	}
+}
+
+func TestRegimeRange(t *testing.T) {
+	c := newRegime(t)
+	// Two full cycles per lookback: the path goes nowhere.
+	s := classify(c, sineCloses(120, 2, 7), 0.5)
+	if !s.Is(RegimeRange, 0.6) {
+		t.Fatalf("expected a confident range, got %+v", s)
+	}
+	if s.EfficiencyRatio > 0.1 {
+		t.Fatalf("a sine wave is an inefficient path, ER %v", s.EfficiencyRatio)
+	}
+	if s.ATR < 1 || s.ATR > 3 {
+		t.Fatalf("ATR %v out of line with the swings", s.ATR)
+	}
+}
+
+func TestRegimeHighVolatility(t *testing.T) {
+	c := newRegime(t)
+	classify(c, sineCloses(120, 0.3, 9), 0.1)
+	s := classify(c, []float64{104, 96, 105, 95}, 1)
+	if !s.Is(RegimeHighVol, 0.5) || s.VolPercentile < 0.9 {
+		t.Fatalf("expected high volatility after the swings, got %+v", s)
+	}
+}
+
+func TestRegimeConfigValidate(t *testing.T) {
+	if err := DefaultRegimeConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	mutate := []func(*RegimeConfig){
+		func(rc *RegimeConfig) { rc.Lookback = 1 },
+		func(rc *RegimeConfig) { rc.VolWindow = 5000 },
+		func(rc *RegimeConfig) { rc.TrendER = 1 },
+		func(rc *RegimeConfig) { rc.TrendADX = 0 },
+		func(rc *RegimeConfig) { rc.HighVolPct = 1 },
+	}
+	for i, m := range mutate {
+		rc := DefaultRegimeConfig()
+		m(&rc)
+		if err := rc.Validate(); err == nil {
+			t.Fatalf("case %d: expected a validation error for %+v", i, rc)
+		}
+	}
+}
+
+func TestBaseStrategyRegimeSurvivesRestore(t *testing.T) {
+	d, _, _ := buildDCA(t, buildConfig(), DefaultDCAConfig())
+	if err := d.SetRegimeConfig(RegimeConfig{Lookback: 1}); err == nil {
+		t.Fatal("expected an invalid regime config to be rejected")
+	}
+	var history []types.Bar
+	for _, p := range sineCloses(60, 2, 9) {
+		history = append(history, types.Bar{High: p + 0.5, Low: p - 0.5, Close: p, Volume: 1000})
+	}
+	d.Warmup(history)
+	want := d.Regime()
+	if want.Regime != RegimeRange {
+		t.Fatalf("Warmup must feed the classifier, got %+v", want)
+	}
+
+	data, err := d.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewDCA("TEST", buildConfig(), testutils.NewMockExecutor(10_000), testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewDCA failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got := resumed.Regime(); got != want {
+		t.Fatalf("restored regime %+v, want %+v", got, want)
+	}
+}
+
+func TestHybridSkipsTrendEntriesInRange(t *testing.T) {
+	ht, exec := buildHybrid(t)
+	closes := sineCloses(120, 3, 12)
+	var history []types.Bar
+	for _, p := range closes[:60] {
+		history = append(history, types.Bar{High: p + 0.5, Low: p - 0.5, Close: p, Volume: 1000})
+	}
+	ht.Warmup(history)
+	var bars []candle
+	for _, p := range closes[60:] {
+		bars = append(bars, candle{p + 0.5, p - 0.5, p, 1000})
+	}
+	feedBars(t, ht, bars)
+
+	if len(exec.Orders()) != 0 || ht.state != stateIdle {
+		t.Fatalf("no trend may be entered in a range regime, got %+v", exec.Orders())
+	}
+	if !ht.Log.(*testutils.MockLogger).HasMessage("hybrid_entry_skipped_regime") {
+		t.Fatal("expected the crossovers to be skipped for the regime")
+	}
+}
@@ -27,10 +27,108 @@ type SymbolState struct {
 	hasLast   bool
 	prevClose float64
 	hasPrev   bool
+	returns   []float64   // rolling close‑to‑close returns
+	closes    []float64   // rolling closes (same window as returns)
+	volumes   []float64   // rolling volumes
+	bars      []barRecord // journal replayed by Restore
 }
 
-// RiskParityRotation rotates capital across a basket of symbols based on a
-// composite strength score (RSI, MFI, ATSO).  It is a multi‑symbol manager, so
+// Symbol returns the symbol this state belongs to.
+func (s *SymbolState) Symbol() string { return s.symbol }
+
+// Suite returns the symbol's indicator suite.
+func (s *SymbolState) Suite() *goti.IndicatorSuite { return s.suite }
+
+// Score returns the strength score assigned at the last rebalance.
+func (s *SymbolState) Score() float64 { return s.score }
+
+// Closes returns a copy of the recent closing prices, oldest first.
+func (s *SymbolState) Closes() []float64 { return append([]float64(nil), s.closes...) }
+
+// Volumes returns a copy of the recent bar volumes, oldest first.
+func (s *SymbolState) Volumes() []float64 { return append([]float64(nil), s.volumes...) }
+
+// Returns returns a copy of the recent close‑to‑close returns, oldest first.
+func (s *SymbolState) Returns() []float64 { return append([]float64(nil), s.returns...) }
+
+// maxReturnHistory bounds the per‑symbol return buffer.
+const maxReturnHistory = 512
+
+// Allocation selects how RiskParityRotation splits capital across the
+// selected symbols.
+type Allocation string
+
+const (
+	// AllocationNominal gives every selected symbol MaxRiskPerTrade/topK of
+	// equity at risk against the StopLossPct stop (the original behaviour).
+	AllocationNominal Allocation = "nominal"
+	// AllocationInverseVol weights symbols by 1/σ of their recent returns.
+	AllocationInverseVol Allocation = "inverse_vol"
+	// AllocationERC solves for equal risk contributions using the rolling
+	// covariance matrix of the basket's returns.
+	AllocationERC Allocation = "erc"
+)
+
+// RotationConfig tunes RiskParityRotation beyond the shared StrategyConfig.
+type RotationConfig struct {
+	Allocation    Allocation
+	Lookback      int     // bars of returns used for volatility / covariance
+	GrossExposure float64 // target Σ|notional| as a multiple of equity
+	MaxWeight     float64 // per‑symbol cap as a fraction of gross (0 = no cap)
+
+	// NoTradeBand is the minimum drift |target − held| / |target| before a
+	// held position is resized (0.1 = 10 %).
+	NoTradeBand float64
+	// MaxTurnover caps the traded notional per rebalance as a fraction of
+	// equity (0 = unlimited).  Exits of deselected symbols always execute;
+	// the remaining budget is shared pro‑rata by entries and resizes.
+	MaxTurnover float64
+
+	// EntryThreshold is the score a symbol must exceed to be selected;
+	// DefaultScorer scores lie in [0, 1].
+	EntryThreshold float64
+}
+
+// DefaultRotationConfig keeps the nominal allocation with a 20‑bar lookback,
+// 1× gross exposure for the volatility‑based modes, a 10 % no‑trade band,
+// no turnover limit and the original 0.1 entry threshold.
+func DefaultRotationConfig() RotationConfig {
+	return RotationConfig{
+		Allocation:     AllocationNominal,
+		Lookback:       20,
+		GrossExposure:  1,
+		NoTradeBand:    0.1,
+		EntryThreshold: 0.1,
+	}
+}
+
+// Validate checks the rotation settings.
+func (rc RotationConfig) Validate() error {
+	switch rc.Allocation {
+	case AllocationNominal, AllocationInverseVol, AllocationERC:
+	default:
+		return fmt.Errorf("unknown allocation %q", rc.Allocation)
+	}
+	if rc.Lookback < 2 || rc.Lookback > maxReturnHistory {
+		return fmt.Errorf("Lookback (%d) must be between 2 and %d", rc.Lookback, maxReturnHistory)
+	}
+	if rc.GrossExposure <= 0 || rc.GrossExposure > 10 {
+		return fmt.Errorf("GrossExposure (%f) must be >0 and <=10", rc.GrossExposure)
+	}
+	if rc.MaxWeight < 0 || rc.MaxWeight > 1 {
+		return fmt.Errorf("MaxWeight (%f) must be between 0 and 1", rc.MaxWeight)
+	}
+	if rc.NoTradeBand < 0 || rc.NoTradeBand >= 1 {
+		return fmt.Errorf("NoTradeBand (%f) must be >=0 and <1", rc.NoTradeBand)
+	}
+	if rc.MaxTurnover < 0 {
+		return fmt.Errorf("MaxTurnover (%f) cannot be negative", rc.MaxTurnover)
+	}
+	return nil
+}
+
+// RiskParityRotation rotates capital across a basket of symbols ranked by a
+// Scorer (DefaultScorer unless SetScorer replaces it).  It is a multi‑symbol manager, so
 // it does not embed BaseStrategy directly; instead it keeps its own logger.
 type RiskParityRotation struct {
 	symbols            []string
@@ -43,6 +141,8 @@ type RiskParityRotation struct {
 	log                logger.Logger
 	mu                 sync.RWMutex // protect states & counters
 	barsSinceRebalance int
+	rotation           RotationConfig
+	scorer             Scorer
 }
 
 // NewRiskParityRotation builds a suite for each symbol and injects a logger.
@@ -52,14 +152,11 @@ func NewRiskParityRotation(symbols []string, cfg config.StrategyConfig,
 	if topK <= 0 || topK > len(symbols) {
 		return nil, logOutputError(log, "invalid topK")
 	}
+	warnIndicatorConfig(log, cfg)
 	states := make(map[string]*SymbolState)
+	newSuite := suiteFactoryFor(cfg)
 	for _, sym := range symbols {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		suite, err := goti.NewIndicatorSuiteWithConfig(ic)
+		suite, err := newSuite()
 		if err != nil {
 			return nil, err
 		}
@@ -77,9 +174,33 @@ func NewRiskParityRotation(symbols []string, cfg config.StrategyConfig,
 		topK:         topK,
 		intervalBars: intervalBars,
 		log:          log,
+		rotation:     DefaultRotationConfig(),
+		scorer:       DefaultScorer(),
 	}, nil
 }
 
+// SetScorer replaces the scoring model used to rank the basket.  A nil
+// scorer restores DefaultScorer.
+func (rp *RiskParityRotation) SetScorer(s Scorer) {
+	if s == nil {
+		s = DefaultScorer()
+	}
+	rp.mu.Lock()
+	rp.scorer = s
+	rp.mu.Unlock()
+}
+
+// SetRotationConfig replaces the allocation settings after validating them.
+func (rp *RiskParityRotation) SetRotationConfig(rc RotationConfig) error {
+	if err := rc.Validate(); err != nil {
+		return logOutputError(rp.log, err.Error())
+	}
+	rp.mu.Lock()
+	rp.rotation = rc
+	rp.mu.Unlock()
+	return nil
+}
+
 // ProcessBar must be called for *every* symbol that receives a new candle.
 func (rp *RiskParityRotation) ProcessBar(symbol string, high, low, close, volume float64) {
 	rp.mu.Lock()
@@ -89,7 +210,7 @@ func (rp *RiskParityRotation) ProcessBar(symbol string, high, low, close, volume
 		// Unknown symbol – ignore silently.
 		return
 	}
-	if err := state.suite.Add(high, low, close, volume); err != nil {
+	if err := state.add(high, low, close, volume); err != nil {
 		rp.mu.Unlock()
 		rp.log.Warn("rp_suite_add_error",
 			logger.String("symbol", symbol),
@@ -97,20 +218,7 @@ func (rp *RiskParityRotation) ProcessBar(symbol string, high, low, close, volume
 		)
 		return
 	}
-	if state.hasLast {
-		state.prevClose = state.lastBar.close
-		state.hasPrev = state.hasLast
-	}
-	state.lastBar = barSnapshot{
-		high:   high,
-		low:    low,
-		close:  close,
-		volume: volume,
-	}
-	state.hasLast = true
 	rp.barsSinceRebalance++
-	// Update strength score on every bar.
-	state.score = rp.computeStrength(state)
 	// Rebalance when all symbols for the interval have been processed.
 	requiredBars := rp.intervalBars * len(rp.symbols)
 	if requiredBars == 0 {
@@ -123,161 +231,307 @@ func (rp *RiskParityRotation) ProcessBar(symbol string, high, low, close, volume
 	rp.mu.Unlock()
 }
 
-// computeStrength builds a normalized composite score from RSI, MFI and ATSO.
-func (rp *RiskParityRotation) computeStrength(state *SymbolState) float64 {
-	suite := state.suite
-	defaults := goti.DefaultConfig()
-
-	rsiUpper := rp.cfg.RSIOverbought
-	rsiLower := rp.cfg.RSIOversold
-	if rsiUpper <= rsiLower {
-		rsiUpper = defaults.RSIOverbought
-		rsiLower = defaults.RSIOversold
-	}
-	mfiUpper := rp.cfg.MFIOverbought
-	mfiLower := rp.cfg.MFIOversold
-	if mfiUpper <= mfiLower {
-		mfiUpper = defaults.MFIOverbought
-		mfiLower = defaults.MFIOversold
-	}
-
-	rsiVal, rsiErr := suite.GetRSI().Calculate()
-	mfiVal, mfiErr := suite.GetMFI().Calculate()
-	atsoVals := suite.GetATSO().GetATSOValues()
-
-	if rsiErr == nil && mfiErr == nil && len(atsoVals) > 0 {
-		rsiNorm := clamp01((rsiVal - rsiLower) / (rsiUpper - rsiLower))
-		mfiNorm := clamp01((mfiVal - mfiLower) / (mfiUpper - mfiLower))
-		atsoNorm := clamp01(math.Abs(atsoVals[len(atsoVals)-1]) / 3.0)
-
-		const (
-			wRSI  = 0.35
-			wMFI  = 0.35
-			wATSO = 0.30
-		)
-		return wRSI*rsiNorm + wMFI*mfiNorm + wATSO*atsoNorm
-	}
-
-	if !state.hasLast {
-		return 0
-	}
-
-	closePx := state.lastBar.close
-	if closePx <= 0 {
-		closePx = 1
-	}
-	rangePerc := 0.0
-	if span := state.lastBar.high - state.lastBar.low; span > 0 {
-		rangePerc = clamp01(span / (closePx * 0.05))
+// add feeds a bar to the symbol's suite and rolling windows.
+func (s *SymbolState) add(high, low, close, volume float64) error {
+	if err := s.suite.Add(high, low, close, volume); err != nil {
+		return err
 	}
-	momentum := 0.0
-	if state.hasPrev && state.prevClose > 0 {
-		delta := (state.lastBar.close - state.prevClose) / state.prevClose
-		delta = clamp(delta/0.05, 0, 1)
-		momentum = delta
+	if s.hasLast {
+		s.prevClose = s.lastBar.close
+		s.hasPrev = s.hasLast
+		if s.prevClose > 0 {
+			s.returns = appendBounded(s.returns, close/s.prevClose-1, maxReturnHistory)
+		}
 	}
-	volumeNorm := 0.0
-	if state.lastBar.volume > 0 {
-		volumeNorm = clamp01(state.lastBar.volume / 8000.0)
+	s.lastBar = barSnapshot{
+		high:   high,
+		low:    low,
+		close:  close,
+		volume: volume,
 	}
-	return 0.6*rangePerc + 0.3*momentum + 0.1*volumeNorm
+	s.hasLast = true
+	s.bars = appendBar(s.bars, barRecord{High: high, Low: low, Close: close, Volume: volume})
+	s.closes = appendBounded(s.closes, close, maxReturnHistory+1)
+	s.volumes = appendBounded(s.volumes, volume, maxReturnHistory+1)
+	return nil
+}
+
+// rebalanceTrade is a single order planned by rebalance.
+type rebalanceTrade struct {
+	symbol  string
+	side    types.Side
+	qty     float64
+	price   float64
+	comment string
 }
 
-// rebalance closes positions that fell out of the top‑K and opens equal‑risk
-// positions for the newly‑selected symbols.
+// rebalance closes positions that fell out of the top‑K, computes target
+// quantities for every selected symbol and trades the difference to the
+// current holdings.
 func (rp *RiskParityRotation) rebalance() {
-	// 1️⃣ Sort symbols by descending score.
+	// 1️⃣ Score the basket and sort by descending score (ties keep basket
+	// order).
+	rp.scoreBasket()
 	type kv struct {
 		sym   string
 		score float64
 	}
 	var sorted []kv
-	for sym, st := range rp.states {
-		sorted = append(sorted, kv{sym, st.score})
+	for _, sym := range rp.symbols {
+		sorted = append(sorted, kv{sym, rp.states[sym].score})
 	}
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].score > sorted[j].score })
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].score > sorted[j].score })
 
 	// 2️⃣ Determine the target set (top‑K) with a minimum strength threshold.
 	targetSet := make(map[string]struct{})
-	const strengthThreshold = 0.1
+	var targets []string // rank order keeps order submission deterministic
 	for i := 0; i < rp.topK && i < len(sorted); i++ {
-		if sorted[i].score <= strengthThreshold {
+		if sorted[i].score <= rp.rotation.EntryThreshold {
 			break
 		}
 		targetSet[sorted[i].sym] = struct{}{}
+		targets = append(targets, sorted[i].sym)
 	}
 
 	// 3️⃣ Close any position not in the target set.
+	exitNotional := 0.0
 	for _, sym := range rp.symbols {
 		qty, _ := rp.exec.Position(sym)
 		if qty == 0 {
 			continue
 		}
 		if _, keep := targetSet[sym]; !keep {
-			state := rp.states[sym]
-			price := state.lastBar.close
-			if price == 0 {
-				closeSeries := state.suite.GetRSI().GetCloses()
-				if len(closeSeries) > 0 {
-					price = closeSeries[len(closeSeries)-1]
-				}
-			}
+			price := rp.lastPrice(rp.states[sym])
 			if price == 0 {
 				continue
 			}
 			rp.closePosition(sym, price)
+			exitNotional += math.Abs(qty) * price
+		}
+	}
+
+	// 4️⃣ Size the target set and trade the drift from current holdings.
+	nav := rp.netAssetValue()
+	prices := make([]float64, len(targets))
+	sides := make([]types.Side, len(targets))
+	for i, sym := range targets {
+		prices[i] = rp.lastPrice(rp.states[sym])
+		sides[i] = rp.entrySide(rp.states[sym])
+	}
+	quantities := rp.targetQuantities(targets, prices, sides, nav)
+	for i, sym := range targets {
+		// A side the position mode forbids becomes an exit‑only target.
+		mode := rp.cfg.PositionMode
+		if (sides[i] == types.Buy && !mode.AllowsLong()) || (sides[i] == types.Sell && !mode.AllowsShort()) {
+			rp.log.Info("entry_skipped_position_mode",
+				logger.String("symbol", sym),
+				logger.String("side", string(sides[i])),
+				logger.String("mode", string(mode)),
+			)
+			quantities[i] = 0
 		}
 	}
+	trades := rp.planTrades(targets, prices, sides, quantities)
+	trades = rp.limitTurnover(trades, nav, exitNotional)
 
-	// 4️⃣ Open equal‑risk positions for the symbols in the target set.
-	totalEquity := rp.exec.Equity()
-	perTradeRiskFraction := rp.cfg.MaxRiskPerTrade / float64(rp.topK)
+	for _, tr := range trades {
+		o := types.Order{
+			Symbol:  tr.symbol,
+			Side:    tr.side,
+			Qty:     tr.qty,
+			Price:   tr.price,
+			Comment: tr.comment,
+		}
+		if err := rp.exec.Submit(o); err != nil {
+			rp.log.Error("risk_parity_submit_error",
+				logger.String("symbol", tr.symbol),
+				logger.Err(err),
+			)
+		}
+	}
+}
 
-	for sym := range targetSet {
+// netAssetValue marks every basket position at its last price and adds the
+// executor's cash balance.
+func (rp *RiskParityRotation) netAssetValue() float64 {
+	nav := rp.exec.Equity()
+	for _, sym := range rp.symbols {
 		qty, _ := rp.exec.Position(sym)
 		if qty != 0 {
-			// Already have a position – skip (could adjust size here).
+			nav += qty * rp.lastPrice(rp.states[sym])
+		}
+	}
+	return nav
+}
+
+// planTrades turns target quantities into delta orders.  New positions are
+// always opened; held positions are only resized when the drift exceeds
+// the no‑trade band.
+func (rp *RiskParityRotation) planTrades(targets []string, prices []float64,
+	sides []types.Side, quantities []float64) []rebalanceTrade {
+
+	var trades []rebalanceTrade
+	for i, sym := range targets {
+		if prices[i] == 0 {
 			continue
 		}
-		state := rp.states[sym]
-		price := state.lastBar.close
-		if price == 0 {
-			closeSeries := state.suite.GetRSI().GetCloses()
-			if len(closeSeries) > 0 {
-				price = closeSeries[len(closeSeries)-1]
-			}
+		held, _ := rp.exec.Position(sym)
+		target := quantities[i]
+		if sides[i] == types.Sell {
+			target = -target
 		}
-		if price == 0 {
+		delta := target - held
+		if delta == 0 {
 			continue
 		}
-
-		qtyToTrade := risk.CalcQty(totalEquity, perTradeRiskFraction, rp.cfg.StopLossPct, price, rp.cfg)
-
-		if qtyToTrade <= 0 {
+		comment := "RiskParity entry"
+		qty := risk.RoundQty(math.Abs(delta), rp.cfg)
+		if held != 0 {
+			if target != 0 && math.Abs(delta) <= rp.rotation.NoTradeBand*math.Abs(target) {
+				continue
+			}
+			comment = "RiskParity resize"
+			if target == 0 {
+				qty = math.Abs(held) // flatten exactly
+			}
+		}
+		if qty <= 0 {
 			continue
 		}
-		// Side based on ATSO sign.
-		atsoRaw, err := state.suite.GetATSO().Calculate()
 		side := types.Buy
-		if err == nil && atsoRaw < 0 {
-			side = types.Sell
-		} else if err != nil && state.hasPrev && state.prevClose > 0 && state.lastBar.close < state.prevClose {
+		if delta < 0 {
 			side = types.Sell
 		}
-		o := types.Order{
-			Symbol:  sym,
-			Side:    side,
-			Qty:     qtyToTrade,
-			Price:   price,
-			Comment: "RiskParity entry",
+		trades = append(trades, rebalanceTrade{
+			symbol:  sym,
+			side:    side,
+			qty:     qty,
+			price:   prices[i],
+			comment: comment,
+		})
+	}
+	return trades
+}
+
+// limitTurnover scales the planned trades down pro‑rata so that, together
+// with the exits already executed, traded notional stays within
+// MaxTurnover × nav.
+func (rp *RiskParityRotation) limitTurnover(trades []rebalanceTrade, nav, exitNotional float64) []rebalanceTrade {
+	if rp.rotation.MaxTurnover <= 0 || len(trades) == 0 {
+		return trades
+	}
+	planned := 0.0
+	for _, tr := range trades {
+		planned += tr.qty * tr.price
+	}
+	budget := rp.rotation.MaxTurnover*nav - exitNotional
+	if planned <= budget {
+		return trades
+	}
+	scale := 0.0
+	if budget > 0 {
+		scale = budget / planned
+	}
+	rp.log.Warn("risk_parity_turnover_limited",
+		logger.Float64("planned", planned),
+		logger.Float64("budget", budget),
+	)
+	out := trades[:0]
+	for _, tr := range trades {
+		tr.qty = risk.RoundQty(tr.qty*scale, rp.cfg)
+		if tr.qty > 0 {
+			out = append(out, tr)
 		}
-		if err := rp.exec.Submit(o); err != nil {
-			rp.log.Error("risk_parity_submit_error",
-				logger.String("symbol", sym),
-				logger.Err(err),
-			)
+	}
+	return out
+}
+
+// lastPrice returns the latest close seen for a symbol (0 if unknown).
+func (rp *RiskParityRotation) lastPrice(state *SymbolState) float64 {
+	price := state.lastBar.close
+	if price == 0 {
+		closeSeries := state.suite.GetRSI().GetCloses()
+		if len(closeSeries) > 0 {
+			price = closeSeries[len(closeSeries)-1]
+		}
+	}
+	return price
+}
+
+// entrySide picks the trade direction from the ATSO sign, falling back to
+// the last close‑to‑close move while ATSO is warming up.
+func (rp *RiskParityRotation) entrySide(state *SymbolState) types.Side {
+	atsoRaw, err := state.suite.GetATSO().Calculate()
+	side := types.Buy
+	if err == nil && atsoRaw < 0 {
+		side = types.Sell
+	} else if err != nil && state.hasPrev && state.prevClose > 0 && state.lastBar.close < state.prevClose {
+		side = types.Sell
+	}
+	return side
+}
+
+// targetQuantities returns the desired absolute quantity for each target
+// symbol under the configured allocation.
+func (rp *RiskParityRotation) targetQuantities(targets []string, prices []float64,
+	sides []types.Side, equity float64) []float64 {
+
+	qtys := make([]float64, len(targets))
+	if len(targets) == 0 {
+		return qtys
+	}
+	if rp.rotation.Allocation == AllocationNominal {
+		perTradeRiskFraction := rp.cfg.MaxRiskPerTrade / float64(rp.topK)
+		for i := range targets {
+			if prices[i] > 0 {
+				qtys[i] = risk.CalcQty(equity, perTradeRiskFraction, rp.cfg.StopLossPct, prices[i], rp.cfg)
+			}
+		}
+		return qtys
+	}
+
+	weights := risk.CapWeights(rp.allocationWeights(targets, sides), rp.rotation.MaxWeight)
+	gross := equity * rp.rotation.GrossExposure
+	for i := range targets {
+		if prices[i] > 0 {
+			qtys[i] = risk.RoundQty(gross*weights[i]/prices[i], rp.cfg)
+		}
+	}
+	return qtys
+}
+
+// allocationWeights computes inverse‑volatility or ERC weights (summing to
+// one) from the last Lookback returns of each target symbol.  ERC falls back
+// to inverse volatility while the covariance matrix is not yet usable.
+func (rp *RiskParityRotation) allocationWeights(targets []string, sides []types.Side) []float64 {
+	series := make([][]float64, len(targets))
+	vols := make([]float64, len(targets))
+	for i, sym := range targets {
+		rets := rp.states[sym].returns
+		if len(rets) > rp.rotation.Lookback {
+			rets = rets[len(rets)-rp.rotation.Lookback:]
+		}
+		series[i] = rets
+		vols[i] = risk.StdDev(rets)
+	}
+	if rp.rotation.Allocation == AllocationERC && len(targets) > 1 {
+		cov := risk.Covariance(series)
+		// A short leg contributes the negative of its return, so flip the
+		// sign of the cross terms between opposite sides.
+		for i := range cov {
+			for j := range cov[i] {
+				if sides[i] != sides[j] {
+					cov[i][j] = -cov[i][j]
+				}
+			}
 		}
+		w, err := risk.EqualRiskContribution(cov)
+		if err == nil {
+			return w
+		}
+		rp.log.Warn("risk_parity_erc_fallback", logger.Err(err))
 	}
+	return risk.InverseVolWeights(vols)
 }
 
 // closePosition flattens the position for a given symbol.
@@ -305,24 +559,13 @@ func (rp *RiskParityRotation) closePosition(symbol string, price float64) {
 	}
 }
 
-func clamp01(v float64) float64 {
-	if v < 0 {
-		return 0
-	}
-	if v > 1 {
-		return 1
-	}
-	return v
-}
-
-func clamp(v, minVal, maxVal float64) float64 {
-	if v < minVal {
-		return minVal
-	}
-	if v > maxVal {
-		return maxVal
+// appendBounded appends v and keeps at most max trailing elements.
+func appendBounded(buf []float64, v float64, max int) []float64 {
+	buf = append(buf, v)
+	if len(buf) > max {
+		buf = buf[len(buf)-max:]
 	}
-	return v
+	return buf
 }
 
 // Helper to create a consistent error when logger is needed.
@@ -1,81 +1,104 @@
 package strategy
 
 import (
+	"math"
 	"testing"
 
+	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/testutils"
 	"github.com/evdnx/gots/types"
 )
 
+// rally feeds bar i of a basket in which AAA rallies on a wide range while
+// BBB drifts lower, so the default scorer ranks AAA first.
+func rally(rp *RiskParityRotation, i int) {
+	a := 100 + float64(i)
+	b := 100 - 0.5*float64(i)
+	rp.ProcessBar("AAA", a+5, a-5, a, 1500)
+	rp.ProcessBar("BBB", b+0.5, b-0.5, b, 1500)
+}
+
+// scored reports whether the last rebalance scored sym.
+func scored(rp *RiskParityRotation, sym string) bool {
+	return !math.IsInf(rp.states[sym].Score(), -1)
+}
+
+// warmUp feeds rally bars until the default scorer scores the basket and
+// returns the number of bars fed.
+func warmUp(t *testing.T, rp *RiskParityRotation) int {
+	t.Helper()
+	for i := range 100 {
+		rally(rp, i)
+		if scored(rp, "AAA") {
+			return i + 1
+		}
+	}
+	t.Fatal("the default scorer never scored the basket")
+	return 0
+}
+
 /*
 -----------------------------------------------------------------------
-Test 1 – Initial rebalance opens a position for the top‑K symbol.
+Test 1 – The first scored rebalance opens a position for the top‑K symbol.
 -----------------------------------------------------------------------
-We use two symbols, `topK = 1`, and a single‑bar interval.
-“AAA” receives a volatile bar (large ATSO magnitude) while “BBB” gets a
-flat bar, so the strength of AAA is higher and the manager should open a
-BUY order for AAA.
+Two symbols, `topK = 1`, a single‑bar interval.  The default scorer leaves
+the basket unscored until RSI, MFI and ATSO are known, so nothing trades
+during the warm‑up; then AAA, rallying while BBB drifts lower, ranks first
+and is bought.
 */
 func TestRiskParity_InitialRebalanceOpensTopK(t *testing.T) {
 	symbols := []string{"AAA", "BBB"}
 	rp, exec := buildRiskParity(t, symbols, 1, 1)
 
-	// ---- volatile bar for AAA ----
-	rp.ProcessBar("AAA", 110, 90, 100, 1500)
-
-	// ---- flat bar for BBB ----
-	rp.ProcessBar("BBB", 101, 99, 100, 1500)
-
-	// After processing both symbols the interval (1 bar) has elapsed,
-	// triggering a rebalance.
+	for i := range 100 {
+		rally(rp, i)
+		if scored(rp, "AAA") {
+			break
+		}
+		if len(exec.Orders()) != 0 {
+			t.Fatalf("bar %d: an unscored basket must not trade, got %+v", i, exec.Orders())
+		}
+	}
+	if !scored(rp, "BBB") {
+		t.Fatal("expected both symbols to be scored on the same bar")
+	}
 	if len(exec.Orders()) != 1 {
-		t.Fatalf("expected one order after initial rebalance, got %d", len(exec.Orders()))
+		t.Fatalf("expected one order after the first scored rebalance, got %+v", exec.Orders())
 	}
 	o := exec.Orders()[0]
-	if o.Symbol != "AAA" {
-		t.Fatalf("expected order for AAA (top‑K), got %s", o.Symbol)
+	if o.Symbol != "AAA" || o.Side != types.Buy || o.Qty <= 0 {
+		t.Fatalf("expected a BUY of the leader AAA, got %+v", o)
 	}
-	if o.Side != types.Buy && o.Side != types.Sell {
-		t.Fatalf("expected BUY or SELL side, got %s", o.Side)
-	}
-	if o.Qty <= 0 {
-		t.Fatalf("expected positive quantity, got %f", o.Qty)
+	if rp.states["AAA"].Score() <= rp.states["BBB"].Score() {
+		t.Fatalf("AAA must outrank BBB, scores %v/%v", rp.states["AAA"].Score(), rp.states["BBB"].Score())
 	}
 }
 
 /*
 -----------------------------------------------------------------------
-Test 2 – Top‑K changes on the next interval → old position closed,
-new position opened.
+Test 2 – Top‑K changes → old position closed, new position opened.
 -----------------------------------------------------------------------
-Interval = 1, `topK = 1`.  First interval: AAA volatile → position opened.
-Second interval: BBB becomes volatile, AAA becomes flat → manager should
-close AAA and open a position for BBB.
+Interval = 1, `topK = 1`.  After AAA is bought the trends reverse: AAA
+falls and BBB rallies until BBB outranks it, so the manager closes AAA and
+opens BBB.
 */
 func TestRiskParity_SwitchesPositionsWhenTopKChanges(t *testing.T) {
 	symbols := []string{"AAA", "BBB"}
 	rp, exec := buildRiskParity(t, symbols, 1, 1)
-
-	/* ---------- Interval 1 – AAA volatile, BBB flat ---------- */
-	rp.ProcessBar("AAA", 110, 90, 100, 1500) // high ATSO magnitude
-	rp.ProcessBar("BBB", 101, 99, 100, 1500) // low ATSO magnitude
-
+	warmUp(t, rp)
 	if len(exec.Orders()) != 1 || exec.Orders()[0].Symbol != "AAA" {
 		t.Fatalf("expected initial order for AAA, got %+v", exec.Orders())
 	}
 	aaaQty := exec.Orders()[0].Qty
 
-	/* ---------- Interval 2 – BBB volatile, AAA flat ---------- */
-	rp.ProcessBar("AAA", 101, 99, 100, 1500) // now flat
-	rp.ProcessBar("BBB", 120, 80, 100, 1500) // high ATSO magnitude
-
-	/*
-	   After the second interval we expect two additional orders:
-	     1. Close AAA (SELL)
-	     2. Open BBB (BUY or SELL depending on ATSO sign)
-	*/
+	a, b := exec.Orders()[0].Price, 90.0
+	for i := 0; i < 30 && len(exec.Orders()) < 3; i++ {
+		a, b = a-1, b+2
+		rp.ProcessBar("AAA", a+0.5, a-0.5, a, 1500)
+		rp.ProcessBar("BBB", b+5, b-5, b, 1500)
+	}
 	if len(exec.Orders()) != 3 {
-		t.Fatalf("expected three total orders after switch, got %d: %+v", len(exec.Orders()), exec.Orders())
+		t.Fatalf("expected three total orders after switch, got %+v", exec.Orders())
 	}
 	closeAAA := exec.Orders()[1]
 	if closeAAA.Symbol != "AAA" || closeAAA.Side != types.Sell {
@@ -85,62 +108,39 @@ func TestRiskParity_SwitchesPositionsWhenTopKChanges(t *testing.T) {
 		t.Fatalf("close‑AAA quantity (%f) should equal original AAA quantity (%f)", closeAAA.Qty, aaaQty)
 	}
 	openBBB := exec.Orders()[2]
-	if openBBB.Symbol != "BBB" {
-		t.Fatalf("expected new order for BBB, got %s", openBBB.Symbol)
-	}
-	if openBBB.Qty <= 0 {
-		t.Fatalf("expected positive quantity for BBB entry, got %f", openBBB.Qty)
+	if openBBB.Symbol != "BBB" || openBBB.Qty <= 0 {
+		t.Fatalf("expected a new order for BBB, got %+v", openBBB)
 	}
 }
 
 /*
 -----------------------------------------------------------------------
-Test 3 – All strengths drop to zero → any open position is closed.
+Test 3 – Nothing clears the entry threshold → any open position is closed.
 -----------------------------------------------------------------------
-First interval opens a position for AAA (volatile bar).  Second interval
-supplies flat bars for both symbols, making ATSO magnitude ≈ 0, so the
-composite strength becomes zero for every symbol.  The manager should
-close the AAA position.
+Rank scores lie in [0, 1] and a symbol needs more than EntryThreshold, so
+raising the threshold to 1 after AAA was bought empties the target set.
 */
-func TestRiskParity_ClosesAllWhenNoStrength(t *testing.T) {
+func TestRiskParity_ClosesAllWhenNothingQualifies(t *testing.T) {
 	symbols := []string{"AAA", "BBB"}
 	rp, exec := buildRiskParity(t, symbols, 1, 1)
-
-	/* ---------- Interval 1 – open AAA ---------- */
-	rp.ProcessBar("AAA", 110, 90, 100, 1500) // volatile → strength > 0
-	rp.ProcessBar("BBB", 101, 99, 100, 1500) // flat → strength ≈ 0
-
+	n := warmUp(t, rp)
 	if len(exec.Orders()) != 1 || exec.Orders()[0].Symbol != "AAA" {
 		t.Fatalf("expected initial order for AAA, got %+v", exec.Orders())
 	}
 	aaaQty := exec.Orders()[0].Qty
 
-	/* ---------- Interval 2 – flat bars for both ---------- */
-	flat := []candle{
-		{
-			high:   100.1,
-			low:    99.9,
-			close:  100.0,
-			volume: 1500,
-		},
-	}
-	// Feed flat bar to both symbols.
-	rp.ProcessBar("AAA", flat[0].high, flat[0].low, flat[0].close, flat[0].volume)
-	rp.ProcessBar("BBB", flat[0].high, flat[0].low, flat[0].close, flat[0].volume)
-
-	/*
-	   After the second interval the manager should issue a SELL order that
-	   closes the AAA position.
-	*/
+	rc := DefaultRotationConfig()
+	rc.EntryThreshold = 1
+	if err := rp.SetRotationConfig(rc); err != nil {
+		t.Fatalf("SetRotationConfig failed: %v", err)
+	}
+	rally(rp, n)
 	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected a second order to close AAA, got %d: %+v", len(exec.Orders()), exec.Orders())
+		t.Fatalf("expected a second order to close AAA, got %+v", exec.Orders())
 	}
 	closeAAA := exec.Orders()[1]
-	if closeAAA.Symbol != "AAA" || closeAAA.Side != types.Sell {
-		t.Fatalf("expected SELL order to close AAA, got %+v", closeAAA)
-	}
-	if closeAAA.Qty != aaaQty {
-		t.Fatalf("close‑AAA quantity (%f) should equal original AAA quantity (%f)", closeAAA.Qty, aaaQty)
+	if closeAAA.Symbol != "AAA" || closeAAA.Side != types.Sell || closeAAA.Qty != aaaQty {
+		t.Fatalf("expected a SELL of the whole AAA position (%v), got %+v", aaaQty, closeAAA)
 	}
 }
 
@@ -161,3 +161,167 @@ func TestRiskParity_InvalidTopK(t *testing.T) {
 		t.Fatalf("expected error for topK > len(symbols), got nil")
 	}
 }
+
+/*
+-----------------------------------------------------------------------
+Test 5 – Inverse‑volatility allocation gives the calmer symbol more
+capital.
+-----------------------------------------------------------------------
+Both symbols are selected (`topK = 2`).  AAA swings ±4 % per bar while
+BBB swings ±1 %, so BBB should receive roughly four times AAA's notional
+and the gross notional should match the configured exposure.
+*/
+func TestRiskParity_InverseVolAllocation(t *testing.T) {
+	symbols := []string{"AAA", "BBB"}
+	rp, exec := buildRiskParity(t, symbols, 2, 6)
+	rc := DefaultRotationConfig()
+	rc.Allocation = AllocationInverseVol
+	rc.GrossExposure = 0.5
+	if err := rp.SetRotationConfig(rc); err != nil {
+		t.Fatalf("SetRotationConfig failed: %v", err)
+	}
+
+	aaa, bbb := 100.0, 100.0
+	for i := 0; i < 6; i++ {
+		sign := 1.0
+		if i%2 == 1 {
+			sign = -1
+		}
+		aaa *= 1 + sign*0.04
+		bbb *= 1 + sign*0.01
+		rp.ProcessBar("AAA", aaa*1.05, aaa*0.95, aaa, 1500)
+		rp.ProcessBar("BBB", bbb*1.05, bbb*0.95, bbb, 1500)
+	}
+
+	orders := exec.Orders()
+	if len(orders) != 2 {
+		t.Fatalf("expected one entry per symbol, got %+v", orders)
+	}
+	notional := map[string]float64{}
+	for _, o := range orders {
+		notional[o.Symbol] = o.Qty * o.Price
+	}
+	ratio := notional["BBB"] / notional["AAA"]
+	if ratio < 3.5 || ratio > 4.5 {
+		t.Fatalf("expected BBB/AAA notional ≈4, got %.2f (%v)", ratio, notional)
+	}
+	if gross := notional["AAA"] + notional["BBB"]; gross < 4_900 || gross > 5_000 {
+		t.Fatalf("expected gross notional ≈5000, got %.2f", gross)
+	}
+}
+
+func TestRiskParity_RejectsInvalidRotationConfig(t *testing.T) {
+	rp, _ := buildRiskParity(t, []string{"AAA", "BBB"}, 1, 1)
+	rc := DefaultRotationConfig()
+	rc.Allocation = "magic"
+	if err := rp.SetRotationConfig(rc); err == nil {
+		t.Fatal("expected error for unknown allocation")
+	}
+}
+
+/*
+-----------------------------------------------------------------------
+Test 6 – Held positions are resized once drift leaves the no‑trade band.
+-----------------------------------------------------------------------
+AAA stays the top symbol.  A 1 % price move keeps the nominal target
+within the 10 % band (no order); a 50 % move shrinks the target quantity
+by a third, so the manager sells the excess instead of skipping AAA.
+*/
+func TestRiskParity_ResizesHeldPositionOutsideBand(t *testing.T) {
+	symbols := []string{"AAA", "BBB"}
+	rp, exec := buildRiskParity(t, symbols, 1, 1)
+	n := warmUp(t, rp)
+	if len(exec.Orders()) != 1 || exec.Orders()[0].Symbol != "AAA" {
+		t.Fatalf("expected initial order for AAA, got %+v", exec.Orders())
+	}
+	entry := exec.Orders()[0]
+
+	// Small move – inside the band.
+	rally(rp, n)
+	if len(exec.Orders()) != 1 {
+		t.Fatalf("expected no trade inside the no‑trade band, got %+v", exec.Orders())
+	}
+
+	// Large move – target quantity drops well outside the band.
+	a, b := 1.5*entry.Price, 100-0.5*float64(n+1)
+	rp.ProcessBar("AAA", a+5, a-5, a, 1500)
+	rp.ProcessBar("BBB", b+0.5, b-0.5, b, 1500)
+	orders := exec.Orders()
+	if len(orders) != 2 {
+		t.Fatalf("expected one resize order, got %+v", orders)
+	}
+	resize := orders[1]
+	if resize.Symbol != "AAA" || resize.Comment != "RiskParity resize" {
+		t.Fatalf("expected AAA resize, got %+v", resize)
+	}
+	held, _ := exec.Position("AAA")
+	if resize.Side != types.Sell || held <= 0 || held >= entry.Qty {
+		t.Fatalf("expected partial SELL of the long, got %+v (held %v)", resize, held)
+	}
+}
+
+/*
+-----------------------------------------------------------------------
+Test 7 – The turnover limit scales entries down.
+-----------------------------------------------------------------------
+With MaxTurnover = 1 % of equity the initial entry may trade at most
+$100 of notional.
+*/
+func TestRiskParity_TurnoverLimit(t *testing.T) {
+	symbols := []string{"AAA", "BBB"}
+	rp, exec := buildRiskParity(t, symbols, 1, 1)
+	rc := DefaultRotationConfig()
+	rc.MaxTurnover = 0.01
+	if err := rp.SetRotationConfig(rc); err != nil {
+		t.Fatalf("SetRotationConfig failed: %v", err)
+	}
+	warmUp(t, rp)
+
+	if len(exec.Orders()) != 1 {
+		t.Fatalf("expected one scaled entry, got %+v", exec.Orders())
+	}
+	o := exec.Orders()[0]
+	if notional := o.Qty * o.Price; notional > 100+1e-9 || notional < 99 {
+		t.Fatalf("expected entry notional capped at $100, got %.4f", notional)
+	}
+}
+
+// A rebalance routed through an AlgoExecutor becomes a parent order that is
+// sliced into TWAP children instead of one market order.
+func TestRiskParity_RebalanceThroughAlgoExecutor(t *testing.T) {
+	mockExec := testutils.NewMockExecutor(10_000)
+	algo, err := executor.NewAlgoExecutor(mockExec, executor.AlgoConfig{Type: executor.AlgoTWAP, Bars: 4}, buildConfig())
+	if err != nil {
+		t.Fatalf("NewAlgoExecutor failed: %v", err)
+	}
+	rp, err := NewRiskParityRotation([]string{"AAA", "BBB"}, buildConfig(), algo, 1, 1, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewRiskParityRotation failed: %v", err)
+	}
+	warmUp(t, rp)
+	if len(mockExec.Orders()) != 0 {
+		t.Fatalf("the rebalance must not trade at once, got %+v", mockExec.Orders())
+	}
+	parent, ok := algo.Working("AAA")
+	if !ok {
+		t.Fatal("expected a working parent for AAA")
+	}
+
+	for range 4 {
+		algo.OnBar("AAA", 101, 99, 100, 1500)
+	}
+	children := mockExec.Orders()
+	if len(children) != 4 {
+		t.Fatalf("expected four TWAP children, got %+v", children)
+	}
+	total := 0.0
+	for _, c := range children {
+		if c.Qty > parent.Qty/4+0.01 {
+			t.Fatalf("child %+v exceeds a quarter of the parent %v", c, parent.Qty)
+		}
+		total += c.Qty
+	}
+	if r := algo.Reports(); len(r) != 1 || r[0].Status != executor.ParentFilled || math.Abs(total-parent.Qty) > 1e-9 {
+		t.Fatalf("children %v must fill the parent %v, reports %+v", total, parent.Qty, r)
+	}
+}
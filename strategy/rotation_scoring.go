@@ -0,0 +1,277 @@
+package strategy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
+)
+
+// Scorer ranks the RiskParityRotation basket.  It receives every symbol's
+// state in basket order and returns one score per state; higher is
+// stronger.  NaN marks a symbol that must not be selected.
+type Scorer interface {
+	Score(states []*SymbolState) []float64
+}
+
+// Factor extracts one raw signal from a symbol's state.  ok is false while
+// the factor lacks data for that symbol.
+type Factor interface {
+	Name() string
+	Value(state *SymbolState) (v float64, ok bool)
+}
+
+// Normalization controls how FactorScorer makes factor values comparable
+// across the basket before weighting them.
+type Normalization string
+
+const (
+	NormalizeNone   Normalization = "none"   // use raw factor values
+	NormalizeRank   Normalization = "rank"   // percentile rank in [0, 1]
+	NormalizeZScore Normalization = "zscore" // cross‑sectional z‑score
+)
+
+// WeightedFactor pairs a factor with its weight in the composite score.
+type WeightedFactor struct {
+	Factor Factor
+	Weight float64
+}
+
+// FactorScorer combines several factors into a weighted average of their
+// cross‑sectionally normalised values.  A symbol missing a factor receives
+// the neutral value for that factor (0 for none/z‑score, 0.5 for rank), or
+// no score at all with RequireAll.
+type FactorScorer struct {
+	Factors       []WeightedFactor
+	Normalization Normalization
+	// RequireAll leaves a symbol unscored (NaN) while any of the factors
+	// lacks data for it, e.g. during the indicator warm‑up.
+	RequireAll bool
+}
+
+// DefaultScorer is the scorer a RiskParityRotation starts with: RSI, MFI
+// and ATSO strength weighted equally and ranked across the basket, with no
+// score until all three are known.  Adjust the Weight of its Factors, or
+// pass a scorer of your own to SetScorer, to change the model.
+func DefaultScorer() FactorScorer {
+	return FactorScorer{
+		Factors: []WeightedFactor{
+			{Factor: RSIFactor{}, Weight: 1},
+			{Factor: MFIFactor{}, Weight: 1},
+			{Factor: ATSOFactor{}, Weight: 1},
+		},
+		Normalization: NormalizeRank,
+		RequireAll:    true,
+	}
+}
+
+// Score implements Scorer.
+func (f FactorScorer) Score(states []*SymbolState) []float64 {
+	scores := make([]float64, len(states))
+	missing := make([]bool, len(states))
+	totalWeight := 0.0
+	for _, wf := range f.Factors {
+		if wf.Factor == nil || wf.Weight == 0 {
+			continue
+		}
+		raw := make([]float64, len(states))
+		for i, st := range states {
+			v, ok := wf.Factor.Value(st)
+			if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+				v = math.NaN()
+				missing[i] = true
+			}
+			raw[i] = v
+		}
+		norm := normalizeCrossSection(raw, f.Normalization)
+		for i := range scores {
+			scores[i] += wf.Weight * norm[i]
+		}
+		totalWeight += math.Abs(wf.Weight)
+	}
+	for i := range scores {
+		switch {
+		case f.RequireAll && missing[i]:
+			scores[i] = math.NaN()
+		case totalWeight > 0:
+			scores[i] /= totalWeight
+		}
+	}
+	return scores
+}
+
+// normalizeCrossSection maps raw values (NaN = missing) onto the requested
+// scale, replacing missing values with the scale's neutral value.
+func normalizeCrossSection(raw []float64, mode Normalization) []float64 {
+	out := make([]float64, len(raw))
+	var present []float64
+	for _, v := range raw {
+		if !math.IsNaN(v) {
+			present = append(present, v)
+		}
+	}
+	switch mode {
+	case NormalizeRank:
+		sorted := append([]float64(nil), present...)
+		sort.Float64s(sorted)
+		for i, v := range raw {
+			if math.IsNaN(v) || len(sorted) < 2 {
+				out[i] = 0.5
+				continue
+			}
+			lo := sort.SearchFloat64s(sorted, v)
+			hi := sort.Search(len(sorted), func(k int) bool { return sorted[k] > v })
+			avgRank := float64(lo+hi-1) / 2 // ties share the average rank
+			out[i] = avgRank / float64(len(sorted)-1)
+		}
+	case NormalizeZScore:
+		mean := 0.0
+		for _, v := range present {
+			mean += v
+		}
+		if len(present) > 0 {
+			mean /= float64(len(present))
+		}
+		sd := risk.StdDev(present)
+		for i, v := range raw {
+			if math.IsNaN(v) || sd == 0 {
+				continue
+			}
+			out[i] = (v - mean) / sd
+		}
+	default:
+		for i, v := range raw {
+			if !math.IsNaN(v) {
+				out[i] = v
+			}
+		}
+	}
+	return out
+}
+
+// MomentumFactor is the simple return over the last Bars bars.
+type MomentumFactor struct{ Bars int }
+
+func (MomentumFactor) Name() string { return "momentum" }
+
+// Value implements Factor.
+func (m MomentumFactor) Value(st *SymbolState) (float64, bool) {
+	n := len(st.closes)
+	if m.Bars <= 0 || n <= m.Bars || st.closes[n-1-m.Bars] <= 0 {
+		return 0, false
+	}
+	return st.closes[n-1]/st.closes[n-1-m.Bars] - 1, true
+}
+
+// RSIFactor is the current RSI value.
+type RSIFactor struct{}
+
+func (RSIFactor) Name() string { return "rsi" }
+
+// Value implements Factor.
+func (RSIFactor) Value(st *SymbolState) (float64, bool) {
+	v, err := st.suite.GetRSI().Calculate()
+	return v, err == nil
+}
+
+// MFIFactor is the current Money Flow Index value.
+type MFIFactor struct{}
+
+func (MFIFactor) Name() string { return "mfi" }
+
+// Value implements Factor.
+func (MFIFactor) Value(st *SymbolState) (float64, bool) {
+	v, err := st.suite.GetMFI().Calculate()
+	return v, err == nil
+}
+
+// ATSOFactor is the magnitude of the latest smoothed ATSO value (trend
+// strength regardless of direction).
+type ATSOFactor struct{}
+
+func (ATSOFactor) Name() string { return "atso" }
+
+// Value implements Factor.
+func (ATSOFactor) Value(st *SymbolState) (float64, bool) {
+	vals := st.suite.GetATSO().GetATSOValues()
+	if len(vals) == 0 {
+		return 0, false
+	}
+	return math.Abs(vals[len(vals)-1]), true
+}
+
+// VolAdjReturnFactor is the cumulative return over Bars bars divided by the
+// volatility expected over the same horizon (σ·√Bars).
+type VolAdjReturnFactor struct{ Bars int }
+
+func (VolAdjReturnFactor) Name() string { return "vol_adj_return" }
+
+// Value implements Factor.
+func (f VolAdjReturnFactor) Value(st *SymbolState) (float64, bool) {
+	if f.Bars < 2 || len(st.returns) < f.Bars {
+		return 0, false
+	}
+	rets := st.returns[len(st.returns)-f.Bars:]
+	sd := risk.StdDev(rets)
+	if sd == 0 {
+		return 0, false
+	}
+	cum := 1.0
+	for _, r := range rets {
+		cum *= 1 + r
+	}
+	return (cum - 1) / (sd * math.Sqrt(float64(f.Bars))), true
+}
+
+// VolumeZFactor is the z‑score of the latest volume against the previous
+// Bars bars.
+type VolumeZFactor struct{ Bars int }
+
+func (VolumeZFactor) Name() string { return "volume_z" }
+
+// Value implements Factor.
+func (f VolumeZFactor) Value(st *SymbolState) (float64, bool) {
+	n := len(st.volumes)
+	if f.Bars < 2 || n <= f.Bars {
+		return 0, false
+	}
+	window := st.volumes[n-1-f.Bars : n-1]
+	sd := risk.StdDev(window)
+	if sd == 0 {
+		return 0, false
+	}
+	mean := 0.0
+	for _, v := range window {
+		mean += v
+	}
+	mean /= float64(len(window))
+	return (st.volumes[n-1] - mean) / sd, true
+}
+
+// scoreBasket assigns a score to every symbol using the configured scorer;
+// unscored symbols get −Inf so they are never selected.
+func (rp *RiskParityRotation) scoreBasket() {
+	states := make([]*SymbolState, len(rp.symbols))
+	for i, sym := range rp.symbols {
+		states[i] = rp.states[sym]
+	}
+	scores := rp.scorer.Score(states)
+	if len(scores) != len(states) {
+		rp.log.Warn("risk_parity_scorer_mismatch",
+			logger.Int("states", len(states)),
+			logger.Int("scores", len(scores)),
+		)
+		scores = make([]float64, len(states))
+		for i := range scores {
+			scores[i] = math.NaN()
+		}
+	}
+	for i, st := range states {
+		if math.IsNaN(scores[i]) {
+			st.score = math.Inf(-1)
+			continue
+		}
+		st.score = scores[i]
+	}
+}
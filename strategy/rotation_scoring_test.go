@@ -0,0 +1,78 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeCrossSection(t *testing.T) {
+	raw := []float64{3, 1, math.NaN(), 1, 5}
+
+	rank := normalizeCrossSection(raw, NormalizeRank)
+	// sorted present values: 1, 1, 3, 5 → ties share rank 0.5 → 0.5/3.
+	want := []float64{2.0 / 3, 0.5 / 3, 0.5, 0.5 / 3, 1}
+	for i := range want {
+		if math.Abs(rank[i]-want[i]) > 1e-12 {
+			t.Fatalf("rank[%d] = %v, want %v", i, rank[i], want[i])
+		}
+	}
+
+	z := normalizeCrossSection(raw, NormalizeZScore)
+	if z[2] != 0 {
+		t.Fatalf("missing value should be neutral (0), got %v", z[2])
+	}
+	if !(z[4] > z[0] && z[0] > z[1]) || math.Abs(z[0]+z[1]+z[3]+z[4]) > 1e-12 {
+		t.Fatalf("unexpected z‑scores: %v", z)
+	}
+}
+
+/*
+-----------------------------------------------------------------------
+Momentum scorer – the symbol with the strongest N‑bar return wins, and
+the entry threshold keeps the rotation flat when nothing qualifies.
+-----------------------------------------------------------------------
+*/
+func TestRiskParity_FactorScorerSelectsMomentumLeader(t *testing.T) {
+	symbols := []string{"AAA", "BBB", "CCC"}
+	rp, exec := buildRiskParity(t, symbols, 1, 4)
+	rp.SetScorer(FactorScorer{
+		Factors:       []WeightedFactor{{Factor: MomentumFactor{Bars: 3}, Weight: 1}},
+		Normalization: NormalizeRank,
+	})
+	rc := DefaultRotationConfig()
+	rc.EntryThreshold = 0.9
+	if err := rp.SetRotationConfig(rc); err != nil {
+		t.Fatalf("SetRotationConfig failed: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		rp.ProcessBar("AAA", 101, 99, 100-float64(i), 1000)   // falling
+		rp.ProcessBar("BBB", 101, 99, 100+float64(i), 1000)   // rising
+		rp.ProcessBar("CCC", 101, 99, 100+float64(i)/4, 1000) // drifting
+	}
+
+	orders := exec.Orders()
+	if len(orders) != 1 || orders[0].Symbol != "BBB" {
+		t.Fatalf("expected a single entry for the momentum leader BBB, got %+v", orders)
+	}
+	if rp.states["BBB"].Score() != 1 {
+		t.Fatalf("expected BBB rank score 1, got %v", rp.states["BBB"].Score())
+	}
+}
+
+func TestRiskParity_EntryThresholdBlocksWeakBasket(t *testing.T) {
+	symbols := []string{"AAA", "BBB"}
+	rp, exec := buildRiskParity(t, symbols, 1, 1)
+	rc := DefaultRotationConfig()
+	rc.EntryThreshold = 1 // unreachable for rank scores (max 1)
+	if err := rp.SetRotationConfig(rc); err != nil {
+		t.Fatalf("SetRotationConfig failed: %v", err)
+	}
+	n := warmUp(t, rp)
+	for i := range 5 {
+		rally(rp, n+i)
+	}
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("expected no entries above an unreachable threshold, got %+v", exec.Orders())
+	}
+}
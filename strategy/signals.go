@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"strings"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/metrics"
+)
+
+// SignalSource tells where a boolean signal's value came from.
+type SignalSource string
+
+const (
+	SourceIndicator SignalSource = "indicator" // the indicator alone fired
+	SourceFallback  SignalSource = "fallback"  // only the price heuristic fired
+	SourceBoth      SignalSource = "both"      // indicator and heuristic agreed
+	SourceNone      SignalSource = "none"      // the signal is false
+)
+
+// SignalRecord is the audit entry of one signal evaluated on a bar.
+type SignalRecord struct {
+	Name   string       `json:"name"`
+	Value  bool         `json:"value"`
+	Source SignalSource `json:"source"`
+	// Ready is false while the indicator was still warming up.
+	Ready bool `json:"ready"`
+}
+
+// Signals returns the audit of every signal evaluated on the latest bar.
+func (b *BaseStrategy) Signals() []SignalRecord {
+	out := make([]SignalRecord, len(b.signals))
+	copy(out, b.signals)
+	return out
+}
+
+// fallbackPolicy returns the configured policy, defaulting to always.
+func (b *BaseStrategy) fallbackPolicy() config.FallbackPolicy {
+	if b.Cfg.SignalFallback == "" {
+		return config.FallbackAlways
+	}
+	return b.Cfg.SignalFallback
+}
+
+// fallbackAllowed reports whether a heuristic may stand in for an indicator
+// value given whether the indicator is ready.
+func (b *BaseStrategy) fallbackAllowed(ready bool) bool {
+	switch b.fallbackPolicy() {
+	case config.FallbackDisabled:
+		return false
+	case config.FallbackWarmup:
+		return !ready
+	default:
+		return true
+	}
+}
+
+// signal evaluates an indicator check, combines it with a price heuristic
+// according to Cfg.SignalFallback and records the outcome for the bar's
+// audit.  The check's error means the indicator is still warming up.
+func (b *BaseStrategy) signal(name string, check func() (bool, error), heuristic func() bool) bool {
+	ind, err := check()
+	return b.combineSignal(name, err == nil && ind, err, heuristic)
+}
+
+// combineSignal is signal for values the caller computed beforehand (for
+// instance from neutral defaults while warming up); ind is used as given and
+// err only reports whether the underlying indicators were ready.
+func (b *BaseStrategy) combineSignal(name string, ind bool, err error, heuristic func() bool) bool {
+	ready := err == nil
+	return b.recordSignal(name, ready, ind, b.fallbackAllowed(ready) && heuristic())
+}
+
+// warmupSignal is signal for call sites that only ever fall back while the
+// indicator warms up; Cfg.SignalFallback can disable that but not widen it.
+func (b *BaseStrategy) warmupSignal(name string, check func() (bool, error), heuristic func() bool) bool {
+	ind, err := check()
+	ready := err == nil
+	fromHeuristic := !ready && b.fallbackPolicy() != config.FallbackDisabled && heuristic()
+	return b.recordSignal(name, ready, ready && ind, fromHeuristic)
+}
+
+// alwaysReady adapts an indicator check that cannot report warm‑up.
+func alwaysReady(check func() bool) func() (bool, error) {
+	return func() (bool, error) { return check(), nil }
+}
+
+func (b *BaseStrategy) recordSignal(name string, ready, fromIndicator, fromHeuristic bool) bool {
+	src := SourceNone
+	switch {
+	case fromIndicator && fromHeuristic:
+		src = SourceBoth
+	case fromIndicator:
+		src = SourceIndicator
+	case fromHeuristic:
+		src = SourceFallback
+	}
+	value := fromIndicator || fromHeuristic
+	b.signals = append(b.signals, SignalRecord{Name: name, Value: value, Source: src, Ready: ready})
+	metrics.SignalSources.WithLabelValues(name, string(src)).Inc()
+	return value
+}
+
+// signalSummary renders the true signals of the current bar as
+// "name=source,…" for order audit logs.
+func (b *BaseStrategy) signalSummary() string {
+	var parts []string
+	for _, s := range b.signals {
+		if s.Value {
+			parts = append(parts, s.Name+"="+string(s.Source))
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+)
+
+var errWarmingUp = errors.New("not enough data")
+
+func TestSignal_FallbackPolicies(t *testing.T) {
+	yes := func() bool { return true }
+	ready := func(v bool) func() (bool, error) { return func() (bool, error) { return v, nil } }
+	warming := func() (bool, error) { return false, errWarmingUp }
+
+	cases := []struct {
+		policy        config.FallbackPolicy
+		readyFalse    bool // indicator ready but false, heuristic true
+		warmingUp     bool // indicator warming up, heuristic true
+		warmupCapOnly bool // warmupSignal while ready and false
+		warmupWarming bool // warmupSignal while warming up
+	}{
+		{"", true, true, false, true},
+		{config.FallbackAlways, true, true, false, true},
+		{config.FallbackWarmup, false, true, false, true},
+		{config.FallbackDisabled, false, false, false, false},
+	}
+	for _, tc := range cases {
+		mr, _ := buildMeanReversion(t)
+		mr.Cfg.SignalFallback = tc.policy
+		if got := mr.signal("a", ready(false), yes); got != tc.readyFalse {
+			t.Errorf("%q: ready/false signal = %v, want %v", tc.policy, got, tc.readyFalse)
+		}
+		if got := mr.signal("b", warming, yes); got != tc.warmingUp {
+			t.Errorf("%q: warming signal = %v, want %v", tc.policy, got, tc.warmingUp)
+		}
+		if got := mr.warmupSignal("c", ready(false), yes); got != tc.warmupCapOnly {
+			t.Errorf("%q: warmupSignal ready = %v, want %v", tc.policy, got, tc.warmupCapOnly)
+		}
+		if got := mr.warmupSignal("d", warming, yes); got != tc.warmupWarming {
+			t.Errorf("%q: warmupSignal warming = %v, want %v", tc.policy, got, tc.warmupWarming)
+		}
+		if !mr.signal("e", ready(true), func() bool { return false }) {
+			t.Errorf("%q: a firing indicator must always pass", tc.policy)
+		}
+	}
+}
+
+func TestSignal_AuditRecordsSources(t *testing.T) {
+	mr, _ := buildMeanReversion(t)
+	mr.recordPrice(100)
+	mr.signal("both", func() (bool, error) { return true, nil }, func() bool { return true })
+	mr.signal("heuristic", func() (bool, error) { return false, errWarmingUp }, func() bool { return true })
+	mr.signal("off", func() (bool, error) { return false, nil }, func() bool { return false })
+
+	want := map[string]SignalRecord{
+		"both":      {Name: "both", Value: true, Source: SourceBoth, Ready: true},
+		"heuristic": {Name: "heuristic", Value: true, Source: SourceFallback, Ready: false},
+		"off":       {Name: "off", Value: false, Source: SourceNone, Ready: true},
+	}
+	got := mr.Signals()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d audit records, got %+v", len(want), got)
+	}
+	for _, r := range got {
+		if r != want[r.Name] {
+			t.Errorf("record %+v, want %+v", r, want[r.Name])
+		}
+	}
+	if s := mr.signalSummary(); s != "both=both,heuristic=fallback" {
+		t.Errorf("unexpected summary %q", s)
+	}
+
+	// A new bar starts a fresh audit.
+	mr.recordPrice(101)
+	if n := len(mr.Signals()); n != 0 {
+		t.Fatalf("audit must be reset per bar, got %d records", n)
+	}
+}
+
+func TestMeanReversion_DisabledFallbackIgnoresPriceRamp(t *testing.T) {
+	var up []candle
+	for i := 1; i <= 15; i++ {
+		p := 100.0 + float64(i)
+		up = append(up, candle{high: p + 0.5, low: p - 0.5, close: p, volume: 1000})
+	}
+
+	mr, exec := buildMeanReversion(t)
+	feedBars(t, mr, up)
+	if len(exec.Orders()) == 0 {
+		t.Fatal("default policy should enter on the heuristic ramp")
+	}
+	for _, r := range mr.Signals() {
+		if r.Value && r.Source == SourceNone {
+			t.Fatalf("inconsistent audit record %+v", r)
+		}
+	}
+
+	mr, exec = buildMeanReversion(t)
+	mr.Cfg.SignalFallback = config.FallbackDisabled
+	feedBars(t, mr, up)
+	if n := len(exec.Orders()); n != 0 {
+		t.Fatalf("disabled fallback must not trade a bare price ramp, got %+v", exec.Orders())
+	}
+}
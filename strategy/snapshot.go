@@ -0,0 +1,642 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// SnapshotVersion is the format version written by Snapshot.  Restore
+// rejects snapshots written by a newer version.
+const SnapshotVersion = 1
+
+// snapshotBars bounds the bar journal that Restore replays into fresh
+// indicator suites (goti does not expose its internal state).  The windowed
+// goti indicators look back a few dozen bars and the EMA‑based ones (ATSO,
+// AMDO) forget their seed long before this, so the replay is exact while
+// fewer bars have been seen and agrees to floating‑point noise afterwards;
+// TestSnapshotRestorePastJournalCap checks that the orders stay identical.
+// At full precision every journaled bar costs about 95 bytes of JSON, so a
+// snapshot levels off at roughly 190 KB per symbol.
+const snapshotBars = 2048
+
+// Snapshotter is implemented by strategies whose state can be persisted by
+// a live runner and resumed later.  Positions themselves are owned by the
+// executor/broker and are not part of a snapshot.
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+// barRecord is one journaled OHLCV bar.
+type barRecord struct {
+	High   float64 `json:"h"`
+	Low    float64 `json:"l"`
+	Close  float64 `json:"c"`
+	Volume float64 `json:"v"`
+}
+
+// appendBar appends b to the journal and keeps the latest snapshotBars.
+func appendBar(bars []barRecord, b barRecord) []barRecord {
+	bars = append(bars, b)
+	if len(bars) > snapshotBars {
+		bars = bars[len(bars)-snapshotBars:]
+	}
+	return bars
+}
+
+// replayBars rebuilds a suite from the journal.  Bars the live suite
+// rejected were already logged, so their errors are ignored here as well.
+func replayBars(newSuite func() (*goti.IndicatorSuite, error), bars []barRecord) (*goti.IndicatorSuite, error) {
+	suite, err := newSuite()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bars {
+		_ = suite.Add(b.High, b.Low, b.Close, b.Volume)
+	}
+	return suite, nil
+}
+
+// snapshotEnvelope is the versioned JSON document written by Snapshot.
+type snapshotEnvelope struct {
+	Version int             `json:"version"`
+	Kind    string          `json:"kind"`
+	Symbol  string          `json:"symbol,omitempty"`
+	Base    *baseSnapshot   `json:"base,omitempty"`
+	State   json.RawMessage `json:"state,omitempty"`
+}
+
+// baseSnapshot is the BaseStrategy part of a snapshot.
+type baseSnapshot struct {
+	Seq    int64                       `json:"seq"`
+	Bars   []barRecord                 `json:"bars"`
+	Prices []float64                   `json:"prices"`
+	Held   map[string]positionSnapshot `json:"held,omitempty"`
+	Trades []float64                   `json:"trades,omitempty"`
+	// Partials holds the scale‑outs of open positions.
+	Partials map[string]partialClose `json:"partials,omitempty"`
+	// Intent is the recorded decision; see setIntent.
+	Intent      Intent  `json:"intent,omitempty"`
+	Strength    float64 `json:"strength,omitempty"`
+	IntentPrice float64 `json:"intent_price,omitempty"`
+	IntentSeq   int64   `json:"intent_seq,omitempty"`
+}
+
+// positionSnapshot mirrors positionState with exported fields.
+type positionSnapshot struct {
+	Side        float64   `json:"side"`
+	Entry       float64   `json:"entry"`
+	Best        float64   `json:"best"`
+	Bars        int       `json:"bars"`
+	Opened      time.Time `json:"opened"`
+	Risk        float64   `json:"risk"`
+	StopDist    float64   `json:"stop_dist,omitempty"`
+	InitQty     float64   `json:"init_qty"`
+	ScaledOut   bool      `json:"scaled_out"`
+	Pyramids    int       `json:"pyramids"`
+	Averages    int       `json:"averages"`
+	LastAdd     float64   `json:"last_add"`
+	LastAverage float64   `json:"last_average"`
+}
+
+func (st *positionState) snapshot() positionSnapshot {
+	return positionSnapshot{
+		Side: st.side, Entry: st.entry, Best: st.best, Bars: st.bars, Opened: st.opened,
+		Risk: st.risk, StopDist: st.stopDist, InitQty: st.initQty, ScaledOut: st.scaledOut,
+		Pyramids: st.pyramids, Averages: st.averages,
+		LastAdd: st.lastAdd, LastAverage: st.lastAverage,
+	}
+}
+
+func (s positionSnapshot) state() *positionState {
+	return &positionState{
+		side: s.Side, entry: s.Entry, best: s.Best, bars: s.Bars, opened: s.Opened,
+		risk: s.Risk, stopDist: s.StopDist, initQty: s.InitQty, scaledOut: s.ScaledOut,
+		pyramids: s.Pyramids, averages: s.Averages,
+		lastAdd: s.LastAdd, lastAverage: s.LastAverage,
+	}
+}
+
+// encodeSnapshot marshals a snapshot of the given kind; state carries the
+// strategy‑specific fields and may be nil.
+func encodeSnapshot(kind, symbol string, base *baseSnapshot, state any) ([]byte, error) {
+	env := snapshotEnvelope{Version: SnapshotVersion, Kind: kind, Symbol: symbol, Base: base}
+	if state != nil {
+		raw, err := json.Marshal(state)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", kind, err)
+		}
+		env.State = raw
+	}
+	return json.Marshal(env)
+}
+
+// decodeSnapshot validates the envelope against the expected kind and
+// symbol and unmarshals the strategy‑specific part into state (if non‑nil).
+func decodeSnapshot(data []byte, kind, symbol string, state any) (*snapshotEnvelope, error) {
+	var env snapshotEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("restore %s: %w", kind, err)
+	}
+	switch {
+	case env.Version < 1 || env.Version > SnapshotVersion:
+		return nil, fmt.Errorf("restore %s: unsupported snapshot version %d", kind, env.Version)
+	case env.Kind != kind:
+		return nil, fmt.Errorf("restore %s: snapshot is for %q", kind, env.Kind)
+	case env.Symbol != symbol:
+		return nil, fmt.Errorf("restore %s: snapshot is for symbol %q, not %q", kind, env.Symbol, symbol)
+	}
+	if state != nil && len(env.State) > 0 {
+		if err := json.Unmarshal(env.State, state); err != nil {
+			return nil, fmt.Errorf("restore %s: %w", kind, err)
+		}
+	}
+	return &env, nil
+}
+
+// recordBar starts a live bar: it journals the bar for Snapshot, feeds the
+// price buffer and opens the bar's decision record.
+func (b *BaseStrategy) recordBar(high, low, close, volume float64) {
+	b.journalBar(high, low, close, volume)
+	b.recordPrice(close)
+	b.beginDecision(high, low, close, volume)
+}
+
+// journalBar appends the bar to the snapshot journal, counts it and
+// classifies the regime.
+func (b *BaseStrategy) journalBar(high, low, close, volume float64) {
+	b.bars = appendBar(b.bars, barRecord{High: high, Low: low, Close: close, Volume: volume})
+	b.barSeq++
+	if b.regime != nil {
+		b.regime.Update(high, low, close)
+	}
+}
+
+func (b *BaseStrategy) snapshotBase() *baseSnapshot {
+	s := &baseSnapshot{
+		Seq:         b.barSeq,
+		Bars:        b.bars,
+		Intent:      b.intent,
+		Strength:    b.strength,
+		IntentPrice: b.intentPrice,
+		IntentSeq:   b.intentSeq,
+	}
+	if b.prices != nil {
+		s.Prices = b.prices.Values()
+	}
+	if len(b.held) > 0 {
+		s.Held = make(map[string]positionSnapshot, len(b.held))
+		for sym, st := range b.held {
+			s.Held[sym] = st.snapshot()
+		}
+	}
+	if b.Trades != nil {
+		s.Trades = b.Trades.Returns()
+	}
+	if len(b.partials) > 0 {
+		s.Partials = maps.Clone(b.partials)
+	}
+	return s
+}
+
+// snapshot encodes the base state together with the strategy's own state.
+func (b *BaseStrategy) snapshot(kind string, state any) ([]byte, error) {
+	return encodeSnapshot(kind, b.Symbol, b.snapshotBase(), state)
+}
+
+// restore decodes a snapshot written by snapshot, rebuilds Suite and every
+// suite in extra from the bar journal and restores the base state.  Nothing
+// is modified when an error is returned.
+func (b *BaseStrategy) restore(kind string, data []byte, state any, extra ...**goti.IndicatorSuite) error {
+	env, err := decodeSnapshot(data, kind, b.Symbol, state)
+	if err != nil {
+		return err
+	}
+	base := env.Base
+	if base == nil {
+		base = &baseSnapshot{}
+	}
+	newSuite := b.newSuite
+	if newSuite == nil {
+		newSuite = suiteFactoryFor(b.Cfg)
+	}
+	suites := make([]*goti.IndicatorSuite, 1+len(extra))
+	for i := range suites {
+		if suites[i], err = replayBars(newSuite, base.Bars); err != nil {
+			return fmt.Errorf("restore %s: %w", kind, err)
+		}
+	}
+
+	b.Suite = suites[0]
+	for i, p := range extra {
+		*p = suites[i+1]
+	}
+	b.bars = append([]barRecord(nil), base.Bars...)
+	b.barSeq = base.Seq
+	b.replayRegime()
+	b.prices = newPriceBuffer(64)
+	for _, v := range base.Prices {
+		b.prices.Add(v)
+	}
+	b.held = make(map[string]*positionState, len(base.Held))
+	for sym, s := range base.Held {
+		b.held[sym] = s.state()
+	}
+	b.Trades = risk.NewTradeHistory(b.Cfg.KellyLookback)
+	for _, r := range base.Trades {
+		b.Trades.Record(r)
+	}
+	b.partials = maps.Clone(base.Partials)
+	b.intent, b.strength = base.Intent, base.Strength
+	b.intentPrice, b.intentSeq = base.IntentPrice, base.IntentSeq
+	b.signals = b.signals[:0]
+	return nil
+}
+
+// Snapshot implements Snapshotter.
+func (a *AdaptiveBandMR) Snapshot() ([]byte, error) { return a.snapshot("adaptive_band_mr", nil) }
+
+// Restore implements Snapshotter.
+func (a *AdaptiveBandMR) Restore(data []byte) error {
+	return a.restore("adaptive_band_mr", data, nil)
+}
+
+// Snapshot implements Snapshotter.
+func (bm *BreakoutMomentum) Snapshot() ([]byte, error) {
+	return bm.snapshot("breakout_momentum", nil)
+}
+
+// Restore implements Snapshotter.
+func (bm *BreakoutMomentum) Restore(data []byte) error {
+	return bm.restore("breakout_momentum", data, nil)
+}
+
+// Snapshot implements Snapshotter.
+func (d *DivergenceSwing) Snapshot() ([]byte, error) { return d.snapshot("divergence_swing", nil) }
+
+// Restore implements Snapshotter.
+func (d *DivergenceSwing) Restore(data []byte) error {
+	return d.restore("divergence_swing", data, nil)
+}
+
+// Snapshot implements Snapshotter.
+func (mr *MeanReversion) Snapshot() ([]byte, error) { return mr.snapshot("mean_reversion", nil) }
+
+// Restore implements Snapshotter.
+func (mr *MeanReversion) Restore(data []byte) error {
+	return mr.restore("mean_reversion", data, nil)
+}
+
+// Snapshot implements Snapshotter.
+func (v *VolScaledPos) Snapshot() ([]byte, error) { return v.snapshot("vol_scaled", nil) }
+
+// Restore implements Snapshotter.
+func (v *VolScaledPos) Restore(data []byte) error { return v.restore("vol_scaled", data, nil) }
+
+type eventDrivenSnapshot struct {
+	EventActive   bool `json:"event_active"`
+	BarSinceEntry int  `json:"bar_since_entry"`
+	Armed         bool `json:"armed"`
+}
+
+// Snapshot implements Snapshotter.
+func (e *EventDriven) Snapshot() ([]byte, error) {
+	return e.snapshot("event_driven", eventDrivenSnapshot{
+		EventActive: e.eventActive, BarSinceEntry: e.barSinceEntry, Armed: e.armed,
+	})
+}
+
+// Restore implements Snapshotter.
+func (e *EventDriven) Restore(data []byte) error {
+	var s eventDrivenSnapshot
+	if err := e.restore("event_driven", data, &s); err != nil {
+		return err
+	}
+	e.eventActive, e.barSinceEntry, e.armed = s.EventActive, s.BarSinceEntry, s.Armed
+	return nil
+}
+
+type hybridSnapshot struct {
+	State          hybridState `json:"state"`
+	TrendSide      string      `json:"trend_side"`
+	FlatBarCounter int         `json:"flat_bar_counter"`
+}
+
+// Snapshot implements Snapshotter.
+func (h *HybridTrendMeanReversion) Snapshot() ([]byte, error) {
+	return h.snapshot("hybrid_trend_mr", hybridSnapshot{
+		State: h.state, TrendSide: string(h.trendSide), FlatBarCounter: h.flatBarCounter,
+	})
+}
+
+// Restore implements Snapshotter.
+func (h *HybridTrendMeanReversion) Restore(data []byte) error {
+	var s hybridSnapshot
+	if err := h.restore("hybrid_trend_mr", data, &s); err != nil {
+		return err
+	}
+	h.state, h.trendSide, h.flatBarCounter = s.State, types.Side(s.TrendSide), s.FlatBarCounter
+	return nil
+}
+
+type multiTFSnapshot struct {
+	LastSignal int `json:"last_signal"`
+}
+
+// Snapshot implements Snapshotter.  The fast and slow suites are fed the
+// same bars as Suite, so they are rebuilt from the same journal.
+func (m *MultiTF) Snapshot() ([]byte, error) {
+	return m.snapshot("multi_tf", multiTFSnapshot{LastSignal: m.lastSignal})
+}
+
+// Restore implements Snapshotter.
+func (m *MultiTF) Restore(data []byte) error {
+	var s multiTFSnapshot
+	if err := m.restore("multi_tf", data, &s, &m.fastSuite, &m.slowSuite); err != nil {
+		return err
+	}
+	m.lastSignal = s.LastSignal
+	return nil
+}
+
+type trendCompositeSnapshot struct {
+	LastDir int `json:"last_dir"`
+}
+
+// Snapshot implements Snapshotter.
+func (t *TrendComposite) Snapshot() ([]byte, error) {
+	return t.snapshot("trend_composite", trendCompositeSnapshot{LastDir: t.lastDir})
+}
+
+// Restore implements Snapshotter.
+func (t *TrendComposite) Restore(data []byte) error {
+	var s trendCompositeSnapshot
+	if err := t.restore("trend_composite", data, &s); err != nil {
+		return err
+	}
+	t.lastDir = s.LastDir
+	return nil
+}
+
+type symbolSnapshot struct {
+	Bars      []barRecord `json:"bars"`
+	Score     float64     `json:"score"`
+	LastBar   barRecord   `json:"last_bar"`
+	HasLast   bool        `json:"has_last"`
+	PrevClose float64     `json:"prev_close"`
+	HasPrev   bool        `json:"has_prev"`
+	Returns   []float64   `json:"returns"`
+	Closes    []float64   `json:"closes"`
+	Volumes   []float64   `json:"volumes"`
+}
+
+type riskParitySnapshot struct {
+	Symbols            map[string]symbolSnapshot `json:"symbols"`
+	BarsSinceRebalance int                       `json:"bars_since_rebalance"`
+}
+
+// Snapshot implements Snapshotter.
+func (rp *RiskParityRotation) Snapshot() ([]byte, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	s := riskParitySnapshot{
+		Symbols:            make(map[string]symbolSnapshot, len(rp.states)),
+		BarsSinceRebalance: rp.barsSinceRebalance,
+	}
+	for sym, st := range rp.states {
+		s.Symbols[sym] = symbolSnapshot{
+			Bars:  st.bars,
+			Score: st.score,
+			LastBar: barRecord{
+				High: st.lastBar.high, Low: st.lastBar.low,
+				Close: st.lastBar.close, Volume: st.lastBar.volume,
+			},
+			HasLast:   st.hasLast,
+			PrevClose: st.prevClose,
+			HasPrev:   st.hasPrev,
+			Returns:   st.returns,
+			Closes:    st.closes,
+			Volumes:   st.volumes,
+		}
+	}
+	return encodeSnapshot("risk_parity_rotation", "", nil, s)
+}
+
+// Restore implements Snapshotter.  The snapshot must cover exactly the
+// basket the strategy was built with.
+func (rp *RiskParityRotation) Restore(data []byte) error {
+	var s riskParitySnapshot
+	if _, err := decodeSnapshot(data, "risk_parity_rotation", "", &s); err != nil {
+		return err
+	}
+	if len(s.Symbols) != len(rp.symbols) {
+		return fmt.Errorf("restore risk_parity_rotation: snapshot has %d symbols, strategy has %d",
+			len(s.Symbols), len(rp.symbols))
+	}
+	newSuite := suiteFactoryFor(rp.cfg)
+	states := make(map[string]*SymbolState, len(rp.symbols))
+	for _, sym := range rp.symbols {
+		ss, ok := s.Symbols[sym]
+		if !ok {
+			return fmt.Errorf("restore risk_parity_rotation: snapshot lacks symbol %q", sym)
+		}
+		suite, err := replayBars(newSuite, ss.Bars)
+		if err != nil {
+			return fmt.Errorf("restore risk_parity_rotation: %w", err)
+		}
+		states[sym] = &SymbolState{
+			suite:  suite,
+			symbol: sym,
+			score:  ss.Score,
+			bars:   ss.Bars,
+			lastBar: barSnapshot{
+				high: ss.LastBar.High, low: ss.LastBar.Low,
+				close: ss.LastBar.Close, volume: ss.LastBar.Volume,
+			},
+			hasLast:   ss.HasLast,
+			prevClose: ss.PrevClose,
+			hasPrev:   ss.HasPrev,
+			returns:   ss.Returns,
+			closes:    ss.Closes,
+			volumes:   ss.Volumes,
+		}
+	}
+	rp.mu.Lock()
+	rp.states = states
+	rp.barsSinceRebalance = s.BarsSinceRebalance
+	rp.mu.Unlock()
+	return nil
+}
+
+type pairsSnapshot struct {
+	Ys         []float64   `json:"ys"`
+	Xs         []float64   `json:"xs"`
+	LastY      float64     `json:"last_y"`
+	LastX      float64     `json:"last_x"`
+	HasY       bool        `json:"has_y"`
+	HasX       bool        `json:"has_x"`
+	Kalman     kalmanHedge `json:"kalman"`
+	Alpha      float64     `json:"alpha"`
+	Beta       float64     `json:"beta"`
+	Z          float64     `json:"z"`
+	Ready      bool        `json:"ready"`
+	Side       int         `json:"side"`
+	EntryGross float64     `json:"entry_gross"`
+	Stopped    bool        `json:"stopped"`
+}
+
+// Snapshot implements Snapshotter.
+func (p *PairsTrading) Snapshot() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return encodeSnapshot("pairs", p.y+"/"+p.x, nil, pairsSnapshot{
+		Ys: p.ys, Xs: p.xs, LastY: p.lastY, LastX: p.lastX, HasY: p.hasY, HasX: p.hasX,
+		Kalman: p.kalman, Alpha: p.alpha, Beta: p.beta, Z: p.z, Ready: p.ready,
+		Side: p.side, EntryGross: p.entryGross, Stopped: p.stopped,
+	})
+}
+
+// Restore implements Snapshotter.
+func (p *PairsTrading) Restore(data []byte) error {
+	var s pairsSnapshot
+	if _, err := decodeSnapshot(data, "pairs", p.y+"/"+p.x, &s); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ys, p.xs = s.Ys, s.Xs
+	p.lastY, p.lastX, p.hasY, p.hasX = s.LastY, s.LastX, s.HasY, s.HasX
+	p.kalman = s.Kalman
+	p.alpha, p.beta, p.z, p.ready = s.Alpha, s.Beta, s.Z, s.Ready
+	p.side, p.entryGross, p.stopped = s.Side, s.EntryGross, s.Stopped
+	return nil
+}
+
+type gridSnapshot struct {
+	Prices []float64          `json:"prices"`
+	Qty    float64            `json:"qty"`
+	MaxInv float64            `json:"max_inventory"`
+	Orders map[int]*gridOrder `json:"orders"`
+}
+
+// Snapshot implements Snapshotter.  The resting orders stay on the
+// executor's book; the snapshot keeps their ids.
+func (g *GridTrading) Snapshot() ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return encodeSnapshot("grid", g.symbol, nil, gridSnapshot{
+		Prices: g.prices, Qty: g.qty, MaxInv: g.maxInv, Orders: g.orders,
+	})
+}
+
+// Restore implements Snapshotter.
+func (g *GridTrading) Restore(data []byte) error {
+	var s gridSnapshot
+	if _, err := decodeSnapshot(data, "grid", g.symbol, &s); err != nil {
+		return err
+	}
+	if s.Orders == nil {
+		s.Orders = make(map[int]*gridOrder)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s.Prices != nil && len(s.Prices) != 2*g.grid.Levels+1 {
+		return fmt.Errorf("restore grid: %d level prices do not match %d levels", len(s.Prices), g.grid.Levels)
+	}
+	g.prices, g.qty, g.maxInv, g.orders = s.Prices, s.Qty, s.MaxInv, s.Orders
+	return nil
+}
+
+type marketMakerSnapshot struct {
+	Bid      float64 `json:"bid"`
+	Ask      float64 `json:"ask"`
+	BidSize  float64 `json:"bid_size"`
+	AskSize  float64 `json:"ask_size"`
+	HasBook  bool    `json:"has_book"`
+	QuoteBid float64 `json:"quote_bid"`
+	QuoteAsk float64 `json:"quote_ask"`
+	BidID    string  `json:"bid_id,omitempty"`
+	AskID    string  `json:"ask_id,omitempty"`
+}
+
+// Snapshot implements Snapshotter.  The quotes stay on the executor's
+// book; the snapshot keeps their ids.
+func (m *MarketMaker) Snapshot() ([]byte, error) {
+	return m.snapshot("market_maker", marketMakerSnapshot{
+		Bid: m.bid, Ask: m.ask, BidSize: m.bidSize, AskSize: m.askSize, HasBook: m.hasBook,
+		QuoteBid: m.quoteBid, QuoteAsk: m.quoteAsk, BidID: m.bidID, AskID: m.askID,
+	})
+}
+
+// Restore implements Snapshotter; the ATR is rebuilt from the bar journal.
+func (m *MarketMaker) Restore(data []byte) error {
+	var s marketMakerSnapshot
+	if err := m.restore("market_maker", data, &s); err != nil {
+		return err
+	}
+	m.bid, m.ask, m.bidSize, m.askSize, m.hasBook = s.Bid, s.Ask, s.BidSize, s.AskSize, s.HasBook
+	m.quoteBid, m.quoteAsk, m.bidID, m.askID = s.QuoteBid, s.QuoteAsk, s.BidID, s.AskID
+	return m.replayATR()
+}
+
+type donchianSnapshot struct {
+	Units     int     `json:"units"`
+	LastEntry float64 `json:"last_entry"`
+	Stop      float64 `json:"stop"`
+	N         float64 `json:"n"`
+}
+
+// Snapshot implements Snapshotter.
+func (d *DonchianBreakout) Snapshot() ([]byte, error) {
+	return d.snapshot("donchian_breakout", donchianSnapshot{
+		Units: d.units, LastEntry: d.lastEntry, Stop: d.stop, N: d.n,
+	})
+}
+
+// Restore implements Snapshotter; the ATR is rebuilt from the bar journal.
+func (d *DonchianBreakout) Restore(data []byte) error {
+	var s donchianSnapshot
+	if err := d.restore("donchian_breakout", data, &s); err != nil {
+		return err
+	}
+	d.units, d.lastEntry, d.stop, d.n = s.Units, s.LastEntry, s.Stop, s.N
+	return d.replayATR()
+}
+
+type dcaSnapshot struct {
+	Started     bool      `json:"started"`
+	SinceBuy    int       `json:"since_buy"`
+	LastBuy     time.Time `json:"last_buy"`
+	Buys        int       `json:"buys"`
+	Invested    float64   `json:"invested"`
+	Accumulated float64   `json:"accumulated"`
+	// TotalInvested includes the cost of the quantity sold since.
+	TotalInvested float64 `json:"total_invested,omitempty"`
+}
+
+// Snapshot implements Snapshotter.
+func (d *DCA) Snapshot() ([]byte, error) {
+	return d.snapshot("dca", dcaSnapshot{
+		Started: d.started, SinceBuy: d.sinceBuy, LastBuy: d.lastBuy,
+		Buys: d.buys, Invested: d.invested, Accumulated: d.accumulated,
+		TotalInvested: d.totalInvested,
+	})
+}
+
+// Restore implements Snapshotter.
+func (d *DCA) Restore(data []byte) error {
+	var s dcaSnapshot
+	if err := d.restore("dca", data, &s); err != nil {
+		return err
+	}
+	d.started, d.sinceBuy, d.lastBuy = s.Started, s.SinceBuy, s.LastBuy
+	d.buys, d.invested, d.accumulated = s.Buys, s.Invested, s.Accumulated
+	d.totalInvested = s.TotalInvested
+	return nil
+}
@@ -0,0 +1,225 @@
+package strategy
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/testutils"
+)
+
+// snapshotter is a single‑symbol strategy that can be persisted.
+type snapshotter interface {
+	Snapshotter
+	ProcessBar(high, low, close, volume float64)
+}
+
+// waveBars is a deterministic oscillating, slowly rising series with the
+// occasional long wick that makes the strategies enter and exit several
+// times.
+func waveBars(n int) []candle {
+	bars := make([]candle, n)
+	for i := range bars {
+		x := float64(i)
+		c := 100 + 8*math.Sin(x/6) + 0.05*x + 0.7*math.Sin(x*1.7)
+		b := candle{high: c + 1, low: c - 1, close: c, volume: 1000 + 200*math.Sin(x/3)}
+		if i%17 == 0 {
+			b.low = c - 6
+		}
+		if i%23 == 0 {
+			b.high = c + 6
+		}
+		bars[i] = b
+	}
+	return bars
+}
+
+// snapshotConfig enables the hard stop, a trailing stop and a holding limit
+// so the exit bookkeeping is part of the persisted state.
+func snapshotConfig() config.StrategyConfig {
+	cfg := buildConfig()
+	cfg.StopType = config.StopPct
+	cfg.StopLossPct = 0.03
+	cfg.TrailingPct = 0.02
+	cfg.MaxHoldingBars = 6
+	return cfg
+}
+
+// snapshotCtors builds every single‑symbol strategy on an executor.
+func snapshotCtors() map[string]func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+	return map[string]func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error){
+		"adaptive": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewAdaptiveBandMR("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"breakout": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewBreakoutMomentum("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"divergence": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewDivergenceSwing("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"event": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			ev, err := NewEventDriven("TEST", cfg, exec, testutils.NewMockLogger(), 0.0001, 8)
+			if err == nil {
+				ev.SetEventActive(true)
+			}
+			return ev, err
+		},
+		"hybrid": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewHybridTrendMeanReversion("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"mean_reversion": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewMeanReversion("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"multi_tf": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewMultiTF("TEST", cfg, exec, testutils.NewMockLogger(), 60, 300)
+		},
+		"trend_composite": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewTrendComposite("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+		"vol_scaled": func(cfg config.StrategyConfig, exec executor.Executor) (snapshotter, error) {
+			return NewVolScaledPos("TEST", cfg, exec, testutils.NewMockLogger())
+		},
+	}
+}
+
+// TestSnapshotRestoreMatchesUninterruptedRun runs every strategy over the
+// same bars twice: once straight through, and once interrupted by
+// Snapshot, a fresh instance on the same executor and Restore.  Both runs
+// must submit identical orders.
+func TestSnapshotRestoreMatchesUninterruptedRun(t *testing.T) {
+	checkResumedRuns(t, waveBars(240), 120)
+}
+
+// TestSnapshotRestorePastJournalCap resumes runs longer than the bar
+// journal, so Restore rebuilds the indicators from the last snapshotBars
+// bars only; the orders must still be identical.
+func TestSnapshotRestorePastJournalCap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("long run")
+	}
+	checkResumedRuns(t, waveBars(snapshotBars+400), snapshotBars+200)
+}
+
+// checkResumedRuns resumes every strategy at each of the bars in resume
+// and, to be sure the resumed run has to reproduce an order, at the bar
+// that submits the last one; both runs must submit identical orders.
+func checkResumedRuns(t *testing.T, bars []candle, resume ...int) {
+	t.Helper()
+	for name, ctor := range snapshotCtors() {
+		t.Run(name, func(t *testing.T) {
+			cfg := snapshotConfig()
+
+			straightExec := testutils.NewMockExecutor(10_000)
+			straight, err := ctor(cfg, straightExec)
+			if err != nil {
+				t.Fatalf("constructor failed: %v", err)
+			}
+			cut := 0
+			for i, b := range bars {
+				n := len(straightExec.Orders())
+				straight.ProcessBar(b.high, b.low, b.close, b.volume)
+				if len(straightExec.Orders()) > n {
+					cut = i
+				}
+			}
+			if cut == 0 {
+				t.Fatal("scenario submits no orders")
+			}
+
+			for _, at := range append(resume, cut) {
+				resumedExec := testutils.NewMockExecutor(10_000)
+				first, err := ctor(cfg, resumedExec)
+				if err != nil {
+					t.Fatalf("constructor failed: %v", err)
+				}
+				feedBars(t, first, bars[:at])
+				data, err := first.Snapshot()
+				if err != nil {
+					t.Fatalf("Snapshot failed: %v", err)
+				}
+
+				resumed, err := ctor(cfg, resumedExec)
+				if err != nil {
+					t.Fatalf("constructor failed: %v", err)
+				}
+				if err := resumed.Restore(data); err != nil {
+					t.Fatalf("Restore failed: %v", err)
+				}
+				feedBars(t, resumed, bars[at:])
+
+				want, got := straightExec.Orders(), resumedExec.Orders()
+				if !reflect.DeepEqual(want, got) {
+					t.Fatalf("run resumed at bar %d diverged:\nwant %+v\ngot  %+v", at, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotRestoreRiskParity(t *testing.T) {
+	symbols := []string{"AAA", "BBB", "CCC"}
+	feed := func(rp *RiskParityRotation, from, to int) {
+		for i := from; i < to; i++ {
+			for j, sym := range symbols {
+				x := float64(i)
+				c := 50 + 10*float64(j) + 4*math.Sin(x/(5+float64(j))) + 0.1*x*float64(j-1)
+				rp.ProcessBar(sym, c+0.5, c-0.5, c, 1000+50*float64(j))
+			}
+		}
+	}
+
+	straight, straightExec := buildRiskParity(t, symbols, 2, 5)
+	feed(straight, 0, 120)
+
+	first, resumedExec := buildRiskParity(t, symbols, 2, 5)
+	feed(first, 0, 63)
+	data, err := first.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	resumed, err := NewRiskParityRotation(symbols, buildConfig(), resumedExec, 2, 5, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewRiskParityRotation failed: %v", err)
+	}
+	if err := resumed.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	feed(resumed, 63, 120)
+
+	if !reflect.DeepEqual(straightExec.Orders(), resumedExec.Orders()) {
+		t.Fatalf("resumed run diverged:\nwant %+v\ngot  %+v", straightExec.Orders(), resumedExec.Orders())
+	}
+	if len(straightExec.Orders()) == 0 {
+		t.Fatal("scenario submits no orders")
+	}
+}
+
+func TestRestoreRejectsForeignSnapshots(t *testing.T) {
+	mr, _ := buildMeanReversion(t)
+	feedBars(t, mr, waveBars(20))
+	data, err := mr.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	tc, _ := buildTrendComposite(t)
+	if err := tc.Restore(data); err == nil || !strings.Contains(err.Error(), "mean_reversion") {
+		t.Fatalf("expected kind mismatch error, got %v", err)
+	}
+
+	future := strings.Replace(string(data), `"version":1`, `"version":99`, 1)
+	if err := mr.Restore([]byte(future)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+
+	other, err := NewMeanReversion("OTHER", buildConfig(), testutils.NewMockExecutor(10_000), testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewMeanReversion failed: %v", err)
+	}
+	if err := other.Restore(data); err == nil {
+		t.Fatal("expected symbol mismatch error")
+	}
+}
@@ -0,0 +1,240 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/executor"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/metrics"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/types"
+)
+
+// TargetPosition is the position a strategy in target mode wants to hold
+// after a bar.
+type TargetPosition struct {
+	Bar    int64 // 1‑based bar count of the strategy
+	Symbol string
+	Time   time.Time
+	Price  float64 // close of the bar
+
+	// Intent and Strength are the decision the strategy recorded, as read
+	// by BaseStrategy.Intent and BaseStrategy.Strength; they do not depend
+	// on any sizing.
+	Intent   Intent
+	Strength float64
+}
+
+// Score returns the signed strength of the target: Strength on the side of
+// Intent.
+func (t TargetPosition) Score() float64 { return float64(t.Intent) * t.Strength }
+
+// TargetSink receives one TargetPosition per bar.  Setting a sink with
+// BaseStrategy.SetTargetMode separates a strategy's signal logic from order
+// handling.
+type TargetSink interface {
+	OnTarget(TargetPosition)
+}
+
+// TargetSinkFunc adapts a function to TargetSink.
+type TargetSinkFunc func(TargetPosition)
+
+// OnTarget implements TargetSink.
+func (f TargetSinkFunc) OnTarget(t TargetPosition) { f(t) }
+
+// SetTargetMode runs the strategy in signal‑only mode (see SetSignalOnly)
+// and hands sink the intent recorded by its decision code after every bar,
+// so sizing and execution can be left to a separate component such as
+// TargetTrader.  Like the Ensemble members, strategies that do not decide
+// a side to hold return ErrSignalOnlyUnsupported.
+func (b *BaseStrategy) SetTargetMode(sink TargetSink) error {
+	if err := b.SetSignalOnly(); err != nil {
+		return err
+	}
+	b.targets = sink
+	b.targetSeq = b.barSeq
+	return nil
+}
+
+// emitTarget hands the intent after the current bar to the target sink.
+// Bars that never reached the journal (suite errors) emit nothing.
+func (b *BaseStrategy) emitTarget() {
+	if b.targets == nil || b.barSeq == b.targetSeq || len(b.bars) == 0 {
+		return
+	}
+	b.targetSeq = b.barSeq
+	b.targets.OnTarget(TargetPosition{
+		Bar:      b.barSeq,
+		Symbol:   b.Symbol,
+		Time:     b.now(),
+		Price:    b.bars[len(b.bars)-1].Close,
+		Intent:   b.intent,
+		Strength: b.strength,
+	})
+}
+
+// TargetSizing maps a target to the signed quantity to hold on the real
+// executor, given the NAV of that executor: its cash plus the positions the
+// trader holds marked at their latest target prices.  The result is rounded
+// by TargetTrader.
+type TargetSizing func(t TargetPosition, nav float64) float64
+
+// SizeByWeight holds weight × Score of the NAV, so a full‑strength target
+// puts weight of the account on the side of the intent.
+func SizeByWeight(weight float64) TargetSizing {
+	return func(t TargetPosition, nav float64) float64 {
+		if t.Price <= 0 {
+			return 0
+		}
+		return weight * t.Score() * nav / t.Price
+	}
+}
+
+// SizeByNotional holds a fixed notional on the side of the intent,
+// whatever its strength.
+func SizeByNotional(notional float64) TargetSizing {
+	return func(t TargetPosition, _ float64) float64 {
+		if t.Price <= 0 {
+			return 0
+		}
+		return float64(t.Intent) * notional / t.Price
+	}
+}
+
+// SizeBySizer holds the quantity s gives for the NAV, scaled by Score.
+func SizeBySizer(s risk.Sizer) TargetSizing {
+	return func(t TargetPosition, nav float64) float64 {
+		if t.Score() == 0 {
+			return 0
+		}
+		return t.Score() * s.Qty(risk.SizeInput{Equity: nav, Price: t.Price})
+	}
+}
+
+// TargetTrader is the execution side of target mode: it sizes every target
+// with its TargetSizing and submits the difference to the position held on
+// its executor as one market order.  Wrapping the executor (for instance in
+// an executor.AlgoExecutor) changes how the orders are worked.  The latest
+// target per symbol wins.  It is safe for concurrent use.
+type TargetTrader struct {
+	Exec executor.Executor
+	Log  logger.Logger
+	// Cfg supplies the exchange rounding (QuantityPrecision, StepSize,
+	// MinQty) and the permitted PositionMode.
+	Cfg    config.StrategyConfig
+	sizing TargetSizing
+	// band is the part of the desired quantity a position may deviate by
+	// before it is rebalanced.
+	band float64
+
+	mu      sync.Mutex
+	targets map[string]TargetPosition
+}
+
+// NewTargetTrader validates cfg and returns a trader without a rebalance
+// band.
+func NewTargetTrader(exec executor.Executor, cfg config.StrategyConfig,
+	sizing TargetSizing, log logger.Logger) (*TargetTrader, error) {
+
+	if exec == nil || sizing == nil {
+		return nil, fmt.Errorf("target trader needs an executor and a sizing policy")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &TargetTrader{
+		Exec:    exec,
+		Log:     log,
+		Cfg:     cfg,
+		sizing:  sizing,
+		targets: make(map[string]TargetPosition),
+	}, nil
+}
+
+// SetBand skips rebalances smaller than band × the desired quantity, which
+// keeps weight‑based sizing from trading every bar as prices drift.
+// Entries, exits and side changes always trade.
+func (tt *TargetTrader) SetBand(band float64) error {
+	if band < 0 || band >= 1 || math.IsNaN(band) {
+		return logOutputError(tt.Log, fmt.Sprintf("band (%f) must be in [0, 1)", band))
+	}
+	tt.mu.Lock()
+	tt.band = band
+	tt.mu.Unlock()
+	return nil
+}
+
+// Target returns the latest target received for symbol.
+func (tt *TargetTrader) Target(symbol string) (TargetPosition, bool) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	t, ok := tt.targets[symbol]
+	return t, ok
+}
+
+// nav returns the cash of the executor plus every position the trader has
+// a target for, marked at the price of that target.  Executors report cash
+// as their equity, and sizing off cash alone would shrink the position
+// every time a target is repeated.
+func (tt *TargetTrader) nav() float64 {
+	nav := tt.Exec.Equity()
+	for sym, t := range tt.targets {
+		held, _ := tt.Exec.Position(sym)
+		nav += held * t.Price
+	}
+	return nav
+}
+
+// OnTarget implements TargetSink.
+func (tt *TargetTrader) OnTarget(t TargetPosition) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.targets[t.Symbol] = t
+
+	want := tt.sizing(t, tt.nav())
+	mode := tt.Cfg.PositionMode
+	if (want > 0 && !mode.AllowsLong()) || (want < 0 && !mode.AllowsShort()) {
+		want = 0
+	}
+	want = math.Copysign(risk.RoundQty(math.Abs(want), tt.Cfg), want)
+	held, _ := tt.Exec.Position(t.Symbol)
+
+	delta := want - held
+	sameSide := want != 0 && held != 0 && (want > 0) == (held > 0)
+	if sameSide && math.Abs(delta) <= tt.band*math.Abs(want) {
+		return
+	}
+	// want and held already lie on the exchange grid; flooring their
+	// difference again would lose a step to float noise.
+	qty := math.Abs(delta)
+	if qty == 0 || (want != 0 && qty < tt.Cfg.MinQty) {
+		return
+	}
+	side := types.Buy
+	if delta < 0 {
+		side = types.Sell
+	}
+	o := types.Order{Symbol: t.Symbol, Side: side, Qty: qty, Price: t.Price, Comment: "target_rebalance"}
+	if err := tt.Exec.Submit(o); err != nil {
+		tt.Log.Error("order_submit_failed",
+			logger.String("symbol", o.Symbol),
+			logger.String("side", string(o.Side)),
+			logger.Float64("qty", o.Qty),
+			logger.Err(err),
+		)
+		return
+	}
+	tt.Log.Info("order_submitted",
+		logger.String("symbol", o.Symbol),
+		logger.String("side", string(o.Side)),
+		logger.Float64("qty", o.Qty),
+		logger.Float64("price", o.Price),
+		logger.String("ctx", o.Comment),
+		logger.Float64("target", want),
+	)
+	metrics.OrdersSubmitted.WithLabelValues(o.Comment).Inc()
+}
@@ -0,0 +1,207 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/risk"
+	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
+)
+
+// upBars is the rally that makes TrendComposite go long.
+func upBars(n int) []candle {
+	var bars []candle
+	for i := 1; i <= n; i++ {
+		p := 100.0 + float64(i)
+		bars = append(bars, candle{p + 0.5, p - 0.5, p, 1000})
+	}
+	return bars
+}
+
+func buildTargetTrader(t *testing.T, sizing TargetSizing) (*TargetTrader, *testutils.MockExecutor) {
+	t.Helper()
+	exec := testutils.NewMockExecutor(10_000)
+	tt, err := NewTargetTrader(exec, buildConfig(), sizing, testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewTargetTrader failed: %v", err)
+	}
+	return tt, exec
+}
+
+func TestTargetModeEmitsInsteadOfTrading(t *testing.T) {
+	tc, exec := buildTrendComposite(t)
+	var targets []TargetPosition
+	if err := tc.SetTargetMode(TargetSinkFunc(func(tp TargetPosition) { targets = append(targets, tp) })); err != nil {
+		t.Fatalf("SetTargetMode failed: %v", err)
+	}
+
+	feedBars(t, tc, upBars(15))
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("a strategy in target mode must not trade, got %+v", exec.Orders())
+	}
+	if len(targets) != 15 {
+		t.Fatalf("expected one target per bar, got %d", len(targets))
+	}
+	last := targets[len(targets)-1]
+	if last.Intent != IntentLong || last.Price != 115 || last.Bar != 15 {
+		t.Fatalf("expected a long target after the rally, got %+v", last)
+	}
+	if last.Strength <= 0 || last.Strength > 1 || last.Strength != tc.Strength() {
+		t.Fatalf("expected the recorded strength %v, got %+v", tc.Strength(), last)
+	}
+	if targets[0].Intent != IntentFlat || targets[0].Score() != 0 {
+		t.Fatalf("expected a flat target before any signal, got %+v", targets[0])
+	}
+}
+
+func TestTargetModeIgnoresTheAccount(t *testing.T) {
+	// The same signal on an empty account emits the same targets.
+	var rich, broke []TargetPosition
+	for _, run := range []struct {
+		cash float64
+		out  *[]TargetPosition
+	}{{10_000, &rich}, {0, &broke}} {
+		tc, err := NewTrendComposite("TEST", buildConfig(), testutils.NewMockExecutor(run.cash), testutils.NewMockLogger())
+		if err != nil {
+			t.Fatalf("NewTrendComposite failed: %v", err)
+		}
+		out := run.out
+		if err := tc.SetTargetMode(TargetSinkFunc(func(tp TargetPosition) { *out = append(*out, tp) })); err != nil {
+			t.Fatalf("SetTargetMode failed: %v", err)
+		}
+		feedBars(t, tc, upBars(15))
+	}
+	if len(rich) != 15 || len(broke) != 15 || rich[14].Score() <= 0 {
+		t.Fatalf("expected 15 targets each ending long, got %+v and %+v", rich, broke)
+	}
+	for i := range rich {
+		if rich[i].Score() != broke[i].Score() {
+			t.Fatalf("bar %d: target %+v depends on the cash, %+v", i+1, broke[i], rich[i])
+		}
+	}
+}
+
+func TestTargetTraderReusesSignalUnderOtherSizing(t *testing.T) {
+	tt, real := buildTargetTrader(t, SizeByNotional(1_000))
+	tc, _ := buildTrendComposite(t)
+	if err := tc.SetTargetMode(tt); err != nil {
+		t.Fatalf("SetTargetMode failed: %v", err)
+	}
+
+	feedBars(t, tc, upBars(15))
+	orders := real.Orders()
+	if len(orders) != 1 || orders[0].Side != types.Buy || orders[0].Comment != "target_rebalance" {
+		t.Fatalf("expected a single rebalance buy, got %+v", orders)
+	}
+	if pos, _ := real.Position("TEST"); pos != risk.RoundQty(1_000/orders[0].Price, buildConfig()) {
+		t.Fatalf("position %v is not the 1000 notional", pos)
+	}
+	if tp, ok := tt.Target("TEST"); !ok || tp.Intent != IntentLong {
+		t.Fatalf("expected the latest target to be kept, got %+v", tp)
+	}
+}
+
+func TestTargetTraderRebalancesToTarget(t *testing.T) {
+	// A full‑strength target is the whole NAV of 10 000, 100 units at 100.
+	tt, real := buildTargetTrader(t, SizeByWeight(1))
+	steps := []struct {
+		intent   Intent
+		strength float64
+		want     float64
+	}{{IntentLong, 0.02, 2}, {IntentLong, 0.03, 3}, {IntentShort, 0.01, -1}, {IntentFlat, 0, 0}}
+	for _, s := range steps {
+		tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 100, Intent: s.intent, Strength: s.strength})
+		if pos, _ := real.Position("TEST"); pos != s.want {
+			t.Fatalf("position %v, want the %v target", pos, s.want)
+		}
+	}
+	var got []types.Order
+	for _, o := range real.Orders() {
+		got = append(got, types.Order{Side: o.Side, Qty: o.Qty})
+	}
+	want := []types.Order{{Side: types.Buy, Qty: 2}, {Side: types.Buy, Qty: 1}, {Side: types.Sell, Qty: 4}, {Side: types.Buy, Qty: 1}}
+	if len(got) != len(want) {
+		t.Fatalf("orders %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order %d: %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTargetTraderBandAndWeight(t *testing.T) {
+	tt, real := buildTargetTrader(t, SizeByWeight(0.2))
+	// A half‑strength target at a 0.2 weight of 10 000 at 100 is 10 units.
+	target := TargetPosition{Symbol: "TEST", Price: 100, Strength: 0.5, Intent: IntentLong}
+	tt.OnTarget(target)
+	if pos, _ := real.Position("TEST"); pos != 10 {
+		t.Fatalf("position %v, want 10", pos)
+	}
+	// The NAV is still 10 000 (9 000 cash plus 10 × 100), so the same
+	// target must not trade again, even without a band.
+	for range 3 {
+		tt.OnTarget(target)
+	}
+	if n := len(real.Orders()); n != 1 {
+		t.Fatalf("a repeated target must not trade, got %+v", real.Orders())
+	}
+
+	if err := tt.SetBand(0.1); err != nil {
+		t.Fatalf("SetBand failed: %v", err)
+	}
+	// At 95 the NAV is 9 950 and the wanted 10.47 units are inside the
+	// 10 % band.
+	tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 95, Strength: 0.5, Intent: IntentLong})
+	if n := len(real.Orders()); n != 1 {
+		t.Fatalf("a drift inside the band must not trade, got %+v", real.Orders())
+	}
+	tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 100, Strength: 0.25, Intent: IntentLong})
+	if pos, _ := real.Position("TEST"); pos != 5 {
+		t.Fatalf("position %v, want 5 after halving the strength", pos)
+	}
+}
+
+func TestTargetTraderSizerScalesWithStrength(t *testing.T) {
+	cfg := buildConfig()
+	sizer := risk.FixedFractional{Cfg: cfg}
+	tt, real := buildTargetTrader(t, SizeBySizer(sizer))
+	full := sizer.Qty(risk.SizeInput{Equity: 10_000, Price: 100})
+	tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 100, Strength: 0.5, Intent: IntentShort})
+	want := -risk.RoundQty(full/2, cfg)
+	if pos, _ := real.Position("TEST"); full <= 0 || pos != want {
+		t.Fatalf("position %v, want half of the sized %v short", pos, full)
+	}
+}
+
+func TestTargetTraderPositionMode(t *testing.T) {
+	exec := testutils.NewMockExecutor(10_000)
+	cfg := buildConfig()
+	cfg.PositionMode = config.PositionLongOnly
+	tt, err := NewTargetTrader(exec, cfg, SizeByNotional(200), testutils.NewMockLogger())
+	if err != nil {
+		t.Fatalf("NewTargetTrader failed: %v", err)
+	}
+	tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 100, Intent: IntentLong})
+	tt.OnTarget(TargetPosition{Symbol: "TEST", Price: 100, Intent: IntentShort})
+	if pos, _ := exec.Position("TEST"); pos != 0 {
+		t.Fatalf("a short target must only flatten a long‑only book, position %v", pos)
+	}
+}
+
+func TestTargetTraderValidation(t *testing.T) {
+	if _, err := NewTargetTrader(nil, buildConfig(), SizeByNotional(1), testutils.NewMockLogger()); err == nil {
+		t.Fatal("expected an error without an executor")
+	}
+	if _, err := NewTargetTrader(testutils.NewMockExecutor(1), buildConfig(), nil, testutils.NewMockLogger()); err == nil {
+		t.Fatal("expected an error without a sizing policy")
+	}
+	tt, _ := buildTargetTrader(t, SizeByNotional(1))
+	for _, band := range []float64{-0.1, 1, math.NaN()} {
+		if err := tt.SetBand(band); err == nil {
+			t.Fatalf("expected band %v to be rejected", band)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
 	"github.com/evdnx/gots/testutils"
+	"github.com/evdnx/gots/types"
 )
 
 // candle represents a single OHLCV bar that the tests feed to the strategy.
@@ -54,6 +55,10 @@ func buildConfig() config.StrategyConfig {
 		TakeProfitPct:   0.0, // enabled per‑test when needed
 		TrailingPct:     0.0, // enabled per‑test when needed
 
+		// The scenario tests exercise signal logic; the hard stop enforced by
+		// BaseStrategy is covered by its own tests.
+		StopType: config.StopNone,
+
 		// Quantity rounding / broker constraints.
 		QuantityPrecision: 2,
 		MinQty:            0.001,
@@ -251,3 +256,35 @@ func buildVolScaled(t *testing.T) (*VolScaledPos, *testutils.MockExecutor) {
 	s, exec := buildStrategy(t, ctor)
 	return s.(*VolScaledPos), exec
 }
+
+// checkTrailingExit drives an open long (entered at entry) to a new high and
+// then retraces it by more than the 2 % trailing distance.  The position
+// must survive the rally and be closed on the retracement.
+func checkTrailingExit(t *testing.T, strat interface {
+	ProcessBar(high, low, close, volume float64)
+}, exec *testutils.MockExecutor, entry float64) {
+	t.Helper()
+	peak := entry * 1.05
+	strat.ProcessBar(peak+0.5, peak-0.5, peak, 1200)
+	if n := len(exec.Orders()); n != 1 {
+		t.Fatalf("rally must not close a trailing position, got %d orders: %+v", n, exec.Orders())
+	}
+
+	retrace := peak * 0.97
+	strat.ProcessBar(peak, retrace-0.5, retrace, 1300)
+	if len(exec.Orders()) != 2 {
+		t.Fatalf("expected trailing‑stop close order, got %d (orders: %+v)", len(exec.Orders()), exec.Orders())
+	}
+	// A strategy's own exit signal may fire on the same bar; either way the
+	// long must be flat at or below the trailing level.
+	o := exec.Orders()[1]
+	if o.Side != types.Sell {
+		t.Fatalf("expected SELL to close trailing stop, got %+v", o)
+	}
+	if qty, _ := exec.Position("TEST"); qty != 0 {
+		t.Fatalf("expected flat position after trailing exit, got %f", qty)
+	}
+	if o.Price > (peak+0.5)*0.98 {
+		t.Fatalf("trailing exit %f above the trailing level %f", o.Price, (peak+0.5)*0.98)
+	}
+}
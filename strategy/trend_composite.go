@@ -3,7 +3,6 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
@@ -20,16 +19,7 @@ type TrendComposite struct {
 func NewTrendComposite(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*TrendComposite, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.RSIOverbought = 70
-		ic.RSIOversold = 30
-		ic.MFIOverbought = 80
-		ic.MFIOversold = 20
-		ic.VWAOStrongTrend = 70
-		ic.ATSEMAperiod = cfg.ATSEMAperiod
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -42,43 +32,38 @@ func NewTrendComposite(symbol string, cfg config.StrategyConfig,
 
 // ProcessBar evaluates the composite signal and manages the position.
 func (t *TrendComposite) ProcessBar(high, low, close, volume float64) {
+	defer t.flushDecision()
 	if err := t.Suite.Add(high, low, close, volume); err != nil {
 		t.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	t.recordPrice(close)
+	t.recordBar(high, low, close, volume)
+	if t.manageExits(high, low, close) {
+		return
+	}
 	if !t.hasHistory(15) {
 		return
 	}
 
 	// Pull the three core signals.
-	hBull := t.bullishFallback()
-	if ok, err := t.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := t.bearishFallback()
-	if ok, err := t.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
-	}
-	aBull := t.bullishFallback()
-	if ok, err := t.Suite.GetAMDO().IsBullishCrossover(); err == nil {
-		aBull = aBull || ok
-	}
-	aBear := t.bearishFallback()
-	if ok, err := t.Suite.GetAMDO().IsBearishCrossover(); err == nil {
-		aBear = aBear || ok
-	}
-	atBull := t.bullishFallback() || t.Suite.GetATSO().IsBullishCrossover()
-	atBear := t.bearishFallback() || t.Suite.GetATSO().IsBearishCrossover()
+	hBull := t.signal("hma_bull", t.Suite.GetHMA().IsBullishCrossover, t.bullishFallback)
+	hBear := t.signal("hma_bear", t.Suite.GetHMA().IsBearishCrossover, t.bearishFallback)
+	aBull := t.signal("amdo_bull", t.Suite.GetAMDO().IsBullishCrossover, t.bullishFallback)
+	aBear := t.signal("amdo_bear", t.Suite.GetAMDO().IsBearishCrossover, t.bearishFallback)
+	atBull := t.signal("atso_bull", alwaysReady(t.Suite.GetATSO().IsBullishCrossover), t.bullishFallback)
+	atBear := t.signal("atso_bear", alwaysReady(t.Suite.GetATSO().IsBearishCrossover), t.bearishFallback)
 
 	// Raw indicator values for momentum direction.
 	admoVal, err := t.Suite.GetAMDO().Calculate()
-	if err != nil {
+	if err != nil && t.fallbackAllowed(false) {
 		admoVal = t.prices.Slope()
 	}
 	atsoVal, err := t.Suite.GetATSO().Calculate()
 	if err != nil {
-		atsoVal = t.prices.Slope()
+		atsoVal = 0
+		if t.fallbackAllowed(false) {
+			atsoVal = t.prices.Slope()
+		}
 	} else {
 		atsoVal = t.sanitizeVolatility(math.Abs(atsoVal), close) * math.Copysign(1, atsoVal)
 	}
@@ -86,22 +71,24 @@ func (t *TrendComposite) ProcessBar(high, low, close, volume float64) {
 	longCond := hBull && aBull && atBull && admoVal > 0 && atsoVal > 0
 	shortCond := hBear && aBear && atBear && admoVal < 0 && atsoVal < 0
 
-	posQty, _ := t.Exec.Position(t.Symbol)
+	posQty, _ := t.position()
 
 	switch {
 	case longCond && posQty <= 0:
+		t.setIntent(IntentLong, t.conviction("hma_bull", "amdo_bull", "atso_bull"), close)
 		if posQty < 0 {
 			t.closePosition(close, "trendcomp_close_short")
 		}
 		t.openLong(close)
 
 	case shortCond && posQty >= 0:
+		t.setIntent(IntentShort, t.conviction("hma_bear", "amdo_bear", "atso_bear"), close)
 		if posQty > 0 {
 			t.closePosition(close, "trendcomp_close_long")
 		}
 		t.openShort(close)
 
-	case posQty != 0 && t.Cfg.TrailingPct > 0:
+	case posQty != 0 && t.trailingEnabled():
 		// Optional trailing‑stop logic.
 		t.applyTrailingStop(close)
 		if t.Cfg.TakeProfitPct > 0 {
@@ -116,6 +103,9 @@ func (t *TrendComposite) ProcessBar(high, low, close, volume float64) {
 
 // openLong creates a long order sized by the generic risk calculator.
 func (t *TrendComposite) openLong(price float64) {
+	if !t.canOpen(types.Buy) {
+		return
+	}
 	qty := t.calcQty(price)
 	if qty <= 0 {
 		return
@@ -133,6 +123,9 @@ func (t *TrendComposite) openLong(price float64) {
 
 // openShort creates a short order sized by the generic risk calculator.
 func (t *TrendComposite) openShort(price float64) {
+	if !t.canOpen(types.Sell) {
+		return
+	}
 	qty := t.calcQty(price)
 	if qty <= 0 {
 		return
@@ -150,10 +143,15 @@ func (t *TrendComposite) openShort(price float64) {
 
 // closePosition flattens the current position at market price.
 func (t *TrendComposite) closePosition(price float64, ctx string) {
-	qty, _ := t.Exec.Position(t.Symbol)
+	qty, avg := t.position()
 	if qty == 0 {
 		return
 	}
+	t.closeIntent(price)
+	if t.signalOnly {
+		t.lastDir = 0
+		return
+	}
 	side := types.Sell
 	if qty < 0 {
 		side = types.Buy
@@ -165,12 +163,14 @@ func (t *TrendComposite) closePosition(price float64, ctx string) {
 		Price:   price,
 		Comment: "TrendComposite exit",
 	}
-	_ = t.submitOrder(o, ctx)
+	if t.submitOrder(o, ctx) == nil {
+		t.recordTrade(qty, avg, price)
+	}
 	t.lastDir = 0
 }
 
 func (t *TrendComposite) manageTakeProfit(currentPrice float64) {
-	qty, avg := t.Exec.Position(t.Symbol)
+	qty, avg := t.position()
 	if qty == 0 {
 		return
 	}
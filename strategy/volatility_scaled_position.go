@@ -3,10 +3,10 @@ package strategy
 import (
 	"math"
 
-	"github.com/evdnx/goti"
 	"github.com/evdnx/gots/config"
 	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/risk"
 	"github.com/evdnx/gots/types"
 )
 
@@ -22,11 +22,7 @@ type VolScaledPos struct {
 func NewVolScaledPos(symbol string, cfg config.StrategyConfig,
 	exec executor.Executor, log logger.Logger) (*VolScaledPos, error) {
 
-	suiteFactory := func() (*goti.IndicatorSuite, error) {
-		ic := goti.DefaultConfig()
-		ic.ATSEMAperiod = cfg.ATSEMAperiod
-		return goti.NewIndicatorSuiteWithConfig(ic)
-	}
+	suiteFactory := suiteFactoryFor(cfg)
 	base, err := NewBaseStrategy(symbol, cfg, exec, suiteFactory, log)
 	if err != nil {
 		return nil, err
@@ -37,24 +33,22 @@ func NewVolScaledPos(symbol string, cfg config.StrategyConfig,
 // ProcessBar updates the suite, evaluates the HMA crossover, computes the
 // volatility‑scaled quantity and manages the position.
 func (v *VolScaledPos) ProcessBar(high, low, close, volume float64) {
+	defer v.flushDecision()
 	if err := v.Suite.Add(high, low, close, volume); err != nil {
 		v.Log.Warn("suite_add_error", logger.Err(err))
 		return
 	}
-	v.recordPrice(close)
+	v.recordBar(high, low, close, volume)
+	if v.manageExits(high, low, close) {
+		return
+	}
 	if !v.hasHistory(15) {
 		return
 	}
 
 	// 1️⃣ Entry signals.
-	hBull := v.bullishFallback()
-	if ok, err := v.Suite.GetHMA().IsBullishCrossover(); err == nil {
-		hBull = hBull || ok
-	}
-	hBear := v.bearishFallback()
-	if ok, err := v.Suite.GetHMA().IsBearishCrossover(); err == nil {
-		hBear = hBear || ok
-	}
+	hBull := v.signal("hma_bull", v.Suite.GetHMA().IsBullishCrossover, v.bullishFallback)
+	hBear := v.signal("hma_bear", v.Suite.GetHMA().IsBearishCrossover, v.bearishFallback)
 
 	// 2️⃣ Volatility metric (ATSO raw value).
 	atsoValRaw, err := v.Suite.GetATSO().Calculate()
@@ -71,35 +65,43 @@ func (v *VolScaledPos) ProcessBar(high, low, close, volume float64) {
 	}
 	atr = v.sanitizeVolatility(atr, close)
 
-	// 4️⃣ Position sizing – base risk scaled by volatility.
-	baseRisk := v.Exec.Equity() * v.Cfg.MaxRiskPerTrade / volFactor
-	stopDist := atr * v.Cfg.StopLossPct
-	if stopDist <= 0 {
-		stopDist = 0.0001
-	}
-	qty := baseRisk / stopDist
-	maxQty := v.Exec.Equity() / close
-	if maxQty > 0 && qty > maxQty {
-		qty = maxQty
+	// 4️⃣ Position sizing – base risk scaled by volatility, unless an
+	// explicit sizer has been selected in the config.
+	var qty float64
+	if v.Cfg.Sizing == "" {
+		baseRisk := v.Exec.Equity() * v.Cfg.MaxRiskPerTrade / volFactor
+		stopDist := atr * v.Cfg.StopLossPct
+		if stopDist <= 0 {
+			stopDist = 0.0001
+		}
+		qty = baseRisk / stopDist
+		maxQty := v.Exec.Equity() / close
+		if maxQty > 0 && qty > maxQty {
+			qty = maxQty
+		}
+		qty = risk.RoundQty(qty, v.Cfg)
+	} else {
+		qty = v.calcQty(close)
 	}
-	qty = math.Floor(qty*100) / 100 // 2‑dp rounding
 
-	posQty, _ := v.Exec.Position(v.Symbol)
+	posQty, _ := v.position()
 
 	switch {
 	case hBull && posQty <= 0:
+		v.setIntent(IntentLong, v.conviction("hma_bull"), close)
 		if posQty < 0 {
 			v.closePosition(close, "volscaled_close_short")
 		}
 		v.openLong(close, qty)
 
 	case hBear && posQty >= 0:
+		v.setIntent(IntentShort, v.conviction("hma_bear"), close)
 		if posQty > 0 {
 			v.closePosition(close, "volscaled_close_long")
 		}
 		v.openShort(close, qty)
 
-	case posQty != 0 && v.Cfg.TrailingPct > 0:
+	case posQty != 0 && v.trailingEnabled():
 		// Optional trailing‑stop.
 		v.applyTrailingStop(close)
 		if v.Cfg.TakeProfitPct > 0 {
@@ -114,6 +116,9 @@ func (v *VolScaledPos) ProcessBar(high, low, close, volume float64) {
 
 // openLong creates a long order with the pre‑computed quantity.
 func (v *VolScaledPos) openLong(price, qty float64) {
+	if !v.canOpen(types.Buy) {
+		return
+	}
 	if qty <= 0 {
 		return
 	}
@@ -129,6 +134,9 @@ func (v *VolScaledPos) openLong(price, qty float64) {
 
 // openShort creates a short order with the pre‑computed quantity.
 func (v *VolScaledPos) openShort(price, qty float64) {
+	if !v.canOpen(types.Sell) {
+		return
+	}
 	if qty <= 0 {
 		return
 	}
@@ -144,10 +152,14 @@ func (v *VolScaledPos) openShort(price, qty float64) {
 
 // closePosition flattens the current position at market price.
 func (v *VolScaledPos) closePosition(price float64, ctx string) {
-	qty, _ := v.Exec.Position(v.Symbol)
+	qty, avg := v.position()
 	if qty == 0 {
 		return
 	}
+	v.closeIntent(price)
+	if v.signalOnly {
+		return
+	}
 	side := types.Sell
 	if qty < 0 {
 		side = types.Buy
@@ -159,11 +171,13 @@ func (v *VolScaledPos) closePosition(price float64, ctx string) {
 		Price:   price,
 		Comment: "VolScaled exit",
 	}
-	_ = v.submitOrder(o, ctx)
+	if v.submitOrder(o, ctx) == nil {
+		v.recordTrade(qty, avg, price)
+	}
 }
 
 func (v *VolScaledPos) manageTakeProfit(currentPrice float64) {
-	qty, avg := v.Exec.Position(v.Symbol)
+	qty, avg := v.position()
 	if qty == 0 {
 		return
 	}
@@ -83,10 +83,9 @@ func TestVolScaled_ShortEntry(t *testing.T) {
 Test 3 – Trailing‑stop while a long position is open.
 -----------------------------------------------------------------------
 1️⃣ Open a long (upward ramp).
-2️⃣ Raise the price so that the trailing‑stop level
+2️⃣ Rally to a new high, then retrace more than TrailingPct from that
 
-	(`entry × (1+TrailingPct)`) is breached → a SELL order should close
-	the position.
+	high → a SELL order should close the position.
 */
 func TestVolScaled_TrailingStop(t *testing.T) {
 	vs, exec := buildVolScaled(t)
@@ -110,19 +109,7 @@ func TestVolScaled_TrailingStop(t *testing.T) {
 	}
 	entry := exec.Orders()[0].Price
 
-	// ---- Phase 2 – price crosses trailing level (entry * 1.02) ----
-	trail := entry * 1.02
-	vs.ProcessBar(trail+0.5, trail-0.5, trail+0.1, 1200)
-
-	if len(exec.Orders()) != 2 {
-		t.Fatalf("expected trailing‑stop close order, got %d (orders: %+v)", len(exec.Orders()), exec.Orders())
-	}
-	if exec.Orders()[1].Side != types.Sell {
-		t.Fatalf("expected SELL to close trailing stop, got %s", exec.Orders()[1].Side)
-	}
-	if exec.Orders()[1].Price < trail {
-		t.Fatalf("trailing‑stop price %f below expected %f", exec.Orders()[1].Price, trail)
-	}
+	checkTrailingExit(t, vs, exec, entry)
 }
 
 /*
@@ -0,0 +1,133 @@
+package strategy
+
+import (
+	"math"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/config"
+	"github.com/evdnx/gots/logger"
+	"github.com/evdnx/gots/types"
+)
+
+// Periods hard‑coded by goti.NewIndicatorSuiteWithConfig.
+const (
+	suiteRSIPeriod     = 5
+	suiteMFIPeriod     = 5
+	suiteVWAOPeriod    = 14
+	suiteHMAPeriod     = 9
+	suiteAMDOLength    = 20
+	suiteAMDOStdev     = 14
+	suiteATSOMaxPeriod = 14
+)
+
+// SuiteWarmupBars returns the number of bars a suite built from cfg needs
+// before every indicator has full windows and its crossover checks stop
+// reporting warm‑up:
+//
+//	RSI   period + 2 (two values for a crossover)
+//	MFI   period + 1
+//	VWAO  period + 2 (two values for a crossover)
+//	HMA   period + √period (two values for a crossover)
+//	AMDO  max(length, stdev length)
+//	ATSO  max adaptive period + ATSEMAperiod − 1 (EMA seeded)
+func SuiteWarmupBars(cfg config.StrategyConfig) int {
+	ema := goti.DefaultConfig().ATSEMAperiod
+	if ic, err := BuildIndicatorConfig(cfg); err == nil {
+		ema = ic.ATSEMAperiod
+	}
+	return max(
+		suiteRSIPeriod+2,
+		suiteMFIPeriod+1,
+		suiteVWAOPeriod+2,
+		suiteHMAPeriod+int(math.Sqrt(suiteHMAPeriod)),
+		max(suiteAMDOLength, suiteAMDOStdev),
+		suiteATSOMaxPeriod+ema-1,
+	)
+}
+
+// warmupBars is the WarmupBars of a strategy that waits for history bars
+// in its price buffer before evaluating signals.
+func (b *BaseStrategy) warmupBars(history int) int {
+	return max(SuiteWarmupBars(b.Cfg), history)
+}
+
+// Warmup feeds historical bars into the indicator suite, the price buffer
+// and the snapshot journal without evaluating signals, managing exits or
+// submitting orders.  Live runners call it with WarmupBars() bars before
+// the first ProcessBar.
+func (b *BaseStrategy) Warmup(bars []types.Bar) {
+	b.warmup(bars)
+}
+
+// warmup is Warmup for strategies that feed every bar to additional suites.
+func (b *BaseStrategy) warmup(bars []types.Bar, extra ...*goti.IndicatorSuite) {
+	for _, bar := range bars {
+		if err := b.Suite.Add(bar.High, bar.Low, bar.Close, bar.Volume); err != nil {
+			b.Log.Warn("warmup_suite_add_error", logger.Err(err))
+			continue
+		}
+		for _, s := range extra {
+			_ = s.Add(bar.High, bar.Low, bar.Close, bar.Volume)
+		}
+		b.journalBar(bar.High, bar.Low, bar.Close, bar.Volume)
+		b.recordPrice(bar.Close)
+	}
+	b.signals = b.signals[:0]
+}
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (a *AdaptiveBandMR) WarmupBars() int { return a.warmupBars(0) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (bm *BreakoutMomentum) WarmupBars() int { return bm.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (d *DivergenceSwing) WarmupBars() int { return d.warmupBars(12) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (e *EventDriven) WarmupBars() int { return e.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (h *HybridTrendMeanReversion) WarmupBars() int { return h.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (mr *MeanReversion) WarmupBars() int { return mr.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (m *MultiTF) WarmupBars() int { return m.warmupBars(15) }
+
+// Warmup feeds the bars to the base, fast and slow suites.
+func (m *MultiTF) Warmup(bars []types.Bar) { m.warmup(bars, m.fastSuite, m.slowSuite) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (t *TrendComposite) WarmupBars() int { return t.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs before the first live bar.
+func (v *VolScaledPos) WarmupBars() int { return v.warmupBars(15) }
+
+// WarmupBars returns the history Warmup needs per symbol: full indicator
+// windows plus Lookback returns for the allocation.
+func (rp *RiskParityRotation) WarmupBars() int {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	return max(SuiteWarmupBars(rp.cfg), rp.rotation.Lookback+1)
+}
+
+// Warmup feeds a symbol's historical bars into its suite and return window
+// without scoring or rebalancing.  Unknown symbols are ignored.
+func (rp *RiskParityRotation) Warmup(symbol string, bars []types.Bar) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	state, ok := rp.states[symbol]
+	if !ok {
+		return
+	}
+	for _, bar := range bars {
+		if err := state.add(bar.High, bar.Low, bar.Close, bar.Volume); err != nil {
+			rp.log.Warn("rp_warmup_suite_add_error",
+				logger.String("symbol", symbol),
+				logger.Err(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/evdnx/goti"
+	"github.com/evdnx/gots/types"
+)
+
+// toBars converts test candles into warm‑up bars.
+func toBars(candles []candle) []types.Bar {
+	bars := make([]types.Bar, len(candles))
+	for i, c := range candles {
+		bars[i] = types.Bar{High: c.high, Low: c.low, Close: c.close, Volume: c.volume}
+	}
+	return bars
+}
+
+// suiteReady reports whether every indicator of the suite has left warm‑up.
+func suiteReady(s *goti.IndicatorSuite) bool {
+	checks := []func() (bool, error){
+		s.GetRSI().IsBullishCrossover,
+		s.GetMFI().IsBullishCrossover,
+		s.GetVWAO().IsBullishCrossover,
+		s.GetHMA().IsBullishCrossover,
+		s.GetAMDO().IsBullishCrossover,
+	}
+	for _, c := range checks {
+		if _, err := c(); err != nil {
+			return false
+		}
+	}
+	_, err := s.GetATSO().Calculate()
+	return err == nil
+}
+
+func TestSuiteWarmupBars(t *testing.T) {
+	cfg := buildConfig()
+	if got := SuiteWarmupBars(cfg); got != 20 {
+		t.Fatalf("expected 20 warm‑up bars (AMDO length), got %d", got)
+	}
+	cfg.ATSEMAperiod = 14
+	if got := SuiteWarmupBars(cfg); got != 27 {
+		t.Fatalf("expected 27 warm‑up bars (ATSO 14 + EMA 14 − 1), got %d", got)
+	}
+
+	mr, _ := buildMeanReversion(t)
+	n := mr.WarmupBars()
+	bars := toBars(waveBars(n))
+	mr.Warmup(bars[:n-1])
+	if suiteReady(mr.Suite) {
+		t.Fatalf("suite ready after %d bars; WarmupBars %d is not tight", n-1, n)
+	}
+	mr.Warmup(bars[n-1:])
+	if !suiteReady(mr.Suite) {
+		t.Fatalf("suite not ready after WarmupBars (%d) bars", n)
+	}
+}
+
+func TestWarmupDoesNotTrade(t *testing.T) {
+	history := waveBars(80)
+
+	// The same history fed live does trade…
+	live, liveExec := buildMeanReversion(t)
+	feedBars(t, live, history)
+	if len(liveExec.Orders()) == 0 {
+		t.Fatal("scenario must trade when fed through ProcessBar")
+	}
+
+	// …but not when used to warm up.
+	mr, exec := buildMeanReversion(t)
+	mr.Warmup(toBars(history))
+	if n := len(exec.Orders()); n != 0 {
+		t.Fatalf("Warmup submitted %d orders", n)
+	}
+	if !mr.hasHistory(15) {
+		t.Fatal("Warmup must fill the price buffer")
+	}
+
+	// The first live bar evaluates signals on warm indicators.
+	next := waveBars(81)[80]
+	mr.ProcessBar(next.high, next.low, next.close, next.volume)
+	sigs := mr.Signals()
+	if len(sigs) == 0 {
+		t.Fatal("expected signals on the first live bar")
+	}
+	for _, s := range sigs {
+		if !s.Ready {
+			t.Fatalf("signal %s still warming up after Warmup", s.Name)
+		}
+	}
+}
+
+func TestMultiTFWarmupFeedsAllSuites(t *testing.T) {
+	m, exec := buildMultiTF(t, 60, 300)
+	m.Warmup(toBars(waveBars(m.WarmupBars())))
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("Warmup submitted orders: %+v", exec.Orders())
+	}
+	for name, s := range map[string]*goti.IndicatorSuite{"base": m.Suite, "fast": m.fastSuite, "slow": m.slowSuite} {
+		if !suiteReady(s) {
+			t.Fatalf("%s suite not warmed up", name)
+		}
+	}
+}
+
+func TestRiskParityWarmup(t *testing.T) {
+	symbols := []string{"AAA", "BBB"}
+	rp, exec := buildRiskParity(t, symbols, 1, 1)
+	n := rp.WarmupBars()
+	if n != 21 {
+		t.Fatalf("expected Lookback+1 = 21 warm‑up bars, got %d", n)
+	}
+	for _, sym := range symbols {
+		rp.Warmup(sym, toBars(waveBars(n)))
+	}
+	if len(exec.Orders()) != 0 {
+		t.Fatalf("Warmup must not rebalance, got orders %+v", exec.Orders())
+	}
+	for _, sym := range symbols {
+		st := rp.states[sym]
+		if got := len(st.Returns()); got != n-1 {
+			t.Fatalf("%s: expected %d returns after warm‑up, got %d", sym, n-1, got)
+		}
+		if !suiteReady(st.Suite()) {
+			t.Fatalf("%s: suite not warmed up", sym)
+		}
+	}
+}
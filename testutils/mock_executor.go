@@ -1,8 +1,10 @@
 package testutils
 
 import (
+	"errors"
 	"sync"
 
+	"github.com/evdnx/gots/executor"
 	"github.com/evdnx/gots/types"
 )
 
@@ -13,6 +15,7 @@ type MockExecutor struct {
 	positions map[string]float64 // qty (signed)
 	avgPrice  map[string]float64
 	orders    []types.Order // captured for assertions
+	book      executor.Book
 }
 
 // NewMockExecutor creates a fresh executor with the supplied starting equity.
@@ -31,28 +34,74 @@ func (m *MockExecutor) Submit(o types.Order) error {
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.fill(o)
+	return nil
+}
 
+func (m *MockExecutor) fill(o types.Order) bool {
 	cost := o.Price * o.Qty
 	if o.Side == types.Buy {
-		if cost > m.equity {
-			return nil // mimic “insufficient cash” – no panic
+		if cost > m.equity-m.book.Reserved() {
+			return false // mimic “insufficient cash” – no panic
 		}
 		m.equity -= cost
+		prev := m.positions[o.Symbol]
 		m.positions[o.Symbol] += o.Qty
-		prev := m.avgPrice[o.Symbol]
-		newAvg := (prev*(m.positions[o.Symbol]-o.Qty) + cost) / m.positions[o.Symbol]
-		m.avgPrice[o.Symbol] = newAvg
+		m.avgPrice[o.Symbol] = executor.NextAvgPrice(prev, m.avgPrice[o.Symbol], o.Qty, o.Price)
 	} else { // Sell / short
 		m.equity += cost
+		prev := m.positions[o.Symbol]
 		m.positions[o.Symbol] -= o.Qty
-		prev := m.avgPrice[o.Symbol]
-		newAvg := (prev*(m.positions[o.Symbol]+o.Qty) + cost) / m.positions[o.Symbol]
-		m.avgPrice[o.Symbol] = newAvg
+		m.avgPrice[o.Symbol] = executor.NextAvgPrice(prev, m.avgPrice[o.Symbol], -o.Qty, o.Price)
 	}
 	m.orders = append(m.orders, o)
+	return true
+}
+
+// PlaceLimit rests a limit order like PaperExecutor; fills show up in
+// Orders once MatchBar crosses it.
+func (m *MockExecutor) PlaceLimit(o types.Order) (string, error) {
+	if o.Qty <= 0 || o.Price <= 0 {
+		return "", errors.New("mock executor: limit order needs a positive qty and price")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if o.Side == types.Buy && o.Qty*o.Price > m.equity-m.book.Reserved() {
+		return "", errors.New("mock executor: insufficient cash")
+	}
+	return m.book.Add(o), nil
+}
+
+// Cancel removes a resting limit order.
+func (m *MockExecutor) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.book.Remove(id) {
+		return errors.New("mock executor: unknown order " + id)
+	}
 	return nil
 }
 
+// OpenOrders returns the resting limit orders of symbol.
+func (m *MockExecutor) OpenOrders(symbol string) []types.RestingOrder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.book.Open(symbol)
+}
+
+// MatchBar fills the resting orders a bar reaches, like PaperExecutor.
+func (m *MockExecutor) MatchBar(symbol string, high, low float64) []types.RestingOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var filled []types.RestingOrder
+	for _, o := range m.book.Cross(symbol, high, low) {
+		if m.fill(o.Order) {
+			filled = append(filled, o)
+		}
+	}
+	return filled
+}
+
 // Equity returns the current cash balance.
 func (m *MockExecutor) Equity() float64 {
 	m.mu.RLock()
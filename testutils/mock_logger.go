@@ -39,3 +39,13 @@ func (l *MockLogger) LastMessage() string {
 	}
 	return l.entries[len(l.entries)-1].msg
 }
+
+// HasMessage reports whether any recorded entry carries msg.
+func (l *MockLogger) HasMessage(msg string) bool {
+	for _, e := range l.entries {
+		if e.msg == msg {
+			return true
+		}
+	}
+	return false
+}
@@ -15,3 +15,18 @@ type Order struct {
 	// meta
 	Comment string
 }
+
+// Bar is a single OHLCV candle.
+type Bar struct {
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// RestingOrder is a limit order waiting in an executor's book.  Price is
+// the limit price.
+type RestingOrder struct {
+	ID string
+	Order
+}